@@ -0,0 +1,119 @@
+package strict
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// AdminClient manages API keys through the server's /admin endpoints.
+// Calls need a key with the "admin" scope. Get one from Client.Admin.
+//
+// New key secrets are only returned once, by CreateAPIKey and
+// RotateAPIKey. Debug logging writes response bodies, so pass "key" to
+// WithDebug's redacted fields when debugging admin calls.
+type AdminClient struct {
+	c *Client
+}
+
+// Admin returns the admin API, sharing the client's configuration.
+func (c *Client) Admin() *AdminClient { return &AdminClient{c: c} }
+
+// APIKeyInfo describes an API key. The secret itself is never listed.
+type APIKeyInfo struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Prefix is the first characters of the key, enough to recognise it
+	// in logs.
+	Prefix    string    `json:"prefix"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+	// ExpiresAt is when the key stops working, or nil if it doesn't
+	// expire.
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	Revoked    bool       `json:"revoked"`
+}
+
+// NewAPIKey is a newly created or rotated key, including its secret.
+type NewAPIKey struct {
+	APIKeyInfo
+	// Key is the secret to send as X-API-Key. Store it now: the server
+	// can't return it again.
+	Key string `json:"key"`
+}
+
+// CreateAPIKeyRequest describes a key to create.
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes,omitempty"`
+	// ExpiresAt is when the key stops working. Leave nil for a key that
+	// doesn't expire.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// CreateAPIKey creates a key with POST /admin/keys.
+func (a *AdminClient) CreateAPIKey(ctx context.Context, req CreateAPIKeyRequest, opts ...CallOption) (*NewAPIKey, error) {
+	var key NewAPIKey
+	cl, err := newJSONCall("CreateAPIKey", http.MethodPost, "/admin/keys", req, &key, newCallOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	if err := a.c.invoke(ctx, cl); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+type apiKeyList struct {
+	Keys []APIKeyInfo `json:"keys"`
+}
+
+// ListAPIKeys lists the account's keys, including revoked and expired
+// ones, with GET /admin/keys.
+func (a *AdminClient) ListAPIKeys(ctx context.Context, opts ...CallOption) ([]APIKeyInfo, error) {
+	var list apiKeyList
+	cl, err := newJSONCall("ListAPIKeys", http.MethodGet, "/admin/keys", nil, &list, newCallOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	if err := a.c.invoke(ctx, cl); err != nil {
+		return nil, err
+	}
+	return list.Keys, nil
+}
+
+type rotateAPIKeyRequest struct {
+	GracePeriodSeconds float64 `json:"grace_period_seconds,omitempty"`
+}
+
+// RotateAPIKey replaces key id with a new secret, keeping its name and
+// scopes. The old secret keeps working for gracePeriod so running
+// services can switch over; zero revokes it immediately.
+func (a *AdminClient) RotateAPIKey(ctx context.Context, id string, gracePeriod time.Duration, opts ...CallOption) (*NewAPIKey, error) {
+	var key NewAPIKey
+	body := rotateAPIKeyRequest{GracePeriodSeconds: gracePeriod.Seconds()}
+	cl, err := newJSONCall("RotateAPIKey", http.MethodPost, adminKeyPath(id)+"/rotate", body, &key, newCallOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	if err := a.c.invoke(ctx, cl); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// RevokeAPIKey revokes key id with DELETE /admin/keys/{id}. Revoking an
+// already revoked key succeeds.
+func (a *AdminClient) RevokeAPIKey(ctx context.Context, id string, opts ...CallOption) error {
+	cl, err := newJSONCall("RevokeAPIKey", http.MethodDelete, adminKeyPath(id), nil, nil, newCallOptions(opts))
+	if err != nil {
+		return err
+	}
+	return a.c.invoke(ctx, cl)
+}
+
+func adminKeyPath(id string) string {
+	return "/admin/keys/" + url.PathEscape(id)
+}
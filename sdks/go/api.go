@@ -0,0 +1,46 @@
+package strict
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// API is the set of Client methods that call the server, so code using
+// the SDK can accept an API and be tested with a fake such as
+// stricttest.FakeClient. *Client implements it.
+//
+// Methods are added to API as they are added to Client. Implementations
+// outside this package should embed an API, such as a nil one, so they
+// keep compiling, and fail only if a new method is actually called.
+// ListJobs and ProcessRequestStreamResult are left out: their results
+// can only be built by a Client.
+type API interface {
+	ProcessRequest(ctx context.Context, req ProcessingRequest, opts ...CallOption) (*OutputSchema, error)
+	ProcessRequestWithProgress(ctx context.Context, req ProcessingRequest, progress chan<- Progress, opts ...CallOption) (*OutputSchema, error)
+	ProcessBatch(ctx context.Context, reqs []ProcessingRequest, opts ...CallOption) (*BatchResult, error)
+	ValidateBatch(ctx context.Context, reqs []ProcessingRequest, opts ...CallOption) ([]ValidationResult, error)
+	ProcessStream(ctx context.Context, r io.Reader, opts ...CallOption) (*StreamOutput, error)
+	ProcessUpload(ctx context.Context, r io.ReaderAt, size int64, opts ...CallOption) (*StreamOutput, error)
+
+	SubmitJob(ctx context.Context, req ProcessingRequest, opts ...CallOption) (JobID, error)
+	GetJobStatus(ctx context.Context, id JobID, opts ...CallOption) (*JobStatus, error)
+	GetJobResult(ctx context.Context, id JobID, opts ...CallOption) (*OutputSchema, error)
+	CancelJob(ctx context.Context, id JobID, opts ...CallOption) (*JobStatus, error)
+	AwaitJob(ctx context.Context, id JobID, opts AwaitOptions) (*OutputSchema, error)
+
+	Health(ctx context.Context) (*HealthStatus, error)
+	Ready(ctx context.Context) (*HealthStatus, error)
+	ServerInfo(ctx context.Context, opts ...CallOption) (*ServerInfo, error)
+	ListProcessors(ctx context.Context, opts ...CallOption) ([]ProcessorInfo, error)
+	GetUsage(ctx context.Context, period UsagePeriod, opts ...CallOption) (*Usage, error)
+
+	CreateWebhook(ctx context.Context, req CreateWebhookRequest, opts ...CallOption) (*Webhook, error)
+	ListWebhooks(ctx context.Context, opts ...CallOption) ([]Webhook, error)
+	GetWebhook(ctx context.Context, id string, opts ...CallOption) (*Webhook, error)
+	UpdateWebhook(ctx context.Context, id string, req UpdateWebhookRequest, opts ...CallOption) (*Webhook, error)
+	DeleteWebhook(ctx context.Context, id string, opts ...CallOption) error
+	ReplayEvents(ctx context.Context, from, to time.Time, filter ReplayFilter, opts ...CallOption) (*Replay, error)
+}
+
+var _ API = (*Client)(nil)
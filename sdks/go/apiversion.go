@@ -0,0 +1,112 @@
+package strict
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DefaultAPIVersion is the API version this SDK is written against. It
+// is sent in the API-Version header unless WithAPIVersion picks another.
+const DefaultAPIVersion = "v1"
+
+// ErrUnsupportedVersion matches errors reporting that the server doesn't
+// serve the requested API version.
+var ErrUnsupportedVersion = errors.New("strict: unsupported API version")
+
+// errCodeUnsupportedVersion is the error code the server uses when it
+// rejects a request's API-Version.
+const errCodeUnsupportedVersion = "unsupported_api_version"
+
+// WithAPIVersion pins the API version sent in the API-Version header.
+func WithAPIVersion(v string) Option {
+	return func(c *Client) {
+		if v == "" {
+			c.setConfigErr(errors.New("strict: WithAPIVersion: empty version"))
+			return
+		}
+		c.apiVersion = v
+	}
+}
+
+// APIVersion returns the API version the client sends.
+func (c *Client) APIVersion() string {
+	return c.apiVersion
+}
+
+// WithVersionNegotiation makes the client call NegotiateVersion before
+// its first request, so a client pinned to a version the server has
+// retired fails fast with ErrUnsupportedVersion. A failed negotiation is
+// attempted again on the next call.
+func WithVersionNegotiation() Option {
+	return func(c *Client) {
+		c.negotiate = true
+	}
+}
+
+// UnsupportedVersionError reports an API version the server doesn't
+// serve. It matches ErrUnsupportedVersion.
+type UnsupportedVersionError struct {
+	Requested string
+	// Supported lists the versions the server does serve.
+	Supported []string
+}
+
+func (e *UnsupportedVersionError) Error() string {
+	return fmt.Sprintf("strict: server does not support API version %s (supported: %s)",
+		e.Requested, strings.Join(e.Supported, ", "))
+}
+
+// Is matches ErrUnsupportedVersion.
+func (e *UnsupportedVersionError) Is(target error) bool {
+	return target == ErrUnsupportedVersion
+}
+
+// versionList is the body of GET /versions.
+type versionList struct {
+	Versions []string `json:"versions"`
+	Default  string   `json:"default,omitempty"`
+}
+
+// NegotiateVersion checks with GET /versions that the server serves the
+// client's API version, returning *UnsupportedVersionError if it
+// doesn't. A server without the endpoint predates versioning and is
+// assumed compatible.
+func (c *Client) NegotiateVersion(ctx context.Context) error {
+	var list versionList
+	cl, err := newJSONCall("NegotiateVersion", http.MethodGet, "/versions", nil, &list, callOptions{})
+	if err != nil {
+		return err
+	}
+	if err := c.invoke(ctx, cl); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			c.negotiated.Store(true)
+			return nil
+		}
+		return err
+	}
+	for _, v := range list.Versions {
+		if v == c.apiVersion {
+			c.negotiated.Store(true)
+			return nil
+		}
+	}
+	return &UnsupportedVersionError{Requested: c.apiVersion, Supported: list.Versions}
+}
+
+// ensureVersion negotiates the API version before cl if
+// WithVersionNegotiation is set and it hasn't succeeded yet.
+func (c *Client) ensureVersion(ctx context.Context, cl *call) error {
+	if !c.negotiate || cl.op == "NegotiateVersion" || c.negotiated.Load() {
+		return nil
+	}
+	c.negotiateMu.Lock()
+	defer c.negotiateMu.Unlock()
+	if c.negotiated.Load() {
+		return nil
+	}
+	return c.NegotiateVersion(ctx)
+}
@@ -0,0 +1,65 @@
+package strict
+
+import (
+	"math/rand"
+	"sync/atomic"
+)
+
+// Balancer chooses which healthy endpoint an attempt goes to first. If
+// that endpoint fails, the remaining healthy endpoints are tried in
+// configured order. Pick is only called with two or more candidates and
+// must be safe for concurrent use.
+type Balancer interface {
+	// Pick returns the index in candidates of the preferred endpoint.
+	Pick(candidates []EndpointStatus) int
+}
+
+// BalancerFunc adapts a function to the Balancer interface.
+type BalancerFunc func(candidates []EndpointStatus) int
+
+func (f BalancerFunc) Pick(candidates []EndpointStatus) int { return f(candidates) }
+
+// RoundRobinBalancer cycles through healthy endpoints.
+func RoundRobinBalancer() Balancer {
+	var next atomic.Uint64
+	return BalancerFunc(func(candidates []EndpointStatus) int {
+		return int((next.Add(1) - 1) % uint64(len(candidates)))
+	})
+}
+
+// LeastLatencyBalancer prefers the endpoint with the lowest average
+// latency. Endpoints without a measurement yet are tried first so every
+// endpoint gets measured.
+func LeastLatencyBalancer() Balancer {
+	return BalancerFunc(func(candidates []EndpointStatus) int {
+		best := 0
+		for i, c := range candidates {
+			if c.Latency < candidates[best].Latency {
+				best = i
+			}
+		}
+		return best
+	})
+}
+
+// WeightedBalancer picks endpoints at random in proportion to their
+// Weight.
+func WeightedBalancer() Balancer {
+	return BalancerFunc(func(candidates []EndpointStatus) int {
+		total := 0
+		for _, c := range candidates {
+			total += c.Weight
+		}
+		if total <= 0 {
+			return 0
+		}
+		n := rand.Intn(total)
+		for i, c := range candidates {
+			if n < c.Weight {
+				return i
+			}
+			n -= c.Weight
+		}
+		return len(candidates) - 1
+	})
+}
@@ -0,0 +1,323 @@
+package strict
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+const defaultBatchConcurrency = 8
+
+// WithConcurrency bounds how many requests ProcessBatch has in flight
+// when it sends them individually. The default is 8.
+func WithConcurrency(n int) CallOption {
+	return func(co *callOptions) {
+		co.concurrency = n
+	}
+}
+
+// BatchItem is the outcome of one request in a batch: Output on success,
+// Err otherwise.
+type BatchItem struct {
+	// Index is the request's position in the batch.
+	Index   int
+	Request ProcessingRequest
+	Output  *OutputSchema
+	Err     error
+}
+
+// BatchResult holds the outcome of every request in a batch, in order.
+type BatchResult struct {
+	Items []BatchItem
+
+	client  *Client
+	opts    []CallOption
+	retries int
+}
+
+// Outputs returns each item's output, nil for failed items.
+func (r *BatchResult) Outputs() []*OutputSchema {
+	out := make([]*OutputSchema, len(r.Items))
+	for i, it := range r.Items {
+		out[i] = it.Output
+	}
+	return out
+}
+
+// FailedItems returns the items that failed.
+func (r *BatchResult) FailedItems() []BatchItem {
+	var failed []BatchItem
+	for _, it := range r.Items {
+		if it.Err != nil {
+			failed = append(failed, it)
+		}
+	}
+	return failed
+}
+
+// Err returns an error describing the failed items, or nil if every item
+// succeeded. It wraps the first failure.
+func (r *BatchResult) Err() error {
+	failed := r.FailedItems()
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("strict: %d of %d batch requests failed; request %d: %w",
+		len(failed), len(r.Items), failed[0].Index, failed[0].Err)
+}
+
+// RetryFailed processes the failed items again with the batch's original
+// call options, updating them in place. The returned error is set only
+// if the retry as a whole could not be sent; check Err or FailedItems
+// for items that failed again. A WithIdempotencyKey key gets a fresh
+// suffix for each retry, so the server doesn't replay the failures.
+func (r *BatchResult) RetryFailed(ctx context.Context) error {
+	var failed []BatchItem
+	for _, it := range r.Items {
+		if it.Err != nil {
+			it.Output, it.Err = nil, nil
+			failed = append(failed, it)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	if r.client == nil {
+		return errors.New("strict: RetryFailed: batch result was not returned by a Client")
+	}
+	r.retries++
+	opts := r.opts
+	if key := newCallOptions(opts).idempotencyKey; key != "" {
+		opts = append(opts[:len(opts):len(opts)], WithIdempotencyKey(key+"-retry"+strconv.Itoa(r.retries)))
+	}
+	if err := r.client.runBatch(ctx, failed, opts); err != nil {
+		return err
+	}
+	for _, it := range failed {
+		r.Items[it.Index] = it
+	}
+	return nil
+}
+
+type batchRequest struct {
+	Requests []ProcessingRequest `json:"requests"`
+}
+
+type batchResponse struct {
+	Results []batchItemResponse `json:"results"`
+}
+
+// batchItemResponse is one request's outcome. Failed items carry an
+// error body in any shape a failed response would have.
+type batchItemResponse struct {
+	StatusCode int             `json:"status_code,omitempty"`
+	Output     *OutputSchema   `json:"output,omitempty"`
+	Error      json.RawMessage `json:"error,omitempty"`
+}
+
+// ProcessBatch processes reqs and returns their outcomes in the same
+// order. It sends them together with POST /process/batch; if the server
+// has no batch endpoint, it remembers that and sends them as individual
+// ProcessRequest calls instead, up to WithConcurrency at a time.
+//
+// A failed request doesn't fail the batch: its BatchItem carries the
+// error, and RetryFailed can send it again. ProcessBatch itself returns
+// an error only if the batch call as a whole fails. With
+// WithRequestValidation, invalid requests fail locally and are not sent.
+//
+// WithCallTimeout bounds the whole batch. A WithIdempotencyKey key is
+// suffixed with each request's batch index when requests are sent
+// individually.
+func (c *Client) ProcessBatch(ctx context.Context, reqs []ProcessingRequest, opts ...CallOption) (*BatchResult, error) {
+	res := &BatchResult{
+		Items:  make([]BatchItem, len(reqs)),
+		client: c,
+		opts:   opts,
+	}
+	for i, req := range reqs {
+		res.Items[i] = BatchItem{Index: i, Request: req}
+	}
+	if err := c.runBatch(ctx, res.Items, opts); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// runBatch processes items, filling in their outcomes. Each item's
+// Request is updated to what was sent; items that fail local validation
+// are not sent.
+func (c *Client) runBatch(ctx context.Context, items []BatchItem, opts []CallOption) error {
+	co := newCallOptions(opts)
+	valid := make([]BatchItem, 0, len(items))
+	pos := make([]int, 0, len(items))
+	for i := range items {
+		if err := c.prepareRequest(ctx, &items[i].Request, co); err != nil {
+			items[i].Err = err
+			continue
+		}
+		valid = append(valid, items[i])
+		pos = append(pos, i)
+	}
+	if len(valid) == 0 {
+		return nil
+	}
+	if err := c.sendBatch(ctx, valid, opts, co); err != nil {
+		return err
+	}
+	for j, i := range pos {
+		items[i] = valid[j]
+	}
+	return nil
+}
+
+// sendBatch sends items to the batch endpoint, or individually if the
+// server has none.
+func (c *Client) sendBatch(ctx context.Context, items []BatchItem, opts []CallOption, co callOptions) error {
+	if co.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, co.timeout)
+		defer cancel()
+	}
+	if !c.batchUnsupported.Load() {
+		err := c.processBatch(ctx, items, co)
+		if !noBatchEndpoint(err) {
+			return err
+		}
+		c.batchUnsupported.Store(true)
+	}
+	c.fanOut(ctx, items, opts, co)
+	return nil
+}
+
+func (c *Client) processBatch(ctx context.Context, items []BatchItem, co callOptions) error {
+	in := batchRequest{Requests: make([]ProcessingRequest, len(items))}
+	for i, it := range items {
+		in.Requests[i] = it.Request
+		co.applyTo(&in.Requests[i])
+		sealed, err := c.sealRequest(ctx, &in.Requests[i])
+		if err != nil {
+			return err
+		}
+		in.Requests[i] = *sealed
+	}
+	var resp batchResponse
+	cl, err := newJSONCall("ProcessBatch", http.MethodPost, "/process/batch", &in, &resp, co)
+	if err != nil {
+		return err
+	}
+	if err := c.do(ctx, cl); err != nil {
+		return err
+	}
+	if len(resp.Results) != len(items) {
+		return fmt.Errorf("strict: batch of %d requests returned %d results", len(items), len(resp.Results))
+	}
+	for i, r := range resp.Results {
+		switch {
+		case r.StatusCode >= 400 || (len(r.Error) > 0 && string(r.Error) != "null"):
+			status := r.StatusCode
+			if status == 0 {
+				status = http.StatusInternalServerError
+			}
+			items[i].Err = parseAPIError(status, cl.serverRequestID, r.Error)
+		case r.Output == nil:
+			items[i].Err = errors.New("strict: batch result has neither output nor error")
+		default:
+			r.Output.RequestID = cl.serverRequestID
+			if err := c.openOutput(ctx, r.Output); err != nil {
+				items[i].Err = err
+				continue
+			}
+			items[i].Output = r.Output
+		}
+	}
+	return nil
+}
+
+type validateBatchResponse struct {
+	Results []ValidationResult `json:"results"`
+}
+
+// ValidateBatch checks reqs with POST /validate/batch without processing
+// them, and returns one ValidationResult per request, in order. It is
+// much cheaper than processing, so pipelines can use it to screen inputs
+// first. Invalid requests are reported in their results, not as an
+// error.
+func (c *Client) ValidateBatch(ctx context.Context, reqs []ProcessingRequest, opts ...CallOption) ([]ValidationResult, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+	co := newCallOptions(opts)
+	in := batchRequest{Requests: make([]ProcessingRequest, len(reqs))}
+	copy(in.Requests, reqs)
+	for i := range in.Requests {
+		co.applyTo(&in.Requests[i])
+		c.countTokens(&in.Requests[i])
+		sealed, err := c.sealRequest(ctx, &in.Requests[i])
+		if err != nil {
+			return nil, err
+		}
+		in.Requests[i] = *sealed
+	}
+	var resp validateBatchResponse
+	cl, err := newJSONCall("ValidateBatch", http.MethodPost, "/validate/batch", &in, &resp, co)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.invoke(ctx, cl); err != nil {
+		return nil, err
+	}
+	if len(resp.Results) != len(reqs) {
+		return nil, fmt.Errorf("strict: validation of %d requests returned %d results", len(reqs), len(resp.Results))
+	}
+	return resp.Results, nil
+}
+
+// noBatchEndpoint reports whether err means the server doesn't support
+// POST /process/batch.
+func noBatchEndpoint(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return true
+	}
+	return false
+}
+
+// fanOut sends items as individual calls. Items not yet sent when ctx is
+// done fail with its error.
+func (c *Client) fanOut(ctx context.Context, items []BatchItem, opts []CallOption, co callOptions) {
+	n := co.concurrency
+	if n <= 0 {
+		n = defaultBatchConcurrency
+	}
+	sem := make(chan struct{}, n)
+	var wg sync.WaitGroup
+	for i := range items {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if err := ctx.Err(); err != nil {
+			items[i].Err = &transportError{err: err}
+			continue
+		}
+		itemOpts := opts
+		if co.idempotencyKey != "" {
+			itemOpts = append(opts[:len(opts):len(opts)], WithIdempotencyKey(co.idempotencyKey+"-"+strconv.Itoa(items[i].Index)))
+		}
+		wg.Add(1)
+		go func(it *BatchItem, opts []CallOption) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			it.Output, it.Err = c.ProcessRequest(ctx, it.Request, opts...)
+		}(&items[i], itemOpts)
+	}
+	wg.Wait()
+}
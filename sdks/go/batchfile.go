@@ -0,0 +1,367 @@
+package strict
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BatchFormat is the format of a batch file.
+type BatchFormat string
+
+const (
+	// BatchCSV files have a header row naming their columns: input_data,
+	// which is required, and optionally id, processor_type, input_tokens,
+	// timeout_seconds and dry_run.
+	BatchCSV BatchFormat = "csv"
+	// BatchNDJSON files hold one JSON ProcessingRequest per line, with an
+	// optional "id" field. Blank lines are skipped.
+	BatchNDJSON BatchFormat = "ndjson"
+)
+
+// BatchFormatFor returns the format a file name's extension implies:
+// BatchCSV for .csv, BatchNDJSON for .ndjson, .jsonl and .json, and ""
+// otherwise.
+func BatchFormatFor(name string) BatchFormat {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return BatchCSV
+	case ".ndjson", ".jsonl", ".json":
+		return BatchNDJSON
+	}
+	return ""
+}
+
+// BatchRecord is one request read from a batch file.
+type BatchRecord struct {
+	// Line is the record's line number in the file, counting from 1.
+	Line int
+	// ID is the record's id column or field, if any, copied to its
+	// result so output can be joined back to the input.
+	ID      string
+	Request ProcessingRequest
+}
+
+// BatchLineError is a record that could not be parsed. The reader can
+// carry on past it.
+type BatchLineError struct {
+	Line int
+	Err  error
+}
+
+func (e *BatchLineError) Error() string {
+	return fmt.Sprintf("strict: batch line %d: %v", e.Line, e.Err)
+}
+
+func (e *BatchLineError) Unwrap() error { return e.Err }
+
+// BatchReader reads records from a batch file one at a time, so files
+// larger than memory can be processed.
+type BatchReader struct {
+	csv     *csv.Reader
+	columns []string
+
+	r    *bufio.Reader
+	line int
+}
+
+// batchColumns are the CSV columns BatchReader understands.
+var batchColumns = map[string]bool{
+	"id": true, "input_data": true, "processor_type": true,
+	"input_tokens": true, "timeout_seconds": true, "dry_run": true,
+}
+
+// NewBatchReader returns a reader for r in format. For CSV it reads the
+// header row, failing if it has no input_data column or one
+// BatchReader doesn't know.
+func NewBatchReader(r io.Reader, format BatchFormat) (*BatchReader, error) {
+	br := &BatchReader{}
+	switch format {
+	case BatchCSV:
+		br.csv = csv.NewReader(r)
+		br.csv.FieldsPerRecord = -1
+		header, err := br.csv.Read()
+		if err != nil {
+			return nil, fmt.Errorf("strict: read CSV header: %w", err)
+		}
+		hasInput := false
+		for i, col := range header {
+			col = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(col, "\ufeff")))
+			if !batchColumns[col] {
+				return nil, fmt.Errorf("strict: unknown CSV column %q", col)
+			}
+			hasInput = hasInput || col == "input_data"
+			header[i] = col
+		}
+		if !hasInput {
+			return nil, errors.New("strict: CSV header has no input_data column")
+		}
+		br.columns = header
+	case BatchNDJSON:
+		br.r = bufio.NewReader(r)
+	default:
+		return nil, fmt.Errorf("strict: unknown batch format %q", format)
+	}
+	return br, nil
+}
+
+// Read returns the next record, or io.EOF after the last. A record that
+// can't be parsed is returned as a *BatchLineError; other errors are
+// from the underlying reader and end the file.
+func (br *BatchReader) Read() (*BatchRecord, error) {
+	if br.csv != nil {
+		return br.readCSV()
+	}
+	return br.readNDJSON()
+}
+
+func (br *BatchReader) readCSV() (*BatchRecord, error) {
+	row, err := br.csv.Read()
+	if err != nil {
+		var pe *csv.ParseError
+		if errors.As(err, &pe) {
+			return nil, &BatchLineError{Line: pe.StartLine, Err: pe.Err}
+		}
+		return nil, err
+	}
+	line, _ := br.csv.FieldPos(0)
+	rec := &BatchRecord{Line: line}
+	if len(row) != len(br.columns) {
+		return nil, &BatchLineError{Line: line, Err: fmt.Errorf("%d fields, want %d", len(row), len(br.columns))}
+	}
+	for i, v := range row {
+		var err error
+		switch br.columns[i] {
+		case "id":
+			rec.ID = v
+		case "input_data":
+			rec.Request.InputData = v
+		case "processor_type":
+			rec.Request.ProcessorType = ProcessorType(strings.TrimSpace(v))
+		case "input_tokens":
+			if v = strings.TrimSpace(v); v != "" {
+				rec.Request.InputTokens, err = strconv.Atoi(v)
+			}
+		case "timeout_seconds":
+			if v = strings.TrimSpace(v); v != "" {
+				rec.Request.TimeoutSeconds, err = strconv.ParseFloat(v, 64)
+			}
+		case "dry_run":
+			if v = strings.TrimSpace(v); v != "" {
+				rec.Request.DryRun, err = strconv.ParseBool(v)
+			}
+		}
+		if err != nil {
+			return nil, &BatchLineError{Line: line, Err: fmt.Errorf("column %s: %w", br.columns[i], err)}
+		}
+	}
+	return rec, nil
+}
+
+func (br *BatchReader) readNDJSON() (*BatchRecord, error) {
+	for {
+		data, err := br.r.ReadBytes('\n')
+		if len(data) == 0 && err != nil {
+			return nil, err
+		}
+		br.line++
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+		var in struct {
+			ID json.RawMessage `json:"id"`
+			ProcessingRequest
+		}
+		if err := json.Unmarshal(data, &in); err != nil {
+			return nil, &BatchLineError{Line: br.line, Err: err}
+		}
+		rec := &BatchRecord{Line: br.line, Request: in.ProcessingRequest}
+		// Accept numeric IDs too, as exported by most databases.
+		if len(in.ID) > 0 && string(in.ID) != "null" {
+			if err := json.Unmarshal(in.ID, &rec.ID); err != nil {
+				rec.ID = string(in.ID)
+			}
+		}
+		return rec, nil
+	}
+}
+
+// BatchRunner processes the records of a batch file with bounded
+// concurrency, writing each success to a results stream and each failure
+// to a failure report:
+//
+//	f, _ := os.Open("requests.csv")
+//	src, err := strict.NewBatchReader(f, strict.BatchCSV)
+//	...
+//	runner := &strict.BatchRunner{Client: c, Concurrency: 16, Retry: strict.DefaultRetryPolicy()}
+//	stats, err := runner.Run(ctx, src, results, failures)
+//
+// Both outputs are NDJSON, written in completion order rather than input
+// order; each line carries the record's line number and ID. A result
+// line is {"line", "id", "output"}. A failure line repeats the record's
+// request fields alongside "error", "status_code" and "attempts", so the
+// failure report can be fed back to a BatchReader to retry just the
+// failures.
+type BatchRunner struct {
+	Client *Client
+	// Concurrency bounds the requests in flight. The default is 8.
+	Concurrency int
+	// Retry retries records that fail with a retryable error, on top of
+	// the client's own retry policy. The zero value makes one attempt.
+	Retry RetryPolicy
+	// CallOptions apply to every request.
+	CallOptions []CallOption
+	// Progress, if set, is called after each record with the running
+	// totals. Calls are serialized.
+	Progress func(BatchRunStats)
+}
+
+// BatchRunStats counts a run's records.
+type BatchRunStats struct {
+	Read      int
+	Succeeded int
+	Failed    int
+	Elapsed   time.Duration
+}
+
+type batchResultLine struct {
+	Line   int           `json:"line"`
+	ID     string        `json:"id,omitempty"`
+	Output *OutputSchema `json:"output"`
+}
+
+type batchFailureLine struct {
+	Line int    `json:"line"`
+	ID   string `json:"id,omitempty"`
+	*ProcessingRequest
+	Error      string `json:"error"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Attempts   int    `json:"attempts,omitempty"`
+}
+
+// Run processes every record from src. results and failures may be nil
+// to discard them. It returns an error only if src or an output fails,
+// or ctx is done; failed records are counted and reported, not
+// returned. Records in flight when ctx is done are reported as failures;
+// records not yet read are not reported at all.
+func (b *BatchRunner) Run(ctx context.Context, src *BatchReader, results, failures io.Writer) (BatchRunStats, error) {
+	if b.Client == nil {
+		return BatchRunStats{}, errors.New("strict: BatchRunner has no Client")
+	}
+	start := time.Now()
+	n := b.Concurrency
+	if n <= 0 {
+		n = defaultBatchConcurrency
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		stats    BatchRunStats
+		writeErr error
+		resEnc   = json.NewEncoder(discardIfNil(results))
+		failEnc  = json.NewEncoder(discardIfNil(failures))
+	)
+	finish := func(write func() error, ok bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if ok {
+			stats.Succeeded++
+		} else {
+			stats.Failed++
+		}
+		if err := write(); err != nil && writeErr == nil {
+			writeErr = err
+			cancel()
+		}
+		if b.Progress != nil {
+			s := stats
+			s.Elapsed = time.Since(start)
+			b.Progress(s)
+		}
+	}
+	fail := func(line int, id string, req *ProcessingRequest, err error, attempts int) {
+		fl := batchFailureLine{Line: line, ID: id, ProcessingRequest: req, Error: err.Error(), Attempts: attempts}
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			fl.StatusCode = apiErr.StatusCode
+		}
+		finish(func() error { return failEnc.Encode(fl) }, false)
+	}
+
+	records := make(chan *BatchRecord)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rec := range records {
+				out, attempts, err := processRetrying(ctx, b.Client, rec.Request, b.Retry, b.CallOptions)
+				if err != nil {
+					fail(rec.Line, rec.ID, &rec.Request, err, attempts)
+					continue
+				}
+				line := batchResultLine{Line: rec.Line, ID: rec.ID, Output: out}
+				finish(func() error { return resEnc.Encode(line) }, true)
+			}
+		}()
+	}
+
+	var readErr error
+read:
+	for {
+		rec, err := src.Read()
+		var lineErr *BatchLineError
+		switch {
+		case errors.As(err, &lineErr):
+			mu.Lock()
+			stats.Read++
+			mu.Unlock()
+			fail(lineErr.Line, "", nil, lineErr.Err, 0)
+			continue
+		case err == io.EOF:
+			break read
+		case err != nil:
+			readErr = fmt.Errorf("strict: read batch: %w", err)
+			break read
+		}
+		select {
+		case records <- rec:
+			mu.Lock()
+			stats.Read++
+			mu.Unlock()
+		case <-ctx.Done():
+			break read
+		}
+	}
+	close(records)
+	wg.Wait()
+
+	stats.Elapsed = time.Since(start)
+	switch {
+	case readErr != nil:
+		return stats, readErr
+	case writeErr != nil:
+		return stats, fmt.Errorf("strict: write batch output: %w", writeErr)
+	}
+	return stats, ctx.Err()
+}
+
+func discardIfNil(w io.Writer) io.Writer {
+	if w == nil {
+		return io.Discard
+	}
+	return w
+}
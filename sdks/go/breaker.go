@@ -0,0 +1,176 @@
+package strict
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting the server while the
+// client's circuit breaker is open.
+var ErrCircuitOpen = errors.New("strict: circuit breaker is open")
+
+// BreakerState is the state of a CircuitBreaker.
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerConfig configures a CircuitBreaker. Zero fields take defaults.
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens
+	// the breaker. Defaults to 5.
+	FailureThreshold int
+	// CoolDown is how long the breaker stays open before letting probe
+	// requests through. Defaults to 30s.
+	CoolDown time.Duration
+	// HalfOpenRequests is the number of probes allowed while half-open;
+	// that many consecutive successes close the breaker. Defaults to 1.
+	HalfOpenRequests int
+	// OnStateChange, if set, is called after every state transition. It
+	// runs without the breaker's lock held, so it may call State or make
+	// requests through the client.
+	OnStateChange func(from, to BreakerState)
+}
+
+// CircuitBreaker fails calls fast after repeated server or network
+// failures and probes for recovery once the cool-down has elapsed.
+type CircuitBreaker struct {
+	cfg BreakerConfig
+
+	mu        sync.Mutex
+	state     BreakerState
+	failures  int
+	successes int
+	inFlight  int
+	openedAt  time.Time
+}
+
+// NewCircuitBreaker returns a closed breaker.
+func NewCircuitBreaker(cfg BreakerConfig) *CircuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.CoolDown <= 0 {
+		cfg.CoolDown = 30 * time.Second
+	}
+	if cfg.HalfOpenRequests <= 0 {
+		cfg.HalfOpenRequests = 1
+	}
+	return &CircuitBreaker{cfg: cfg}
+}
+
+// State reports the current state, moving from open to half-open if the
+// cool-down has elapsed.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	change := b.advance(time.Now())
+	state := b.state
+	b.mu.Unlock()
+	b.notify(change)
+	return state
+}
+
+// allow reports whether a request may proceed. Every successful call must
+// be paired with a call to done.
+func (b *CircuitBreaker) allow() error {
+	b.mu.Lock()
+	change := b.advance(time.Now())
+	err := b.admit()
+	b.mu.Unlock()
+	b.notify(change)
+	return err
+}
+
+func (b *CircuitBreaker) admit() error {
+	switch b.state {
+	case BreakerOpen:
+		return ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.inFlight >= b.cfg.HalfOpenRequests {
+			return ErrCircuitOpen
+		}
+	}
+	b.inFlight++
+	return nil
+}
+
+// done records the outcome of a request admitted by allow.
+func (b *CircuitBreaker) done(failed bool) {
+	b.mu.Lock()
+	change := b.record(failed)
+	b.mu.Unlock()
+	b.notify(change)
+}
+
+// abandon releases a request admitted by allow without recording an
+// outcome, for one the client cut short itself.
+func (b *CircuitBreaker) abandon() {
+	b.mu.Lock()
+	b.inFlight--
+	b.mu.Unlock()
+}
+
+func (b *CircuitBreaker) record(failed bool) stateChange {
+	b.inFlight--
+	if failed {
+		b.successes = 0
+		b.failures++
+		if b.state == BreakerHalfOpen || b.failures >= b.cfg.FailureThreshold {
+			b.openedAt = time.Now()
+			return b.transition(BreakerOpen)
+		}
+		return stateChange{}
+	}
+	b.failures = 0
+	if b.state == BreakerHalfOpen {
+		b.successes++
+		if b.successes >= b.cfg.HalfOpenRequests {
+			b.successes = 0
+			return b.transition(BreakerClosed)
+		}
+	}
+	return stateChange{}
+}
+
+func (b *CircuitBreaker) advance(now time.Time) stateChange {
+	if b.state == BreakerOpen && now.Sub(b.openedAt) >= b.cfg.CoolDown {
+		b.successes = 0
+		return b.transition(BreakerHalfOpen)
+	}
+	return stateChange{}
+}
+
+// stateChange is a transition to report once b.mu is released. The zero
+// value, from closed to closed, reports nothing.
+type stateChange struct {
+	from, to BreakerState
+}
+
+func (b *CircuitBreaker) transition(to BreakerState) stateChange {
+	from := b.state
+	b.state = to
+	return stateChange{from, to}
+}
+
+func (b *CircuitBreaker) notify(c stateChange) {
+	if c.from != c.to && b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(c.from, c.to)
+	}
+}
@@ -0,0 +1,120 @@
+package strict
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// breakerStep is one event fed to a breaker: an admitted call that fails
+// or succeeds, one the client abandons, a call that should be refused, or
+// the cool-down elapsing.
+type breakerStep int
+
+const (
+	stepFail breakerStep = iota
+	stepSucceed
+	stepRefused
+	stepCoolDown
+	stepAbandon
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   BreakerConfig
+		steps []breakerStep
+		want  BreakerState
+	}{
+		{"closed after successes", BreakerConfig{}, []breakerStep{stepSucceed, stepSucceed}, BreakerClosed},
+		{"below threshold", BreakerConfig{FailureThreshold: 3}, []breakerStep{stepFail, stepFail}, BreakerClosed},
+		{"success resets failures", BreakerConfig{FailureThreshold: 2}, []breakerStep{stepFail, stepSucceed, stepFail}, BreakerClosed},
+		{"opens at threshold", BreakerConfig{FailureThreshold: 2}, []breakerStep{stepFail, stepFail, stepRefused}, BreakerOpen},
+		{"half-open after cool-down", BreakerConfig{FailureThreshold: 1}, []breakerStep{stepFail, stepCoolDown}, BreakerHalfOpen},
+		{"probe success closes", BreakerConfig{FailureThreshold: 1}, []breakerStep{stepFail, stepCoolDown, stepSucceed}, BreakerClosed},
+		{"probe failure reopens", BreakerConfig{FailureThreshold: 1}, []breakerStep{stepFail, stepCoolDown, stepFail, stepRefused}, BreakerOpen},
+		{"needs every probe", BreakerConfig{FailureThreshold: 1, HalfOpenRequests: 2}, []breakerStep{stepFail, stepCoolDown, stepSucceed}, BreakerHalfOpen},
+		{"all probes close", BreakerConfig{FailureThreshold: 1, HalfOpenRequests: 2}, []breakerStep{stepFail, stepCoolDown, stepSucceed, stepSucceed}, BreakerClosed},
+		{"half-open + cancelled probe stays half-open", BreakerConfig{FailureThreshold: 1}, []breakerStep{stepFail, stepCoolDown, stepAbandon}, BreakerHalfOpen},
+		{"cancelled probe frees its slot", BreakerConfig{FailureThreshold: 1}, []breakerStep{stepFail, stepCoolDown, stepAbandon, stepSucceed}, BreakerClosed},
+		{"cancelled call keeps failures", BreakerConfig{FailureThreshold: 2}, []breakerStep{stepFail, stepAbandon, stepFail, stepRefused}, BreakerOpen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewCircuitBreaker(tt.cfg)
+			for i, step := range tt.steps {
+				switch step {
+				case stepCoolDown:
+					b.mu.Lock()
+					b.openedAt = b.openedAt.Add(-b.cfg.CoolDown)
+					b.mu.Unlock()
+				case stepRefused:
+					if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+						t.Fatalf("step %d: allow() = %v, want ErrCircuitOpen", i, err)
+					}
+				case stepAbandon:
+					if err := b.allow(); err != nil {
+						t.Fatalf("step %d: allow() = %v", i, err)
+					}
+					b.abandon()
+				default:
+					if err := b.allow(); err != nil {
+						t.Fatalf("step %d: allow() = %v", i, err)
+					}
+					b.done(step == stepFail)
+				}
+			}
+			if got := b.State(); got != tt.want {
+				t.Errorf("State() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerHalfOpenLimit(t *testing.T) {
+	b := NewCircuitBreaker(BreakerConfig{FailureThreshold: 1, CoolDown: time.Millisecond})
+	b.allow()
+	b.done(true)
+	time.Sleep(2 * time.Millisecond)
+	if err := b.allow(); err != nil {
+		t.Fatalf("first probe: %v", err)
+	}
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second probe: allow() = %v, want ErrCircuitOpen", err)
+	}
+}
+
+func TestCircuitBreakerOnStateChange(t *testing.T) {
+	type change struct{ from, to BreakerState }
+	var (
+		b       *CircuitBreaker
+		changes []change
+	)
+	b = NewCircuitBreaker(BreakerConfig{
+		FailureThreshold: 1,
+		CoolDown:         time.Millisecond,
+		OnStateChange: func(from, to BreakerState) {
+			// Reading the state from the callback must not deadlock.
+			b.State()
+			changes = append(changes, change{from, to})
+		},
+	})
+	b.allow()
+	b.done(true)
+	time.Sleep(2 * time.Millisecond)
+	b.allow()
+	b.done(false)
+	want := []change{
+		{BreakerClosed, BreakerOpen},
+		{BreakerOpen, BreakerHalfOpen},
+		{BreakerHalfOpen, BreakerClosed},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("changes = %v, want %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("change %d = %v, want %v", i, changes[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package strict
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// CacheEntry is a cached response body and its expiry.
+type CacheEntry struct {
+	Value   []byte    `json:"value"`
+	Expires time.Time `json:"expires"`
+	// ETag is the server's entity tag for the response, if it sent one.
+	// Expired entries with an ETag are revalidated with If-None-Match.
+	ETag string `json:"etag,omitempty"`
+}
+
+// Cache stores serialized responses. Implementations decide eviction;
+// the client checks Expires itself, so a cache may return expired entries.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry)
+	Delete(key string)
+}
+
+// CacheConfig configures response caching.
+type CacheConfig struct {
+	// TTL is how long a response is served from cache. Defaults to five
+	// minutes.
+	TTL time.Duration
+	// MaxEntries bounds the default in-memory cache. Defaults to 1000.
+	MaxEntries int
+	// Backend replaces the default in-memory LRU cache, for example with
+	// a DiskCache.
+	Backend Cache
+	// StaleWhileRevalidate lets an expired entry be served for up to this
+	// long past its TTL while a background request refreshes it. Entries
+	// older than TTL plus this bound are never served. Zero disables it.
+	StaleWhileRevalidate time.Duration
+}
+
+type responseCache struct {
+	CacheConfig
+	// scope partitions the backend between clients sharing it with
+	// different credentials; see Clone.
+	scope string
+	// refreshing holds keys with a background refresh in flight.
+	refreshing sync.Map
+}
+
+// WithCache serves repeated identical ProcessRequest calls from a cache
+// keyed on a hash of the request. Only successful responses are cached.
+func WithCache(cfg CacheConfig) Option {
+	return func(c *Client) {
+		if cfg.TTL <= 0 {
+			cfg.TTL = 5 * time.Minute
+		}
+		if cfg.Backend == nil {
+			cfg.Backend = NewMemoryCache(cfg.MaxEntries)
+		}
+		c.cache = &responseCache{CacheConfig: cfg}
+	}
+}
+
+// cacheKey hashes the request as sent on the wire, and the cache scope
+// and tenant it is sent for, if any.
+func cacheKey(scope, tenant string, body []byte) string {
+	h := sha256.New()
+	if scope != "" {
+		h.Write([]byte(scope))
+		h.Write([]byte{1})
+	}
+	if tenant != "" {
+		h.Write([]byte(tenant))
+		h.Write([]byte{0})
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheLookup returns a cached response for key if one may be served.
+// revalidate reports that it is past its TTL, within the stale window, and
+// should be refreshed in the background. stale is the expired entry, if
+// any, for conditional revalidation.
+func (c *Client) cacheLookup(ctx context.Context, key string) (out *OutputSchema, stale *CacheEntry, revalidate bool) {
+	entry, ok := c.cache.Backend.Get(key)
+	if !ok {
+		return nil, nil, false
+	}
+	now := time.Now()
+	if now.After(entry.Expires) {
+		if now.After(entry.Expires.Add(c.cache.StaleWhileRevalidate)) {
+			if entry.ETag != "" {
+				return nil, &entry, false
+			}
+			return nil, nil, false
+		}
+		revalidate = true
+	}
+	out, ok = c.decodeCached(key, entry)
+	if !ok {
+		return nil, nil, false
+	}
+	if revalidate {
+		c.log(ctx, slog.LevelDebug, "strict: serving stale cache entry", slog.String("key", key))
+		return out, &entry, true
+	}
+	c.log(ctx, slog.LevelDebug, "strict: cache hit", slog.String("key", key))
+	return out, nil, false
+}
+
+// refreshInBackground repopulates an entry served stale. At most one
+// refresh per key runs at a time.
+func (c *Client) refreshInBackground(key string, req ProcessingRequest, co callOptions, data []byte, stale *CacheEntry) {
+	if _, busy := c.cache.refreshing.LoadOrStore(key, struct{}{}); busy {
+		return
+	}
+	go func() {
+		defer c.cache.refreshing.Delete(key)
+		ctx := context.Background()
+		if _, err := c.fetch(ctx, req, co, data, key, stale); err != nil {
+			c.log(ctx, slog.LevelWarn, "strict: background cache refresh failed",
+				slog.String("key", key), slog.String("error", err.Error()))
+		}
+	}()
+}
+
+func (c *Client) decodeCached(key string, entry CacheEntry) (*OutputSchema, bool) {
+	var out OutputSchema
+	if err := json.Unmarshal(entry.Value, &out); err != nil {
+		c.cache.Backend.Delete(key)
+		return nil, false
+	}
+	return &out, true
+}
+
+func (c *Client) cacheSet(key string, out *OutputSchema, etag string) {
+	value, err := json.Marshal(out)
+	if err != nil {
+		return
+	}
+	c.cache.Backend.Set(key, CacheEntry{Value: value, Expires: time.Now().Add(c.cache.TTL), ETag: etag})
+}
+
+// revalidated handles a 304 Not Modified by serving the stale entry and
+// renewing its expiry.
+func (c *Client) revalidated(ctx context.Context, key string, stale *CacheEntry, etag string) (*OutputSchema, error) {
+	out, ok := c.decodeCached(key, *stale)
+	if !ok {
+		return nil, errors.New("strict: server returned 304 but the cached response is unreadable")
+	}
+	if etag == "" {
+		etag = stale.ETag
+	}
+	stale.Expires = time.Now().Add(c.cache.TTL)
+	stale.ETag = etag
+	c.cache.Backend.Set(key, *stale)
+	c.log(ctx, slog.LevelDebug, "strict: cache revalidated", slog.String("key", key))
+	return out, nil
+}
+
+// MemoryCache is an in-memory LRU Cache.
+type MemoryCache struct {
+	max int
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type memoryItem struct {
+	key   string
+	entry CacheEntry
+}
+
+// NewMemoryCache returns an LRU cache holding at most maxEntries entries,
+// or 1000 if maxEntries is not positive.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = 1000
+	}
+	return &MemoryCache{max: maxEntries, ll: list.New(), items: make(map[string]*list.Element)}
+}
+
+func (m *MemoryCache) Get(key string) (CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	el, ok := m.items[key]
+	if !ok {
+		return CacheEntry{}, false
+	}
+	m.ll.MoveToFront(el)
+	return el.Value.(*memoryItem).entry, true
+}
+
+func (m *MemoryCache) Set(key string, entry CacheEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, ok := m.items[key]; ok {
+		el.Value.(*memoryItem).entry = entry
+		m.ll.MoveToFront(el)
+		return
+	}
+	m.items[key] = m.ll.PushFront(&memoryItem{key: key, entry: entry})
+	for m.ll.Len() > m.max {
+		oldest := m.ll.Back()
+		m.ll.Remove(oldest)
+		delete(m.items, oldest.Value.(*memoryItem).key)
+	}
+}
+
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, ok := m.items[key]; ok {
+		m.ll.Remove(el)
+		delete(m.items, key)
+	}
+}
+
+// Len returns the number of cached entries.
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ll.Len()
+}
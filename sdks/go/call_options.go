@@ -0,0 +1,93 @@
+package strict
+
+import (
+	"net/http"
+	"time"
+)
+
+// CallOption customizes a single API call, overriding client defaults.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	timeout        time.Duration
+	headers        http.Header
+	processor      ProcessorType
+	idempotencyKey string
+	uploadKey      string
+	concurrency    int
+	dryRun         bool
+	tenant         string
+	attempts       *attemptLog
+}
+
+func newCallOptions(opts []CallOption) callOptions {
+	co := callOptions{headers: make(http.Header)}
+	for _, opt := range opts {
+		opt(&co)
+	}
+	return co
+}
+
+// applyTo applies the options that override request fields.
+func (co callOptions) applyTo(req *ProcessingRequest) {
+	if co.processor != "" {
+		req.ProcessorType = co.processor
+	}
+	if co.dryRun {
+		req.DryRun = true
+	}
+}
+
+// WithCallTimeout bounds the whole call, including retries. It takes
+// precedence over ProcessingRequest.TimeoutSeconds.
+func WithCallTimeout(d time.Duration) CallOption {
+	return func(co *callOptions) {
+		co.timeout = d
+	}
+}
+
+// WithCallHeader sets a header on this call only, replacing any
+// client-level value for the same key.
+func WithCallHeader(key, value string) CallOption {
+	return func(co *callOptions) {
+		co.headers.Set(key, value)
+	}
+}
+
+// WithProcessorOverride sends the request to the given processor type
+// regardless of ProcessingRequest.ProcessorType.
+func WithProcessorOverride(p ProcessorType) CallOption {
+	return func(co *callOptions) {
+		co.processor = p
+	}
+}
+
+// WithIdempotencyKey sets the Idempotency-Key header so the server can
+// deduplicate retried calls. It overrides the key the client would
+// otherwise generate, which is useful when the caller itself retries a
+// call across processes.
+func WithIdempotencyKey(key string) CallOption {
+	return func(co *callOptions) {
+		co.idempotencyKey = key
+	}
+}
+
+// WithUploadKey names a ProcessUpload call so its progress can be saved
+// to the configured UploadStore and resumed later. Use a key that
+// identifies the input, such as its file path.
+func WithUploadKey(key string) CallOption {
+	return func(co *callOptions) {
+		co.uploadKey = key
+	}
+}
+
+// WithDryRun marks the request as a dry run: the server validates it and
+// picks a processor but doesn't run it. The returned OutputSchema has
+// DryRun set, the ValidationResult, and the processor that would have
+// been used. Setting ProcessingRequest.DryRun does the same for one
+// request.
+func WithDryRun() CallOption {
+	return func(co *callOptions) {
+		co.dryRun = true
+	}
+}
@@ -0,0 +1,84 @@
+package strict
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ErrorClass groups errors by how a caller should react to them.
+type ErrorClass int
+
+const (
+	// ErrorClassPermanent errors will fail again if retried unchanged.
+	ErrorClassPermanent ErrorClass = iota
+	// ErrorClassTransient errors are caused by network or server trouble
+	// and may succeed on retry.
+	ErrorClassTransient
+	// ErrorClassThrottled errors mean the caller exceeded its quota and
+	// should back off before retrying.
+	ErrorClassThrottled
+	// ErrorClassValidation errors mean the request itself was rejected
+	// and must be fixed before resubmitting.
+	ErrorClassValidation
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorClassPermanent:
+		return "permanent"
+	case ErrorClassTransient:
+		return "transient"
+	case ErrorClassThrottled:
+		return "throttled"
+	case ErrorClassValidation:
+		return "validation"
+	default:
+		return "unknown"
+	}
+}
+
+// Class classifies the error by its status code.
+func (e *APIError) Class() ErrorClass {
+	switch code := e.StatusCode; {
+	case code == http.StatusTooManyRequests:
+		return ErrorClassThrottled
+	case code == http.StatusBadRequest || code == http.StatusUnprocessableEntity:
+		return ErrorClassValidation
+	case code == http.StatusRequestTimeout,
+		code >= http.StatusInternalServerError && code != http.StatusNotImplemented && code != http.StatusHTTPVersionNotSupported:
+		return ErrorClassTransient
+	default:
+		return ErrorClassPermanent
+	}
+}
+
+// Classify returns the class of any error returned by the client. Errors
+// the client does not recognize are treated as permanent.
+func Classify(err error) ErrorClass {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Class()
+	}
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		return ErrorClassValidation
+	}
+	var te *transportError
+	if errors.As(err, &te) && !errors.Is(err, context.Canceled) {
+		return ErrorClassTransient
+	}
+	return ErrorClassPermanent
+}
+
+// IsRetryable reports whether retrying the failed call may succeed.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch Classify(err) {
+	case ErrorClassTransient, ErrorClassThrottled:
+		return true
+	}
+	return false
+}
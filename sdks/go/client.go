@@ -3,9 +3,16 @@ package strict
 import (
 	"bytes"
 	"context"
+	"crypto"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -20,16 +27,20 @@ const (
 type ProcessorType string
 
 const (
-	Cloud  ProcessorType = "cloud"
-	Local  ProcessorType = "local"
+	Cloud      ProcessorType = "cloud"
+	Local      ProcessorType = "local"
 	HybridProc ProcessorType = "hybrid"
 )
 
 type ProcessingRequest struct {
-	InputData      string        `json:"input_data"`
+	InputData string `json:"input_data"`
+	// InputTokens is counted with the client's Tokenizer if left zero.
 	InputTokens    int           `json:"input_tokens"`
 	ProcessorType  ProcessorType `json:"processor_type,omitempty"`
 	TimeoutSeconds float64       `json:"timeout_seconds,omitempty"`
+	// DryRun asks the server to validate and route the request without
+	// running the processor. See WithDryRun.
+	DryRun bool `json:"dry_run,omitempty"`
 }
 
 type ValidationResult struct {
@@ -40,67 +51,622 @@ type ValidationResult struct {
 }
 
 type OutputSchema struct {
-	Result            interface{}      `json:"result"`
-	Validation        ValidationResult `json:"validation"`
-	ProcessorUsed     ProcessorType    `json:"processor_used"`
-	ProcessingTimeMs  float64          `json:"processing_time_ms"`
-	RetriesAttempted  int              `json:"retries_attempted"`
+	// Result is the computation result. Decoded from JSON it is a
+	// json.RawMessage; read it with DecodeResult, RawResult or
+	// ResultValue.
+	Result           interface{}      `json:"result"`
+	Validation       ValidationResult `json:"validation"`
+	ProcessorUsed    ProcessorType    `json:"processor_used"`
+	ProcessingTimeMs float64          `json:"processing_time_ms"`
+	RetriesAttempted int              `json:"retries_attempted"`
+	// DryRun is set when the processor was not run: Result is empty and
+	// ProcessorUsed names the processor that would have been used.
+	DryRun bool `json:"dry_run,omitempty"`
+
+	// RequestID is the server's ID for the request, taken from the
+	// X-Request-ID response header.
+	RequestID string `json:"-"`
+	// Fallback is set when the requested processor failed and another
+	// produced this output. See WithFallback.
+	Fallback *FallbackInfo `json:"-"`
 }
 
+const defaultTimeout = 30 * time.Second
+
 type Client struct {
 	BaseURL    string
 	APIKey     string
 	httpClient *http.Client
+	timeout    time.Duration
+	headers    http.Header
+	retry      RetryPolicy
+	breaker    *CircuitBreaker
+	limiter    *RateLimiter
+	middleware []Middleware
+	hooks      []Hooks
+	compressor Compressor
+	debug      *debugLogger
+	logger     *slog.Logger
+	tracer     Tracer
+	stats      *clientStats
+	pool       *poolCounters
+	processor  ProcessorType
+
+	autoIdempotency   bool
+	userAgent         string
+	compressThreshold int
+	decompressors     map[string]Decompressor
+	codec             Codec
+	codecRejected     atomic.Bool
+	batchUnsupported  atomic.Bool
+	upload            UploadConfig
+	limits            *RequestLimits
+	tokenizer         Tokenizer
+	tokenizers        map[ProcessorType]Tokenizer
+	verifyHash        bool
+	strictDecoding    bool
+	apiVersion        string
+	negotiate         bool
+	negotiated        atomic.Bool
+	negotiateMu       sync.Mutex
+	onDeprecation     func(DeprecationNotice)
+	deprecations      sync.Map
+	quota             quotaTracker
+	credentials       CredentialsProvider
+	apiKey            atomic.Pointer[string]
+	tokens            *tokenCache
+	signer            *requestSigner
+	clockSkew         time.Duration
+	clockOffset       atomic.Int64
+	responseKey       crypto.PublicKey
+	envelope          KeyWrapper
+	redactor          Redactor
+	tenant            string
+	tenantLimiters    tenantLimiters
+	sandbox           bool
+	region            *Region
+	fallback          *FallbackPolicy
+	router            Router
+	catalog           *processorCatalog
+	deadLetters       DeadLetterStore
+
+	// httpTransport is the SDK-owned transport used when neither
+	// WithHTTPClient nor WithTransport is given.
+	httpTransport     *http.Transport
+	transport         http.RoundTripper
+	transportWrappers []TransportWrapper
+	unixSocket        string
+	h2PriorKnowledge  bool
+	http3             http.RoundTripper
+	dnsCache          *dnsCache
+
+	endpoints *endpointSet
+	cooldown  time.Duration
+	balancer  Balancer
+	hedge     *HedgeConfig
+	flights   *flightGroup
+	cache     *responseCache
+
+	// configErr records an invalid option. Every call fails with it, since
+	// NewClient cannot return an error.
+	configErr error
 }
 
-func NewClient(baseURL, apiKey string) *Client {
-	return &Client{
-		BaseURL: baseURL,
-		APIKey:  apiKey,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+// NewClient returns a Client for the strict API at baseURL. Options are
+// applied in order, so later options override earlier ones.
+func NewClient(baseURL, apiKey string, opts ...Option) *Client {
+	c := &Client{
+		BaseURL:       baseURL,
+		APIKey:        apiKey,
+		httpTransport: newDefaultTransport(),
+		timeout:       defaultTimeout,
+		headers:       make(http.Header),
+		retry:         RetryPolicy{MaxAttempts: 1},
+		stats:         new(clientStats),
+		pool:          new(poolCounters),
+
+		autoIdempotency: true,
+		decompressors:   defaultDecompressors(),
+		tokenizer:       WordTokenizer(),
+		apiVersion:      DefaultAPIVersion,
+		clockSkew:       defaultClockSkew,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.userAgent == "" {
+		c.userAgent = userAgent("")
+	}
+	if c.debug != nil {
+		c.debug.redactor = c.redactor
+	}
+	c.configureUnixSocket()
+	c.configureDNSCache()
+	c.countConns()
+	c.httpClient = c.buildHTTPClient()
+	return c
 }
 
-func (c *Client) ProcessRequest(ctx context.Context, req ProcessingRequest) (*OutputSchema, error) {
+func (c *Client) ProcessRequest(ctx context.Context, req ProcessingRequest, opts ...CallOption) (*OutputSchema, error) {
+	co := newCallOptions(opts)
+	if err := c.prepareRequest(ctx, &req, co); err != nil {
+		return nil, err
+	}
+	if c.deadLetters == nil || co.attempts != nil {
+		return c.processWithFallback(ctx, req, co)
+	}
+	co.attempts = new(attemptLog)
+	out, err := c.processWithFallback(ctx, req, co)
+	if err != nil {
+		c.deadLetter(ctx, req, co.attempts.history(err))
+	}
+	return out, err
+}
+
+func (c *Client) processWithFallback(ctx context.Context, req ProcessingRequest, co callOptions) (*OutputSchema, error) {
+	out, err := c.processOnce(ctx, req, co)
+	if err != nil && c.fallback != nil {
+		return c.processFallback(ctx, req, co, err)
+	}
+	return out, err
+}
+
+// processOnce processes a prepared request with its own processor type.
+func (c *Client) processOnce(ctx context.Context, req ProcessingRequest, co callOptions) (*OutputSchema, error) {
+	if c.flights != nil {
+		key, err := requestKey(&req, c.tenantOf(co))
+		if err != nil {
+			return nil, err
+		}
+		return c.flights.do(ctx, key, func() (*OutputSchema, error) {
+			return c.processRequest(ctx, req, co)
+		})
+	}
+	return c.processRequest(ctx, req, co)
+}
+
+func (c *Client) processRequest(ctx context.Context, req ProcessingRequest, co callOptions) (*OutputSchema, error) {
 	data, err := json.Marshal(req)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("strict: encode request: %w", err)
+	}
+	if c.cache == nil {
+		return c.fetch(ctx, req, co, data, "", nil)
+	}
+
+	key := cacheKey(c.cache.scope, c.tenantOf(co), data)
+	out, stale, revalidate := c.cacheLookup(ctx, key)
+	if out != nil {
+		if revalidate {
+			c.refreshInBackground(key, req, co, data, stale)
+		}
+		if err := c.openOutput(ctx, out); err != nil {
+			return nil, err
+		}
+		return out, nil
 	}
+	return c.fetch(ctx, req, co, data, key, stale)
+}
 
-	// Use request timeout if specified, otherwise rely on context
+// fetch sends a processing request to the server. If key is set the
+// response is cached; stale, if set, is revalidated with its ETag.
+func (c *Client) fetch(ctx context.Context, req ProcessingRequest, co callOptions, data []byte, key string, stale *CacheEntry) (*OutputSchema, error) {
 	requestCtx := ctx
-	if req.TimeoutSeconds > 0 {
+	if timeout := callTimeout(req, co); timeout > 0 {
 		var cancel context.CancelFunc
-		requestCtx, cancel = context.WithTimeout(ctx, time.Duration(req.TimeoutSeconds*float64(time.Second)))
+		requestCtx, cancel = context.WithTimeout(ctx, timeout)
 		defer cancel()
 	}
 
-	httpReq, err := http.NewRequestWithContext(requestCtx, "POST", c.BaseURL+"/process/request", bytes.NewBuffer(data))
+	in, err := c.sealRequest(requestCtx, &req)
 	if err != nil {
 		return nil, err
 	}
+	if in != &req {
+		if data, err = json.Marshal(in); err != nil {
+			return nil, fmt.Errorf("strict: encode request: %w", err)
+		}
+	}
+	var output OutputSchema
+	cl := &call{
+		op:      "ProcessRequest",
+		method:  http.MethodPost,
+		path:    "/process/request",
+		body:    data,
+		in:      in,
+		out:     &output,
+		opts:    co,
+		request: &req,
+	}
+	if stale != nil {
+		cl.ifNoneMatch = stale.ETag
+	}
+	if err := c.do(requestCtx, cl); err != nil {
+		return nil, err
+	}
+	if cl.notModified {
+		if stale == nil {
+			return nil, errors.New("strict: server returned 304 for an unconditional request")
+		}
+		out, err := c.revalidated(ctx, key, stale, cl.etag)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.openOutput(requestCtx, out); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+	output.RequestID = cl.serverRequestID
+	if err := c.verifyInputHash(ComputeInputHash(req), &output); err != nil {
+		return nil, err
+	}
+	// Cache the result as the server sent it, so an encrypted result
+	// stays encrypted at rest; hits decrypt it again.
+	if key != "" {
+		c.cacheSet(key, &output, cl.etag)
+	}
+	if err := c.openOutput(requestCtx, &output); err != nil {
+		return nil, err
+	}
+	if p := output.ProcessorUsed; p != "" && !p.Known() {
+		c.log(ctx, slog.LevelWarn, "strict: server used an unknown processor type",
+			slog.String("processor_type", string(p)),
+			slog.String("request_id", output.RequestID),
+		)
+	}
+	return &output, nil
+}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	if c.APIKey != "" {
-		httpReq.Header.Set("X-API-Key", c.APIKey)
+// callTimeout is the call or request timeout, if either is set;
+// otherwise the call relies on its context.
+func callTimeout(req ProcessingRequest, co callOptions) time.Duration {
+	if co.timeout > 0 {
+		return co.timeout
 	}
+	return time.Duration(req.TimeoutSeconds * float64(time.Second))
+}
+
+// call describes one logical API call, which may span several attempts.
+type call struct {
+	op     string
+	method string
+	path   string
+	body   []byte
+	out    interface{}
+	opts   callOptions
+	// stream, if set, is sent instead of body. It can be read only once,
+	// so the call gets a single attempt.
+	stream io.Reader
+	// once limits the call to a single attempt, as for probes that should
+	// report what they see rather than retry past it.
+	once bool
+	// keepBody hands a successful response's body and headers to the
+	// caller in respBody and respHeader instead of decoding it into out.
+	keepBody   bool
+	respBody   io.ReadCloser
+	respHeader http.Header
+	// in is the value body was marshaled from. Only calls that set it
+	// can be re-encoded with a non-JSON codec.
+	in          interface{}
+	contentType string
+	// request is the processing request being sent, if any.
+	request *ProcessingRequest
+
+	// wire is body as sent, after any compression.
+	wire            []byte
+	contentEncoding string
+
+	requestID       string
+	correlationID   string
+	serverRequestID string
+	// tenant is the tenant the call is made for, if any.
+	tenant string
+
+	// ifNoneMatch is sent as If-None-Match; etag and notModified record
+	// the server's answer.
+	ifNoneMatch string
+	etag        string
+	notModified bool
 
-	resp, err := c.httpClient.Do(httpReq)
+	attempt int
+	status  int
+}
+
+// newJSONCall builds a call whose body is in, marshaled as JSON, and
+// whose response is decoded into out. in may be nil for bodiless calls.
+func newJSONCall(op, method, path string, in, out interface{}, co callOptions) (*call, error) {
+	cl := &call{op: op, method: method, path: path, out: out, opts: co}
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return nil, fmt.Errorf("strict: encode request: %w", err)
+		}
+		cl.body = data
+		cl.in = in
+	}
+	return cl, nil
+}
+
+// invoke sends cl, bounded by its call timeout if one is set.
+func (c *Client) invoke(ctx context.Context, cl *call) error {
+	if cl.opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cl.opts.timeout)
+		defer cancel()
+	}
+	return c.do(ctx, cl)
+}
+
+// do sends cl, retrying according to the client's retry policy, and
+// decodes a successful response into cl.out.
+func (c *Client) do(ctx context.Context, cl *call) (err error) {
+	if c.configErr != nil {
+		return c.configErr
+	}
+	if err := c.ensureVersion(ctx, cl); err != nil {
+		return err
+	}
+	cl.requestID = newUUIDv7()
+	cl.correlationID = CorrelationIDFromContext(ctx)
+	cl.tenant = c.tenantOf(cl.opts)
+	start := time.Now()
+	c.stats.begin()
+	defer func() {
+		c.stats.end(cl, time.Since(start), err)
+	}()
+
+	if err := c.marshalBody(cl, c.requestCodec()); err != nil {
+		return err
+	}
+	c.encodeBody(cl)
+
+	// Generate the key once per logical call so every retry carries the
+	// same one and the server can deduplicate them.
+	if c.autoIdempotency && cl.method == http.MethodPost && cl.opts.idempotencyKey == "" {
+		cl.opts.idempotencyKey = newUUIDv7()
+	}
+
+	if c.tracer != nil {
+		var finish func(*CallResult)
+		ctx, finish = c.tracer.StartCall(ctx, &CallInfo{
+			Operation: cl.op,
+			Method:    cl.method,
+			Path:      cl.path,
+			Request:   cl.request,
+			Tenant:    cl.tenant,
+
+			RequestID:     cl.requestID,
+			CorrelationID: cl.correlationID,
+		})
+		defer func() {
+			finish(&CallResult{Attempts: cl.attempt, StatusCode: cl.status, Err: err})
+		}()
+	}
+	err = c.retryLoop(ctx, cl)
+	if unsupportedMediaType(cl, err) {
+		c.codecRejected.Store(true)
+		if cl.body, err = json.Marshal(cl.in); err != nil {
+			return fmt.Errorf("strict: encode request: %w", err)
+		}
+		cl.contentType = jsonContentType
+		cl.contentEncoding = ""
+		c.encodeBody(cl)
+		err = c.retryLoop(ctx, cl)
+	}
+	return err
+}
+
+func (c *Client) retryLoop(ctx context.Context, cl *call) error {
+	var lastErr error
+	for attempt := 0; attempt < c.retry.attempts(); attempt++ {
+		if attempt > 0 {
+			delay := c.retry.backoff(attempt)
+			var rle *RateLimitError
+			if errors.As(lastErr, &rle) && rle.RetryAfter > delay {
+				delay = rle.RetryAfter
+				// Don't sleep past the deadline only to fail anyway.
+				if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+					return lastErr
+				}
+			}
+			c.logRetry(ctx, attempt+1, delay, lastErr)
+			if err := sleepContext(ctx, delay); err != nil {
+				return lastErr
+			}
+		}
+
+		if limiter := c.limiterFor(cl.tenant); limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				if ctx.Err() != nil || errors.Is(err, context.DeadlineExceeded) {
+					return &transportError{err: err}
+				}
+				return err
+			}
+		}
+		if c.breaker != nil {
+			if err := c.breaker.allow(); err != nil {
+				return err
+			}
+		}
+		cl.attempt = attempt + 1
+		err := c.send(ctx, cl)
+		if c.breaker != nil {
+			// Throttling, or the caller giving up, says nothing about the
+			// server's health.
+			if ctx.Err() != nil || Classify(err) == ErrorClassThrottled {
+				c.breaker.abandon()
+			} else {
+				c.breaker.done(Classify(err) == ErrorClassTransient)
+			}
+		}
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		cl.opts.attempts.add(err)
+		if !IsRetryable(err) || ctx.Err() != nil || cl.singleAttempt() {
+			break
+		}
+	}
+	return lastErr
+}
+
+// singleAttempt reports whether cl must not be retried or failed over.
+func (cl *call) singleAttempt() bool {
+	return cl.stream != nil || cl.once
+}
+
+// attempt performs a single HTTP round trip.
+func (c *Client) attempt(ctx context.Context, cl *call, base string) (err error) {
+	// kept is set when the response body is handed over in cl.respBody,
+	// whose Close then releases the attempt's context.
+	var kept bool
+	cancel := context.CancelFunc(func() {})
+	if c.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+	}
+	defer func() {
+		if !kept {
+			cancel()
+		}
+	}()
+
+	var body io.Reader = bytes.NewReader(cl.wire)
+	if cl.stream != nil {
+		body = &countingReader{ReadCloser: io.NopCloser(cl.stream), n: &c.stats.bytesSent}
+	}
+	if err := c.CheckEndpoint(base); err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, cl.method, base+cl.path, body)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("strict: build request: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	for key, values := range c.headers {
+		httpReq.Header[key] = append([]string(nil), values...)
+	}
+	httpReq.Header.Set("Content-Type", cl.contentType)
+	if accept := cl.accept(); accept != "" {
+		httpReq.Header.Set("Accept", accept)
+	}
+	if cl.contentEncoding != "" {
+		httpReq.Header.Set("Content-Encoding", cl.contentEncoding)
+	}
+	httpReq.Header.Set("Accept-Encoding", c.acceptEncoding())
+	httpReq.Header.Set("User-Agent", c.userAgent)
+	httpReq.Header.Set("X-SDK-Version", Version)
+	httpReq.Header.Set("API-Version", c.apiVersion)
+	if err := c.SetAuthHeader(ctx, httpReq.Header); err != nil {
+		return err
+	}
+	if cl.opts.idempotencyKey != "" {
+		httpReq.Header.Set("Idempotency-Key", cl.opts.idempotencyKey)
+	}
+	httpReq.Header.Set("X-Request-ID", cl.requestID)
+	if cl.ifNoneMatch != "" {
+		httpReq.Header.Set("If-None-Match", cl.ifNoneMatch)
+	}
+	if cl.correlationID != "" {
+		httpReq.Header.Set("X-Correlation-ID", cl.correlationID)
+	}
+	if cl.tenant != "" {
+		httpReq.Header.Set("X-Tenant-ID", cl.tenant)
+	}
+	if c.sandbox {
+		httpReq.Header.Set(SandboxHeader, "true")
+	}
+	for key, values := range cl.opts.headers {
+		httpReq.Header[key] = append([]string(nil), values...)
+	}
+	if c.signer != nil {
+		if err := c.signer.sign(httpReq, cl.wire, cl.stream != nil, c.signingTime()); err != nil {
+			return err
+		}
 	}
 
-	var output OutputSchema
-	if err := json.NewDecoder(resp.Body).Decode(&output); err != nil {
-		return nil, err
+	reqInfo := &RequestInfo{
+		Operation: cl.op,
+		Method:    httpReq.Method,
+		URL:       httpReq.URL.String(),
+		Header:    httpReq.Header,
+		Body:      cl.body,
+		Attempt:   cl.attempt,
+
+		RequestID:     cl.requestID,
+		CorrelationID: cl.correlationID,
+		Tenant:        cl.tenant,
 	}
+	c.logRequestStart(ctx, reqInfo)
+	c.fireRequest(ctx, reqInfo)
+	respInfo := &ResponseInfo{Request: reqInfo}
+	start := time.Now()
+	defer func() {
+		respInfo.Latency = time.Since(start)
+		respInfo.Err = err
+		c.logRequestEnd(ctx, respInfo)
+		c.fireResponse(ctx, respInfo)
+	}()
 
-	return &output, nil
+	c.pool.inUse.Add(1)
+	defer c.pool.inUse.Add(-1)
+	resp, err := c.handler()(httpReq)
+	if err != nil {
+		return &transportError{err: err}
+	}
+	c.stats.bytesSent.Add(int64(len(cl.wire)))
+	resp.Body = &countingReader{ReadCloser: resp.Body, n: &c.stats.bytesReceived}
+	defer func() {
+		if !kept {
+			resp.Body.Close()
+		}
+	}()
+	if err := c.decodeBody(resp); err != nil {
+		return err
+	}
+	respInfo.StatusCode = resp.StatusCode
+	cl.status = resp.StatusCode
+	respInfo.Header = resp.Header
+	// Servers that don't assign their own ID echo ours, or nothing.
+	cl.serverRequestID = resp.Header.Get("X-Request-ID")
+	if cl.serverRequestID == "" {
+		cl.serverRequestID = cl.requestID
+	}
+	respInfo.ServerRequestID = cl.serverRequestID
+	c.noteDeprecation(ctx, cl, resp.Header)
+	c.quota.update(resp.Header, time.Now())
+	c.observeClock(resp.Header, time.Now())
+
+	cl.etag = resp.Header.Get("ETag")
+	if resp.StatusCode == http.StatusNotModified && cl.ifNoneMatch != "" {
+		cl.notModified = true
+		return nil
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		rle := newRateLimitError(resp)
+		rle.fillRequestID(cl.serverRequestID)
+		return rle
+	}
+	if resp.StatusCode == http.StatusUnauthorized && c.tokens != nil {
+		c.tokens.invalidate(strings.TrimPrefix(httpReq.Header.Get("Authorization"), "Bearer "))
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := newAPIError(resp)
+		apiErr.fillRequestID(cl.serverRequestID)
+		return apiErr
+	}
+
+	if c.responseKey != nil {
+		if err := c.verifyResponse(resp, cl); err != nil {
+			return err
+		}
+	}
+	if cl.keepBody {
+		kept = true
+		cl.respBody = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+		cl.respHeader = resp.Header
+		return nil
+	}
+	return c.decodeResponse(resp, cl)
 }
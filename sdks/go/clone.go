@@ -0,0 +1,161 @@
+package strict
+
+// Clone returns a new client derived from c, with opts applied on top of
+// c's configuration. It is cheap: the clone shares c's connections,
+// endpoint health, circuit breaker, rate limiter, response cache,
+// in-flight deduplication, token cache and statistics, so it suits
+// per-tenant or per-job specialization such as
+//
+//	jobClient := client.Clone(strict.WithTimeout(5*time.Minute), strict.WithDefaultProcessor(strict.Local))
+//
+// Options that configure connections (WithHTTPClient, WithTransport,
+// WithProxy, WithCACertFile, WithUnixSocket and the like) have no effect
+// on a clone; build a new client with NewClient for those. A clone given
+// its own API key, credentials or token source acts for another account,
+// so it gets its own response cache partition, in-flight deduplication
+// and token cache instead of sharing c's. A clone with its own API
+// version or base URLs negotiates the version again (see
+// WithVersionNegotiation).
+func (c *Client) Clone(opts ...Option) *Client {
+	n := &Client{
+		BaseURL:    c.BaseURL,
+		APIKey:     c.APIKey,
+		httpClient: c.httpClient,
+		timeout:    c.timeout,
+		headers:    c.headers.Clone(),
+		retry:      c.retry,
+		breaker:    c.breaker,
+		limiter:    c.limiter,
+		middleware: append([]Middleware(nil), c.middleware...),
+		hooks:      append([]Hooks(nil), c.hooks...),
+		compressor: c.compressor,
+		logger:     c.logger,
+		tracer:     c.tracer,
+		stats:      c.stats,
+		pool:       c.pool,
+		processor:  c.processor,
+
+		autoIdempotency:   c.autoIdempotency,
+		userAgent:         c.userAgent,
+		compressThreshold: c.compressThreshold,
+		decompressors:     make(map[string]Decompressor, len(c.decompressors)),
+		codec:             c.codec,
+		upload:            c.upload,
+		limits:            c.limits,
+		tokenizer:         c.tokenizer,
+		verifyHash:        c.verifyHash,
+		strictDecoding:    c.strictDecoding,
+		apiVersion:        c.apiVersion,
+		negotiate:         c.negotiate,
+		onDeprecation:     c.onDeprecation,
+		credentials:       c.credentials,
+		tokens:            c.tokens,
+		signer:            c.signer,
+		clockSkew:         c.clockSkew,
+		responseKey:       c.responseKey,
+		envelope:          c.envelope,
+		redactor:          c.redactor,
+		tenant:            c.tenant,
+		sandbox:           c.sandbox,
+		region:            c.region,
+		fallback:          c.fallback,
+		router:            c.router,
+		catalog:           c.catalog,
+		deadLetters:       c.deadLetters,
+
+		httpTransport:     c.httpTransport,
+		transport:         c.transport,
+		transportWrappers: c.transportWrappers,
+		unixSocket:        c.unixSocket,
+		h2PriorKnowledge:  c.h2PriorKnowledge,
+		http3:             c.http3,
+		dnsCache:          c.dnsCache,
+
+		endpoints: c.endpoints,
+		cooldown:  c.cooldown,
+		balancer:  c.balancer,
+		hedge:     c.hedge,
+		flights:   c.flights,
+		cache:     c.cache,
+
+		configErr: c.configErr,
+	}
+	for k, d := range c.decompressors {
+		n.decompressors[k] = d
+	}
+	if c.tokenizers != nil {
+		n.tokenizers = make(map[ProcessorType]Tokenizer, len(c.tokenizers))
+		for p, t := range c.tokenizers {
+			n.tokenizers[p] = t
+		}
+	}
+	if c.debug != nil {
+		n.debug = &debugLogger{w: c.debug.w, fields: c.debug.fields}
+	}
+	n.codecRejected.Store(c.codecRejected.Load())
+	n.batchUnsupported.Store(c.batchUnsupported.Load())
+	n.apiKey.Store(c.apiKey.Load())
+	n.clockOffset.Store(c.clockOffset.Load())
+	n.tenantLimiters.cfg = c.tenantLimiters.cfg
+	c.quota.mu.Lock()
+	n.quota.q = c.quota.q
+	c.quota.mu.Unlock()
+
+	// Options that touch the transport get a scratch copy, so they can't
+	// reconfigure the connections shared with c, and the connection
+	// settings are put back afterwards. Credentials start empty, to show
+	// whether an option replaced them.
+	n.httpTransport = c.httpTransport.Clone()
+	n.APIKey, n.credentials, n.tokens = "", nil, nil
+	for _, opt := range opts {
+		opt(n)
+	}
+	ownCredentials := n.APIKey != "" || n.credentials != nil || n.tokens != nil
+	if n.APIKey == "" {
+		n.APIKey = c.APIKey
+	} else {
+		// A key set with SetAPIKey on c would take precedence.
+		n.apiKey.Store(nil)
+	}
+	if n.credentials == nil {
+		n.credentials = c.credentials
+	}
+	if n.tokens == nil {
+		n.tokens = c.tokens
+	}
+	if ownCredentials {
+		n.isolate(c)
+	}
+	// A version negotiated by c holds only for the same version on the
+	// same servers.
+	if n.apiVersion == c.apiVersion && n.BaseURL == c.BaseURL && n.endpoints == c.endpoints {
+		n.negotiated.Store(c.negotiated.Load())
+	}
+	n.httpClient = c.httpClient
+	n.httpTransport = c.httpTransport
+	n.transport = c.transport
+	n.transportWrappers = c.transportWrappers
+	n.unixSocket = c.unixSocket
+	n.h2PriorKnowledge = c.h2PriorKnowledge
+	n.http3 = c.http3
+	n.dnsCache = c.dnsCache
+	if n.debug != nil {
+		n.debug.redactor = n.redactor
+	}
+	return n
+}
+
+// isolate gives c, a clone of parent acting with other credentials, its
+// own state wherever parent's would mix results or tokens between
+// accounts.
+func (c *Client) isolate(parent *Client) {
+	if c.cache != nil && c.cache == parent.cache {
+		c.cache = &responseCache{CacheConfig: parent.cache.CacheConfig, scope: newUUIDv7()}
+	}
+	if c.flights != nil && c.flights == parent.flights {
+		c.flights = &flightGroup{m: make(map[string]*flight)}
+	}
+	if c.tokens != nil && c.tokens == parent.tokens {
+		c.tokens = &tokenCache{src: parent.tokens.src}
+	}
+}
@@ -0,0 +1,103 @@
+package strict
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCloneCredentialIsolation(t *testing.T) {
+	childKey := CredentialsFunc(func(context.Context) (string, error) { return "child", nil })
+	childToken := TokenSourceFunc(func(context.Context) (Token, error) { return Token{AccessToken: "child"}, nil })
+	tests := []struct {
+		name       string
+		opts       []Option
+		wantShared bool
+	}{
+		{"no options", nil, true},
+		{"timeout", []Option{WithTimeout(time.Minute)}, true},
+		{"api key", []Option{withAPIKey("child")}, false},
+		{"credentials", []Option{WithCredentials(childKey)}, false},
+		{"token source", []Option{WithTokenSource(childToken)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.Write([]byte(`{"result":1,"processor_used":"local"}`))
+			}))
+			defer srv.Close()
+			parent := NewClient(srv.URL, "parent", WithCache(CacheConfig{}), WithRequestDeduplication())
+			child := parent.Clone(tt.opts...)
+
+			if shared := child.cache == parent.cache; shared != tt.wantShared {
+				t.Errorf("cache shared = %v, want %v", shared, tt.wantShared)
+			}
+			if shared := child.flights == parent.flights; shared != tt.wantShared {
+				t.Errorf("flights shared = %v, want %v", shared, tt.wantShared)
+			}
+			req := ProcessingRequest{InputData: "x", InputTokens: 1}
+			ctx := context.Background()
+			if _, err := parent.ProcessRequest(ctx, req); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := child.ProcessRequest(ctx, req); err != nil {
+				t.Fatal(err)
+			}
+			want := 2
+			if tt.wantShared {
+				want = 1
+			}
+			if requests != want {
+				t.Errorf("server saw %d requests, want %d", requests, want)
+			}
+			if parent.APIKey != "parent" {
+				t.Errorf("parent APIKey = %q", parent.APIKey)
+			}
+		})
+	}
+}
+
+func TestCloneKeepsCredentials(t *testing.T) {
+	parent := NewClient("http://localhost", "parent", WithTokenSource(TokenSourceFunc(func(context.Context) (Token, error) {
+		return Token{AccessToken: "t"}, nil
+	})))
+	parent.SetAPIKey("rotated")
+	child := parent.Clone(WithTimeout(time.Minute))
+	if child.tokens != parent.tokens {
+		t.Error("token cache not shared")
+	}
+	if key, _ := child.ResolveAPIKey(context.Background()); key != "rotated" {
+		t.Errorf("ResolveAPIKey() = %q, want the parent's rotated key", key)
+	}
+	override := parent.Clone(withAPIKey("child"))
+	if key, _ := override.ResolveAPIKey(context.Background()); key != "child" {
+		t.Errorf("ResolveAPIKey() = %q, want child", key)
+	}
+}
+
+func TestCloneNegotiatedVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want bool
+	}{
+		{"no options", nil, true},
+		{"timeout", []Option{WithTimeout(time.Minute)}, true},
+		{"api version", []Option{WithAPIVersion("2099-01-01")}, false},
+		{"base urls", []Option{WithBaseURLs("http://a.example", "http://b.example")}, false},
+		{"endpoints", []Option{WithEndpoints(Endpoint{URL: "http://a.example"})}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := NewClient("http://localhost", "k", WithVersionNegotiation())
+			parent.negotiated.Store(true)
+			if got := parent.Clone(tt.opts...).negotiated.Load(); got != tt.want {
+				t.Errorf("negotiated = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
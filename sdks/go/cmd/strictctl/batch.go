@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+func runBatch(ctx context.Context, e *env, args []string) error {
+	const usage = "batch [-format csv|ndjson] [-concurrency n] [-retries n] [-processor type] [-results file] [-failures file] file"
+	fs := e.flags(usage)
+	format := fs.String("format", "", "input `format`, csv or ndjson (default: from the file extension)")
+	concurrency := fs.Int("concurrency", 8, "requests in `flight`")
+	retries := fs.Int("retries", 2, "retries of each failed record, on top of the client's own")
+	processor := fs.String("processor", "", "processor `type` for records that don't name one")
+	resultsPath := fs.String("results", "-", "results `file`, NDJSON; - for stdout")
+	failuresPath := fs.String("failures", "-", "failure report `file`, NDJSON; - for stderr")
+	quiet := fs.Bool("quiet", false, "don't report progress on stderr")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	input := fs.Arg(0)
+	bf := strict.BatchFormat(*format)
+	if bf == "" {
+		if bf = strict.BatchFormatFor(input); bf == "" {
+			return fmt.Errorf("can't tell the format of %s; use -format", input)
+		}
+	}
+
+	var in io.Reader = e.stdin
+	if input != "-" {
+		f, err := os.Open(input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	src, err := strict.NewBatchReader(in, bf)
+	if err != nil {
+		return err
+	}
+	results, closeResults, err := createOutput(*resultsPath, e.stdout)
+	if err != nil {
+		return err
+	}
+	defer closeResults()
+	failures, closeFailures, err := createOutput(*failuresPath, e.stderr)
+	if err != nil {
+		return err
+	}
+	defer closeFailures()
+
+	var opts []strict.Option
+	if *processor != "" {
+		opts = append(opts, strict.WithDefaultProcessor(strict.ProcessorType(*processor)))
+	}
+	c, err := e.client(opts...)
+	if err != nil {
+		return err
+	}
+	retry := strict.DefaultRetryPolicy()
+	retry.MaxAttempts = *retries + 1
+	runner := &strict.BatchRunner{Client: c, Concurrency: *concurrency, Retry: retry}
+	if !*quiet {
+		var last time.Time
+		runner.Progress = func(s strict.BatchRunStats) {
+			if time.Since(last) >= time.Second {
+				last = time.Now()
+				fmt.Fprintf(e.stderr, "strictctl batch: %d done, %d failed\n", s.Succeeded+s.Failed, s.Failed)
+			}
+		}
+	}
+	stats, err := runner.Run(ctx, src, results, failures)
+	fmt.Fprintf(e.stderr, "strictctl batch: %d succeeded, %d failed of %d in %s\n",
+		stats.Succeeded, stats.Failed, stats.Read, stats.Elapsed.Round(time.Millisecond))
+	if err != nil {
+		return err
+	}
+	if err := closeResults(); err != nil {
+		return err
+	}
+	if err := closeFailures(); err != nil {
+		return err
+	}
+	if stats.Failed > 0 {
+		return errFailed
+	}
+	return nil
+}
+
+// createOutput opens path for writing, or returns std for "-". The close
+// function may be called more than once.
+func createOutput(path string, std io.Writer) (io.Writer, func() error, error) {
+	if path == "-" || path == "" {
+		return std, func() error { return nil }, nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var closed bool
+	var closeErr error
+	return f, func() error {
+		if !closed {
+			closed = true
+			closeErr = f.Close()
+		}
+		return closeErr
+	}, nil
+}
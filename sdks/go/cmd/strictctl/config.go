@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+func runConfig(ctx context.Context, e *env, args []string) error {
+	const usage = "config list|show [name]|path"
+	if len(args) == 0 {
+		fmt.Fprintf(e.stderr, "usage: strictctl %s\n", usage)
+		return errUsage
+	}
+	switch args[0] {
+	case "path":
+		path, err := strict.DefaultConfigPath()
+		if err != nil {
+			return err
+		}
+		return e.out.print(map[string]string{"path": path}, func(t *table) {
+			t.row(path)
+		})
+	case "list":
+		names, err := strict.ProfileNames("")
+		if err != nil {
+			return err
+		}
+		return e.out.print(names, func(t *table) {
+			for _, name := range names {
+				t.row(name)
+			}
+		})
+	case "show":
+		if len(args) > 2 {
+			break
+		}
+		name := e.profile
+		if len(args) == 2 {
+			name = args[1]
+		}
+		p, err := strict.LoadProfile("", name)
+		if err != nil {
+			return err
+		}
+		return e.printProfile(p)
+	}
+	fmt.Fprintf(e.stderr, "usage: strictctl %s\n", usage)
+	return errUsage
+}
+
+// shownProfile is a profile as printed, with the API key masked.
+type shownProfile struct {
+	Name            string   `json:"name"`
+	BaseURLs        []string `json:"base_urls,omitempty"`
+	APIKey          string   `json:"api_key,omitempty"`
+	Timeout         string   `json:"timeout,omitempty"`
+	MaxRetries      *int     `json:"max_retries,omitempty"`
+	Proxy           string   `json:"proxy,omitempty"`
+	APIVersion      string   `json:"api_version,omitempty"`
+	Tenant          string   `json:"tenant,omitempty"`
+	CACertFile      string   `json:"ca_cert_file,omitempty"`
+	UserAgentSuffix string   `json:"user_agent_suffix,omitempty"`
+}
+
+func (e *env) printProfile(p *strict.Profile) error {
+	sp := shownProfile{
+		Name:            p.Name,
+		BaseURLs:        p.BaseURLs,
+		APIKey:          maskKey(p.APIKey),
+		Proxy:           p.Proxy,
+		APIVersion:      p.APIVersion,
+		Tenant:          p.Tenant,
+		CACertFile:      p.CACertFile,
+		UserAgentSuffix: p.UserAgentSuffix,
+	}
+	if p.Timeout > 0 {
+		sp.Timeout = p.Timeout.String()
+	}
+	if p.MaxRetries >= 0 {
+		sp.MaxRetries = &p.MaxRetries
+	}
+	return e.out.print(sp, func(t *table) {
+		t.row("PROFILE", sp.Name)
+		t.row("BASE URLS", strings.Join(sp.BaseURLs, ", "))
+		t.row("API KEY", sp.APIKey)
+		t.row("TIMEOUT", sp.Timeout)
+		if sp.MaxRetries != nil {
+			t.row("MAX RETRIES", *sp.MaxRetries)
+		}
+		t.row("PROXY", sp.Proxy)
+		t.row("API VERSION", sp.APIVersion)
+		t.row("TENANT", sp.Tenant)
+		t.row("CA CERT FILE", sp.CACertFile)
+		t.row("USER AGENT", sp.UserAgentSuffix)
+	})
+}
+
+// maskKey hides all but the last four characters of an API key.
+func maskKey(key string) string {
+	if len(key) <= 8 {
+		return strings.Repeat("*", len(key))
+	}
+	return strings.Repeat("*", len(key)-4) + key[len(key)-4:]
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"sort"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+func runHealth(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("health [-ready]")
+	ready := fs.Bool("ready", false, "check readiness (GET /ready) instead of health")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	var st *strict.HealthStatus
+	if *ready {
+		st, err = c.Ready(ctx)
+	} else {
+		st, err = c.Health(ctx)
+	}
+	// A failed probe may still carry the server's report.
+	if st == nil {
+		return err
+	}
+	if perr := e.out.print(st, func(t *table) {
+		t.row("COMPONENT", "STATUS", "MESSAGE")
+		version := ""
+		if st.Version != "" {
+			version = "version " + st.Version
+		}
+		t.row("server", st.Status, version)
+		names := make([]string, 0, len(st.Components))
+		for name := range st.Components {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			cs := st.Components[name]
+			t.row(name, cs.Status, cs.Message)
+		}
+	}); perr != nil {
+		return perr
+	}
+	if err != nil {
+		return err
+	}
+	if !st.OK() {
+		return errFailed
+	}
+	return nil
+}
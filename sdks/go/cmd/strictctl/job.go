@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+func runJob(ctx context.Context, e *env, args []string) error {
+	const usage = "job status|await|cancel <id>"
+	if len(args) == 0 {
+		fmt.Fprintf(e.stderr, "usage: strictctl %s\n", usage)
+		return errUsage
+	}
+	switch args[0] {
+	case "status":
+		return runJobStatus(ctx, e, args[1:])
+	case "await":
+		return runJobAwait(ctx, e, args[1:])
+	case "cancel":
+		return runJobCancel(ctx, e, args[1:])
+	}
+	fmt.Fprintf(e.stderr, "strictctl job: unknown subcommand %q\nusage: strictctl %s\n", args[0], usage)
+	return errUsage
+}
+
+// jobID parses the single job ID argument of a job subcommand.
+func jobID(e *env, usage string, args []string) (strict.JobID, error) {
+	if len(args) != 1 || args[0] == "" {
+		fmt.Fprintf(e.stderr, "usage: strictctl %s\n", usage)
+		return "", errUsage
+	}
+	return strict.JobID(args[0]), nil
+}
+
+func runJobStatus(ctx context.Context, e *env, args []string) error {
+	id, err := jobID(e, "job status <id>", args)
+	if err != nil {
+		return err
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	st, err := c.GetJobStatus(ctx, id)
+	if err != nil {
+		return err
+	}
+	return e.printJob(st)
+}
+
+func runJobCancel(ctx context.Context, e *env, args []string) error {
+	id, err := jobID(e, "job cancel <id>", args)
+	if err != nil {
+		return err
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	st, err := c.CancelJob(ctx, id)
+	if err != nil {
+		return err
+	}
+	return e.printJob(st)
+}
+
+func runJobAwait(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("job await [-cancel-on-interrupt] [-quiet] <id>")
+	cancel := fs.Bool("cancel-on-interrupt", false, "cancel the job on the server if interrupted")
+	quiet := fs.Bool("quiet", false, "don't report status changes on stderr")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	id, err := jobID(e, "job await [-cancel-on-interrupt] [-quiet] <id>", fs.Args())
+	if err != nil {
+		return err
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	opts := strict.AwaitOptions{CancelOnDone: *cancel}
+	if !*quiet {
+		var last strict.JobState
+		opts.OnStatus = func(st *strict.JobStatus) {
+			if st.State != last {
+				last = st.State
+				fmt.Fprintf(e.stderr, "job %s: %s\n", st.ID, st.State)
+			}
+		}
+	}
+	out, err := c.AwaitJob(ctx, id, opts)
+	if err != nil {
+		return err
+	}
+	return e.printOutput(out)
+}
+
+func (e *env) printJob(st *strict.JobStatus) error {
+	return e.out.print(st, func(t *table) {
+		t.row("JOB", st.ID)
+		t.row("STATUS", st.State)
+		t.row("PROCESSOR", st.ProcessorType)
+		t.row("CREATED", st.CreatedAt)
+		t.row("UPDATED", st.UpdatedAt)
+		if st.Error != "" {
+			t.row("ERROR", st.Error)
+		}
+	})
+}
@@ -0,0 +1,187 @@
+// Command strictctl is a command-line client for the strict API, for
+// operators and quick debugging.
+//
+// Usage:
+//
+//	strictctl [flags] <command> [arguments]
+//
+// The commands are:
+//
+//	process    process input from a file or stdin
+//	validate   validate inputs without processing them
+//	batch      process a CSV or NDJSON file of requests
+//	job        show, await or cancel an asynchronous job
+//	health     check the server's health or readiness
+//	config     list and show config file profiles
+//	version    print the SDK version
+//
+// The client is configured like strict.NewClientFromEnv: from the config
+// file profile, ~/.strict/config, then the STRICT_* environment variables,
+// then the flags below. API keys are deliberately not accepted as flags,
+// so they stay out of shell history; set STRICT_API_KEY or use a profile.
+//
+// Output is a table by default, or JSON with -o json for scripts. The exit
+// status is 0 on success, 1 if the command failed or found a problem,
+// such as invalid input or an unhealthy server, and 2 on a usage error.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+// errUsage reports a usage error; the usage has already been printed.
+var errUsage = errors.New("usage error")
+
+// errFailed makes strictctl exit 1 after printing a result, such as an
+// invalid input, that is not itself an error.
+var errFailed = errors.New("failed")
+
+// env is one invocation's I/O and global settings.
+type env struct {
+	stdin          io.Reader
+	stdout, stderr io.Writer
+	out            *output
+
+	profile string
+	baseURL string
+	timeout time.Duration
+	debug   bool
+}
+
+type command struct {
+	name, summary string
+	run           func(ctx context.Context, e *env, args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"process", "process input from file or stdin", runProcess},
+		{"validate", "validate inputs, one per line, without processing them", runValidate},
+		{"batch", "process a CSV or NDJSON file of requests", runBatch},
+		{"job", "show, await or cancel an asynchronous job", runJob},
+		{"health", "check the server's health or readiness", runHealth},
+		{"config", "list and show config file profiles", runConfig},
+		{"version", "print the SDK version", runVersion},
+	}
+}
+
+// run is strictctl's main, returning the exit status.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	e := &env{stdin: stdin, stdout: stdout, stderr: stderr}
+	fs := flag.NewFlagSet("strictctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("o", "table", "output `format`: table or json")
+	fs.StringVar(&e.profile, "profile", "", "config file `profile` (default $STRICT_PROFILE or \"default\")")
+	fs.StringVar(&e.baseURL, "base-url", "", "API base `URL`, overriding the profile and $STRICT_BASE_URL")
+	fs.DurationVar(&e.timeout, "timeout", 0, "per-attempt `timeout`")
+	fs.BoolVar(&e.debug, "debug", false, "log requests and responses to stderr")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: strictctl [flags] <command> [arguments]\n\nCommands:\n")
+		for _, c := range commands {
+			fmt.Fprintf(stderr, "  %-10s %s\n", c.name, c.summary)
+		}
+		fmt.Fprintf(stderr, "\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	switch *format {
+	case "table", "json":
+		e.out = &output{w: stdout, json: *format == "json"}
+	default:
+		fmt.Fprintf(stderr, "strictctl: unknown output format %q\n", *format)
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	name := fs.Arg(0)
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		err := c.run(ctx, e, fs.Args()[1:])
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, errUsage):
+			return 2
+		case errors.Is(err, errFailed):
+			return 1
+		}
+		fmt.Fprintf(stderr, "strictctl %s: %v\n", name, err)
+		return 1
+	}
+	fmt.Fprintf(stderr, "strictctl: unknown command %q\n", name)
+	fs.Usage()
+	return 2
+}
+
+// client builds the API client from the profile, environment and flags,
+// then extra.
+func (e *env) client(extra ...strict.Option) (*strict.Client, error) {
+	var opts []strict.Option
+	if e.profile != "" {
+		opts = append(opts, strict.WithProfile(e.profile))
+	}
+	if e.baseURL != "" {
+		opts = append(opts, strict.WithBaseURLs(e.baseURL))
+	}
+	if e.timeout > 0 {
+		opts = append(opts, strict.WithTimeout(e.timeout))
+	}
+	if e.debug {
+		opts = append(opts, strict.WithDebug(e.stderr))
+	}
+	opts = append(opts, strict.WithUserAgentSuffix("strictctl/"+strict.Version))
+	return strict.NewClientFromEnv(append(opts, extra...)...)
+}
+
+// flags returns a flag set for a command, printing its usage line on
+// error.
+func (e *env) flags(usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(usage, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "usage: strictctl %s\n", usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses a command's flags, mapping errors to errUsage.
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	return nil
+}
+
+func runVersion(ctx context.Context, e *env, args []string) error {
+	return e.out.print(map[string]string{"version": strict.Version}, func(t *table) {
+		t.row("strictctl", strict.Version)
+	})
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+// validateServer answers POST /validate/batch, rejecting inputs that
+// say "bad".
+func validateServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in struct {
+			Requests []strict.ProcessingRequest `json:"requests"`
+		}
+		if r.URL.Path != "/validate/batch" || json.NewDecoder(r.Body).Decode(&in) != nil {
+			http.NotFound(w, r)
+			return
+		}
+		var out struct {
+			Results []strict.ValidationResult `json:"results"`
+		}
+		for _, req := range in.Requests {
+			res := strict.ValidationResult{Status: "valid", IsValid: true, InputHash: req.InputData}
+			if req.InputData == "bad" {
+				res = strict.ValidationResult{Status: "invalid", InputHash: req.InputData, Errors: []string{"bad input"}}
+			}
+			out.Results = append(out.Results, res)
+		}
+		json.NewEncoder(w).Encode(out)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("STRICT_CONFIG_FILE", filepath.Join(t.TempDir(), "none"))
+	t.Setenv("STRICT_PROFILE", "")
+	t.Setenv("STRICT_BASE_URL", srv.URL)
+	t.Setenv("STRICT_API_KEY", "k")
+	return srv
+}
+
+func TestRunExitCodes(t *testing.T) {
+	validateServer(t)
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+		want  int
+	}{
+		{"valid", []string{"validate"}, "a\nb\n", 0},
+		{"invalid input", []string{"validate"}, "a\nbad\n", 1},
+		{"no input", []string{"validate"}, "\n\n", 1},
+		{"help", []string{"-h"}, "", 0},
+		{"no command", nil, "", 2},
+		{"unknown command", []string{"bogus"}, "", 2},
+		{"unknown flag", []string{"-bogus", "validate"}, "", 2},
+		{"unknown format", []string{"-o", "xml", "validate"}, "a\n", 2},
+		{"bad command flag", []string{"validate", "-bogus"}, "a\n", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := run(context.Background(), tt.args, strings.NewReader(tt.stdin), &stdout, &stderr); got != tt.want {
+				t.Errorf("run(%q) = %d, want %d; stderr:\n%s", tt.args, got, tt.want, stderr.String())
+			}
+		})
+	}
+}
+
+func TestValidateLineNumbers(t *testing.T) {
+	validateServer(t)
+	var stdout, stderr bytes.Buffer
+	input := "a\n\n  \nbad\n\nc\n"
+	if code := run(context.Background(), []string{"validate"}, strings.NewReader(input), &stdout, &stderr); code != 1 {
+		t.Fatalf("exit status %d, want 1; stderr:\n%s", code, stderr.String())
+	}
+	// Each row's LINE and HASH columns: the server echoes the input as
+	// its hash.
+	var got []string
+	for _, row := range strings.Split(strings.TrimSpace(stdout.String()), "\n")[1:] {
+		f := strings.Fields(row)
+		got = append(got, f[0]+":"+f[3])
+	}
+	if want := "1:a 4:bad 6:c"; strings.Join(got, " ") != want {
+		t.Errorf("rows = %q, want %q\n%s", got, want, stdout.String())
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// output prints results as JSON or as an aligned table.
+type output struct {
+	w    io.Writer
+	json bool
+}
+
+// print writes v as indented JSON, or calls fill to build a table.
+func (o *output) print(v interface{}, fill func(*table)) error {
+	if o.json {
+		enc := json.NewEncoder(o.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	t := &table{tw: tabwriter.NewWriter(o.w, 0, 4, 2, ' ', 0)}
+	fill(t)
+	return t.tw.Flush()
+}
+
+type table struct {
+	tw *tabwriter.Writer
+}
+
+// row writes one line of tab-separated cells.
+func (t *table) row(cells ...interface{}) {
+	s := make([]string, len(cells))
+	for i, c := range cells {
+		s[i] = cell(c)
+	}
+	fmt.Fprintln(t.tw, strings.Join(s, "\t"))
+}
+
+func cell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		if v == "" {
+			return "-"
+		}
+		return v
+	case time.Time:
+		if v.IsZero() {
+			return "-"
+		}
+		return v.Format(time.RFC3339)
+	case time.Duration:
+		return v.String()
+	case fmt.Stringer:
+		return cell(v.String())
+	}
+	return fmt.Sprint(v)
+}
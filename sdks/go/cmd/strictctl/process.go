@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+// readInput reads the file named by args, or stdin if there is none or
+// it is "-".
+func (e *env) readInput(args []string) ([]byte, error) {
+	switch {
+	case len(args) > 1:
+		return nil, errUsage
+	case len(args) == 0 || args[0] == "-":
+		return io.ReadAll(e.stdin)
+	}
+	return os.ReadFile(args[0])
+}
+
+func runProcess(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("process [-processor type] [-dry-run] [-async] [file]")
+	processor := fs.String("processor", "", "processor `type`, such as cloud or local (default: the server's choice)")
+	dryRun := fs.Bool("dry-run", false, "validate and route without running the processor")
+	async := fs.Bool("async", false, "submit a job and print its ID instead of waiting")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	input, err := e.readInput(fs.Args())
+	if err != nil {
+		if err == errUsage {
+			fs.Usage()
+		}
+		return err
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	req := strict.ProcessingRequest{
+		InputData:     string(input),
+		ProcessorType: strict.ProcessorType(*processor),
+	}
+	var opts []strict.CallOption
+	if *dryRun {
+		opts = append(opts, strict.WithDryRun())
+	}
+	if *async {
+		id, err := c.SubmitJob(ctx, req, opts...)
+		if err != nil {
+			return err
+		}
+		return e.out.print(map[string]strict.JobID{"job_id": id}, func(t *table) {
+			t.row("JOB", id)
+		})
+	}
+	out, err := c.ProcessRequest(ctx, req, opts...)
+	if err != nil {
+		return err
+	}
+	return e.printOutput(out)
+}
+
+// printOutput prints a processing result, failing if it didn't validate.
+func (e *env) printOutput(out *strict.OutputSchema) error {
+	raw, err := out.RawResult()
+	if err != nil {
+		return err
+	}
+	err = e.out.print(out, func(t *table) {
+		t.row("PROCESSOR", out.ProcessorUsed)
+		t.row("STATUS", out.Validation.Status)
+		t.row("VALID", out.Validation.IsValid)
+		t.row("TIME", fmt.Sprintf("%.1fms", out.ProcessingTimeMs))
+		t.row("RETRIES", out.RetriesAttempted)
+		if out.DryRun {
+			t.row("DRY RUN", true)
+		}
+		if out.RequestID != "" {
+			t.row("REQUEST", out.RequestID)
+		}
+		for _, msg := range out.Validation.Errors {
+			t.row("ERROR", msg)
+		}
+		if string(raw) != "null" {
+			t.row("RESULT", string(raw))
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if !out.Validation.IsValid && out.Validation.Status != "" {
+		return errFailed
+	}
+	return nil
+}
+
+func runValidate(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("validate [-processor type] [file]")
+	processor := fs.String("processor", "", "processor `type` to validate for")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	input, err := e.readInput(fs.Args())
+	if err != nil {
+		if err == errUsage {
+			fs.Usage()
+		}
+		return err
+	}
+	var (
+		reqs  []strict.ProcessingRequest
+		lines []int // of each request in the input, counting from 1
+	)
+	sc := bufio.NewScanner(strings.NewReader(string(input)))
+	sc.Buffer(nil, len(input)+1)
+	for n := 1; sc.Scan(); n++ {
+		if line := strings.TrimSpace(sc.Text()); line != "" {
+			reqs = append(reqs, strict.ProcessingRequest{InputData: line, ProcessorType: strict.ProcessorType(*processor)})
+			lines = append(lines, n)
+		}
+	}
+	if len(reqs) == 0 {
+		return fmt.Errorf("no input")
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	results, err := c.ValidateBatch(ctx, reqs)
+	if err != nil {
+		return err
+	}
+	err = e.out.print(results, func(t *table) {
+		t.row("LINE", "VALID", "STATUS", "HASH", "ERRORS")
+		for i, r := range results {
+			t.row(lines[i], r.IsValid, r.Status, r.InputHash, strings.Join(r.Errors, "; "))
+		}
+	})
+	if err != nil {
+		return err
+	}
+	invalid := 0
+	for _, r := range results {
+		if !r.IsValid {
+			invalid++
+		}
+	}
+	if invalid > 0 {
+		fmt.Fprintf(e.stderr, "strictctl validate: %d of %d inputs invalid\n", invalid, len(results))
+		return errFailed
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package strict
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+const jsonContentType = "application/json"
+
+// Codec converts request and response bodies to and from a wire format.
+// JSON is the default; the strictmsgpack and strictcbor packages provide
+// MessagePack and CBOR.
+type Codec interface {
+	// ContentType is the media type sent in Content-Type and Accept.
+	ContentType() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONCodec returns the default JSON codec.
+func JSONCodec() Codec { return jsonCodec{} }
+
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string { return jsonContentType }
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// WithCodec encodes request bodies with codec and asks the server to
+// answer in kind, accepting JSON as well. If the server rejects the
+// format with 415 Unsupported Media Type, the client resends the call as
+// JSON and keeps using JSON from then on.
+func WithCodec(codec Codec) Option {
+	return func(c *Client) {
+		if codec == nil {
+			c.setConfigErr(errors.New("strict: WithCodec: nil codec"))
+			return
+		}
+		c.codec = codec
+	}
+}
+
+// requestCodec is the codec to encode the next call with.
+func (c *Client) requestCodec() Codec {
+	if c.codec == nil || c.codecRejected.Load() {
+		return jsonCodec{}
+	}
+	return c.codec
+}
+
+// marshalBody re-encodes cl's body with codec. Bodies are built as JSON,
+// so only calls that carry their source value in cl.in are affected.
+// Calls that set their own content type are sent as-is.
+func (c *Client) marshalBody(cl *call, codec Codec) error {
+	if cl.contentType != "" {
+		return nil
+	}
+	cl.contentType = jsonContentType
+	if cl.in == nil || codec.ContentType() == jsonContentType {
+		return nil
+	}
+	data, err := codec.Marshal(cl.in)
+	if err != nil {
+		return fmt.Errorf("strict: encode request: %w", err)
+	}
+	cl.body = data
+	cl.contentType = codec.ContentType()
+	return nil
+}
+
+// accept is the Accept header for cl, or "" to leave it unset.
+func (cl *call) accept() string {
+	if cl.in == nil || cl.contentType == jsonContentType {
+		return ""
+	}
+	return cl.contentType + ", " + jsonContentType + ";q=0.9"
+}
+
+// decodeResponse decodes a successful response into cl.out, honouring
+// the response Content-Type so servers may answer in JSON either way.
+func (c *Client) decodeResponse(resp *http.Response, cl *call) error {
+	if resp.StatusCode == http.StatusNoContent || cl.out == nil {
+		return nil
+	}
+	var err error
+	if mt, _, perr := mime.ParseMediaType(resp.Header.Get("Content-Type")); perr == nil &&
+		c.codec != nil && mt == c.codec.ContentType() && mt != jsonContentType {
+		var data []byte
+		if data, err = io.ReadAll(resp.Body); err == nil {
+			err = c.codec.Unmarshal(data, cl.out)
+		}
+	} else if c.strictDecoding {
+		var data []byte
+		if data, err = io.ReadAll(resp.Body); err == nil {
+			err = c.decodeJSON(data, cl.out)
+		}
+	} else {
+		err = json.NewDecoder(resp.Body).Decode(cl.out)
+	}
+	if _, ok := err.(*UnknownFieldsError); ok {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("strict: decode response: %w", err)
+	}
+	return nil
+}
+
+// unsupportedMediaType reports whether err is the server refusing cl's
+// non-JSON body format.
+func unsupportedMediaType(cl *call, err error) bool {
+	var apiErr *APIError
+	return cl.in != nil && cl.contentType != jsonContentType && errors.As(err, &apiErr) &&
+		apiErr.StatusCode == http.StatusUnsupportedMediaType
+}
@@ -0,0 +1,169 @@
+package strict
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// defaultCompressionThreshold is the body size above which requests are
+// compressed when no threshold is given.
+const defaultCompressionThreshold = 64 << 10
+
+// Compressor encodes request bodies for a Content-Encoding. The strictzstd
+// package provides a zstd implementation.
+type Compressor interface {
+	// Encoding is the Content-Encoding token, e.g. "gzip".
+	Encoding() string
+	Compress(p []byte) ([]byte, error)
+}
+
+// GzipCompressor compresses with gzip at the given level; see
+// compress/gzip for valid levels.
+func GzipCompressor(level int) Compressor {
+	return gzipCompressor{level: level}
+}
+
+type gzipCompressor struct {
+	level int
+}
+
+func (gzipCompressor) Encoding() string { return "gzip" }
+
+func (g gzipCompressor) Compress(p []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, g.level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(p); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WithRequestCompression compresses request bodies larger than threshold
+// bytes with comp. A threshold of zero or less means 64 KiB.
+func WithRequestCompression(comp Compressor, threshold int) Option {
+	return func(c *Client) {
+		if threshold <= 0 {
+			threshold = defaultCompressionThreshold
+		}
+		c.compressor = comp
+		c.compressThreshold = threshold
+	}
+}
+
+// encodeBody compresses the call's body once, before the first attempt,
+// so retries reuse the result. Compression failures fall back to sending
+// the body as-is.
+func (c *Client) encodeBody(cl *call) {
+	cl.wire = cl.body
+	if c.compressor == nil || len(cl.body) <= c.compressThreshold {
+		return
+	}
+	compressed, err := c.compressor.Compress(cl.body)
+	if err != nil || len(compressed) >= len(cl.body) {
+		return
+	}
+	cl.wire = compressed
+	cl.contentEncoding = c.compressor.Encoding()
+}
+
+// Decompressor decodes response bodies for a Content-Encoding. The
+// strictzstd package provides a zstd implementation.
+type Decompressor interface {
+	// Encoding is the Content-Encoding token, e.g. "gzip".
+	Encoding() string
+	Decompress(r io.Reader) (io.ReadCloser, error)
+}
+
+type gzipDecompressor struct{}
+
+func (gzipDecompressor) Encoding() string { return "gzip" }
+
+func (gzipDecompressor) Decompress(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// WithResponseDecompression advertises the given encodings, in addition
+// to gzip, in Accept-Encoding and decodes responses that use them.
+func WithResponseDecompression(ds ...Decompressor) Option {
+	return func(c *Client) {
+		for _, d := range ds {
+			c.decompressors[strings.ToLower(d.Encoding())] = d
+		}
+	}
+}
+
+// WithoutResponseCompression asks the server for uncompressed responses.
+func WithoutResponseCompression() Option {
+	return func(c *Client) {
+		c.decompressors = nil
+	}
+}
+
+func defaultDecompressors() map[string]Decompressor {
+	return map[string]Decompressor{"gzip": gzipDecompressor{}}
+}
+
+// acceptEncoding lists the supported encodings, preferring non-gzip ones
+// since they are opt-in and usually denser.
+func (c *Client) acceptEncoding() string {
+	if len(c.decompressors) == 0 {
+		return "identity"
+	}
+	encs := make([]string, 0, len(c.decompressors))
+	for enc := range c.decompressors {
+		if enc != "gzip" {
+			encs = append(encs, enc)
+		}
+	}
+	sort.Strings(encs)
+	if _, ok := c.decompressors["gzip"]; ok {
+		encs = append(encs, "gzip")
+	}
+	return strings.Join(encs, ", ")
+}
+
+// decodeBody wraps resp.Body to undo its Content-Encoding.
+func (c *Client) decodeBody(resp *http.Response) error {
+	enc := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if enc == "" || enc == "identity" {
+		return nil
+	}
+	d, ok := c.decompressors[enc]
+	if !ok {
+		return fmt.Errorf("strict: unsupported response Content-Encoding %q", enc)
+	}
+	rc, err := d.Decompress(resp.Body)
+	if err != nil {
+		return fmt.Errorf("strict: decompress response: %w", err)
+	}
+	resp.Body = &decodedBody{ReadCloser: rc, raw: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	return nil
+}
+
+// decodedBody closes both the decoder and the underlying body.
+type decodedBody struct {
+	io.ReadCloser
+	raw io.Closer
+}
+
+func (b *decodedBody) Close() error {
+	err := b.ReadCloser.Close()
+	if rerr := b.raw.Close(); err == nil {
+		err = rerr
+	}
+	return err
+}
@@ -0,0 +1,422 @@
+package strict
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mohitmishra786/strict/sdks/go/internal/openapi"
+)
+
+// ContractError is a difference between one of the SDK's wire types and
+// the server's OpenAPI schema for it.
+type ContractError struct {
+	// Operation is the endpoint, such as "POST /process/request".
+	Operation string
+	// Type is the Go type checked, such as "strict.OutputSchema". It is
+	// empty for problems with the operation itself.
+	Type string
+	// Field is the JSON path of the mismatch, such as
+	// "validation.errors", or empty for the whole body.
+	Field   string
+	Problem string
+}
+
+func (e *ContractError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Operation)
+	if e.Type != "" {
+		b.WriteString(" " + e.Type)
+	}
+	if e.Field != "" {
+		b.WriteString("." + e.Field)
+	}
+	return b.String() + ": " + e.Problem
+}
+
+// wireContract names the Go types the SDK sends to and expects from one
+// endpoint. A nil type means no JSON body.
+type wireContract struct {
+	method, path string
+	req, resp    interface{}
+}
+
+//go:generate go run ./internal/cmd/strictgen -o api_gen.go
+
+// wireContracts lists every hand-written JSON endpoint the client calls.
+// Keep it in step with the newJSONCall sites: strictgen generates methods
+// for the spec's other operations and appends them here.
+var wireContracts = []wireContract{
+	{http.MethodPost, "/process/request", ProcessingRequest{}, OutputSchema{}},
+	{http.MethodPost, "/process/batch", batchRequest{}, batchResponse{}},
+	{http.MethodPost, "/validate/batch", batchRequest{}, validateBatchResponse{}},
+	{http.MethodPost, "/jobs", ProcessingRequest{}, JobStatus{}},
+	{http.MethodGet, "/jobs", nil, jobPage{}},
+	{http.MethodGet, "/jobs/{id}", nil, JobStatus{}},
+	{http.MethodGet, "/jobs/{id}/result", nil, OutputSchema{}},
+	{http.MethodPost, "/jobs/{id}/cancel", nil, JobStatus{}},
+	{http.MethodPost, "/uploads", map[string]int64{}, uploadSession{}},
+	{http.MethodGet, "/uploads/{id}", nil, uploadSession{}},
+	{http.MethodPost, "/uploads/{id}/complete", uploadComplete{}, OutputSchema{}},
+	{http.MethodGet, "/health", nil, HealthStatus{}},
+	{http.MethodGet, "/ready", nil, HealthStatus{}},
+	{http.MethodGet, "/info", nil, ServerInfo{}},
+	{http.MethodGet, "/versions", nil, versionList{}},
+	{http.MethodGet, "/processors", nil, processorList{}},
+	{http.MethodGet, "/usage", nil, Usage{}},
+	{http.MethodPost, "/admin/keys", CreateAPIKeyRequest{}, NewAPIKey{}},
+	{http.MethodGet, "/admin/keys", nil, apiKeyList{}},
+	{http.MethodPost, "/admin/keys/{id}/rotate", rotateAPIKeyRequest{}, NewAPIKey{}},
+	{http.MethodDelete, "/admin/keys/{id}", nil, nil},
+	{http.MethodPost, "/webhooks", CreateWebhookRequest{}, Webhook{}},
+	{http.MethodGet, "/webhooks", nil, webhookList{}},
+	{http.MethodGet, "/webhooks/{id}", nil, Webhook{}},
+	{http.MethodPatch, "/webhooks/{id}", UpdateWebhookRequest{}, Webhook{}},
+	{http.MethodDelete, "/webhooks/{id}", nil, nil},
+	{http.MethodPost, "/events/replay", replayRequest{}, Replay{}},
+}
+
+// CheckContract compares the SDK's request and response types with spec,
+// the server's OpenAPI document as served at /openapi.json, and returns
+// every mismatch it finds: endpoints missing from the spec, fields one
+// side has and the other doesn't, incompatible field types, and fields
+// the spec requires in requests that the SDK may omit. Each type is also
+// round-tripped: an example built from its schema is decoded into the Go
+// type and encoded again, and any field lost on the way is reported.
+//
+// The error is set only if spec can't be parsed. stricttest.VerifyContract
+// runs the check from a test.
+func CheckContract(spec []byte) ([]*ContractError, error) {
+	doc, err := openapi.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	var problems []*ContractError
+	for _, wc := range wireContracts {
+		op, _ := doc.Operation(wc.method, wc.path)
+		name := wc.method + " " + wc.path
+		if op == nil {
+			problems = append(problems, &ContractError{Operation: name, Problem: "operation not in spec"})
+			continue
+		}
+		if wc.req != nil {
+			problems = append(problems, checkBody(doc, name, reflect.TypeOf(wc.req), op.RequestBody, true)...)
+		}
+		if wc.resp != nil {
+			resp, _ := op.SuccessResponse()
+			problems = append(problems, checkBody(doc, name, reflect.TypeOf(wc.resp), resp, false)...)
+		}
+	}
+	return problems, nil
+}
+
+// checkBody checks t against the JSON schema of body.
+func checkBody(doc *openapi.Document, op string, t reflect.Type, body *openapi.Body, request bool) []*ContractError {
+	side := "response"
+	if request {
+		side = "request"
+	}
+	schema := body.JSONSchema()
+	if schema == nil {
+		return []*ContractError{{Operation: op, Type: t.String(), Problem: "spec has no JSON " + side + " body"}}
+	}
+	cc := &contractCheck{doc: doc, op: op, typ: t.String(), request: request, seen: make(map[contractPair]bool)}
+	cc.compare(t, schema, "")
+	if len(cc.problems) == 0 {
+		cc.roundTrip(t, schema)
+	}
+	return cc.problems
+}
+
+type contractPair struct {
+	t reflect.Type
+	s *openapi.Schema
+}
+
+type contractCheck struct {
+	doc      *openapi.Document
+	op, typ  string
+	request  bool
+	seen     map[contractPair]bool
+	problems []*ContractError
+}
+
+func (cc *contractCheck) report(field, format string, args ...interface{}) {
+	cc.problems = append(cc.problems, &ContractError{
+		Operation: cc.op,
+		Type:      cc.typ,
+		Field:     field,
+		Problem:   fmt.Sprintf(format, args...),
+	})
+}
+
+var (
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	timeType            = reflect.TypeOf(time.Time{})
+)
+
+// compare checks that values of t fit schema s, recursing into fields,
+// elements and map values.
+func (cc *contractCheck) compare(t reflect.Type, s *openapi.Schema, field string) {
+	s, err := cc.doc.Resolve(s)
+	if err != nil {
+		cc.report(field, "%v", err)
+		return
+	}
+	if s == nil {
+		return
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	key := contractPair{t, s}
+	if cc.seen[key] {
+		return
+	}
+	cc.seen[key] = true
+
+	if branches := append(append([]*openapi.Schema(nil), s.AnyOf...), s.OneOf...); len(branches) > 0 {
+		cc.compareBranches(t, branches, field)
+		return
+	}
+	if t == timeType {
+		if s.Type.Main() != "string" && s.Type.Main() != "" {
+			cc.report(field, "Go type %s but schema type %s", t, s.Type.Main())
+		}
+		return
+	}
+	if t.Kind() == reflect.Interface || t == rawMessageType ||
+		t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return
+	}
+	want := s.Type.Main()
+	if want == "" {
+		if len(s.Properties) > 0 {
+			want = "object"
+		} else {
+			return
+		}
+	}
+	if got := schemaKind(t); got != want && !(got == "integer" && want == "number") {
+		cc.report(field, "Go type %s but schema type %s", t, want)
+		return
+	}
+	switch want {
+	case "array":
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		if s.Items != nil {
+			cc.compare(t.Elem(), s.Items, field+"[]")
+		}
+	case "object":
+		if t.Kind() == reflect.Map {
+			if extra, _ := s.AdditionalSchema(); extra != nil {
+				cc.compare(t.Elem(), extra, joinField(field, "*"))
+			}
+			return
+		}
+		cc.compareStruct(t, s, field)
+	}
+}
+
+// compareBranches checks t against the first anyOf or oneOf branch it
+// fits, ignoring null branches.
+func (cc *contractCheck) compareBranches(t reflect.Type, branches []*openapi.Schema, field string) {
+	var last []*ContractError
+	for _, b := range branches {
+		r, err := cc.doc.Resolve(b)
+		if err != nil || r == nil || (len(r.Type) == 1 && r.Type[0] == "null") {
+			continue
+		}
+		sub := &contractCheck{doc: cc.doc, op: cc.op, typ: cc.typ, request: cc.request, seen: cc.seen}
+		sub.compare(t, r, field)
+		if len(sub.problems) == 0 {
+			return
+		}
+		last = sub.problems
+	}
+	cc.problems = append(cc.problems, last...)
+}
+
+func (cc *contractCheck) compareStruct(t reflect.Type, s *openapi.Schema, field string) {
+	fields := jsonFields(t)
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	_, open := s.AdditionalSchema()
+	for _, name := range names {
+		f := fields[name]
+		prop, ok := s.Properties[name]
+		if !ok {
+			if len(s.Properties) > 0 && !open {
+				cc.report(joinField(field, name), "field not in schema")
+			}
+			continue
+		}
+		if cc.request && omitsEmpty(f) && s.IsRequired(name) {
+			cc.report(joinField(field, name), "required by schema but omitted when empty")
+		}
+		cc.compare(f.Type, prop, joinField(field, name))
+	}
+	props := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		props = append(props, name)
+	}
+	sort.Strings(props)
+	for _, name := range props {
+		if _, ok := fields[name]; !ok && (cc.request && s.IsRequired(name)) {
+			cc.report(joinField(field, name), "required by schema but missing from Go type")
+		}
+	}
+}
+
+// roundTrip decodes an example of s into t and encodes it again,
+// reporting fields that are rejected or lost.
+func (cc *contractCheck) roundTrip(t reflect.Type, s *openapi.Schema) {
+	example := cc.example(s, 0)
+	data, err := json.Marshal(example)
+	if err != nil {
+		return
+	}
+	v := reflect.New(t)
+	if err := json.Unmarshal(data, v.Interface()); err != nil {
+		cc.report("", "schema example does not decode: %v", err)
+		return
+	}
+	again, err := json.Marshal(v.Interface())
+	if err != nil {
+		cc.report("", "decoded example does not encode: %v", err)
+		return
+	}
+	var back interface{}
+	json.Unmarshal(again, &back)
+	cc.lost(example, back, "")
+}
+
+// lost reports object keys of want missing from got. Null examples may
+// be dropped by omitempty, so they aren't missed.
+func (cc *contractCheck) lost(want, got interface{}, field string) {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			return
+		}
+		keys := make([]string, 0, len(w))
+		for k := range w {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			gv, ok := g[k]
+			if !ok && w[k] != nil {
+				cc.report(joinField(field, k), "schema field lost in round trip")
+				continue
+			}
+			cc.lost(w[k], gv, joinField(field, k))
+		}
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if ok && len(w) > 0 && len(g) > 0 {
+			cc.lost(w[0], g[0], field+"[]")
+		}
+	}
+}
+
+// example builds a value that fits s, with every property set to a
+// non-zero value.
+func (cc *contractCheck) example(s *openapi.Schema, depth int) interface{} {
+	s, err := cc.doc.Resolve(s)
+	if err != nil || s == nil || depth > 8 {
+		return nil
+	}
+	if len(s.Enum) > 0 {
+		return s.Enum[0]
+	}
+	for _, b := range append(append([]*openapi.Schema(nil), s.AnyOf...), s.OneOf...) {
+		if r, err := cc.doc.Resolve(b); err == nil && r != nil && (r.Type.Main() != "" || len(r.Properties) > 0) {
+			return cc.example(r, depth+1)
+		}
+	}
+	switch s.Type.Main() {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			return "2024-01-02T03:04:05Z"
+		case "byte":
+			return "AQID"
+		}
+		return "x"
+	case "integer":
+		return 1
+	case "number":
+		return 1.5
+	case "boolean":
+		return true
+	case "array":
+		if s.Items == nil {
+			return []interface{}{}
+		}
+		return []interface{}{cc.example(s.Items, depth+1)}
+	case "object", "":
+		if s.Type.Main() == "" && len(s.Properties) == 0 {
+			// Any value will do.
+			return nil
+		}
+		obj := make(map[string]interface{})
+		for name, p := range s.Properties {
+			obj[name] = cc.example(p, depth+1)
+		}
+		if len(s.Properties) == 0 {
+			if extra, _ := s.AdditionalSchema(); extra != nil {
+				obj["key"] = cc.example(extra, depth+1)
+			}
+		}
+		return obj
+	}
+	return nil
+}
+
+// schemaKind is the JSON Schema type Go encodes t as.
+func schemaKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string"
+		}
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return t.Kind().String()
+}
+
+// omitsEmpty reports whether f is tagged omitempty.
+func omitsEmpty(f reflect.StructField) bool {
+	_, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+	return strings.Contains(opts, "omitempty")
+}
+
+func joinField(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
@@ -0,0 +1,57 @@
+package strict
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// CredentialsProvider supplies the API key for each request. It is called
+// once per attempt, so implementations that fetch keys remotely should
+// cache them. It must be safe for concurrent use.
+type CredentialsProvider interface {
+	APIKey(ctx context.Context) (string, error)
+}
+
+// CredentialsFunc adapts a function to a CredentialsProvider.
+type CredentialsFunc func(ctx context.Context) (string, error)
+
+// APIKey calls f.
+func (f CredentialsFunc) APIKey(ctx context.Context) (string, error) { return f(ctx) }
+
+// WithCredentials takes the API key for each request from p instead of
+// the key given to NewClient, so keys can rotate without rebuilding the
+// client. An error from p fails the call without retrying.
+func WithCredentials(p CredentialsProvider) Option {
+	return func(c *Client) {
+		if p == nil {
+			c.setConfigErr(errors.New("strict: WithCredentials: nil provider"))
+			return
+		}
+		c.credentials = p
+	}
+}
+
+// SetAPIKey replaces the API key sent with later requests. Requests
+// already in flight keep the key they started with. It is safe for
+// concurrent use, unlike assigning to the APIKey field, and has no effect
+// on a client configured WithCredentials.
+func (c *Client) SetAPIKey(key string) {
+	c.apiKey.Store(&key)
+}
+
+// ResolveAPIKey returns the API key the next request will send, for
+// packages that open their own connections to the server.
+func (c *Client) ResolveAPIKey(ctx context.Context) (string, error) {
+	if c.credentials != nil {
+		key, err := c.credentials.APIKey(ctx)
+		if err != nil {
+			return "", fmt.Errorf("strict: credentials: %w", err)
+		}
+		return key, nil
+	}
+	if key := c.apiKey.Load(); key != nil {
+		return *key, nil
+	}
+	return c.APIKey, nil
+}
@@ -0,0 +1,335 @@
+package strict
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNoDeadLetterStore is returned by the dead-letter methods of a
+	// client built without WithDeadLetters.
+	ErrNoDeadLetterStore = errors.New("strict: client has no dead-letter store")
+	// ErrDeadLetterNotFound is returned for a dead letter that isn't in
+	// the store.
+	ErrDeadLetterNotFound = errors.New("strict: dead letter not found")
+)
+
+// maxDeadLetterErrors bounds the error history kept per request; older
+// errors are dropped.
+const maxDeadLetterErrors = 50
+
+// AttemptError records one failed attempt to process a request.
+type AttemptError struct {
+	Time  time.Time `json:"time"`
+	Error string    `json:"error"`
+	// StatusCode is the HTTP status of an API error, or zero.
+	StatusCode int `json:"status_code,omitempty"`
+	// Class is the error's ErrorClass, as a string.
+	Class string `json:"class"`
+}
+
+func newAttemptError(err error) AttemptError {
+	ae := AttemptError{Time: time.Now(), Error: err.Error(), Class: Classify(err).String()}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		ae.StatusCode = apiErr.StatusCode
+	}
+	return ae
+}
+
+// DeadLetter is a request that failed for good, kept with its history so
+// it can be inspected and reprocessed.
+type DeadLetter struct {
+	ID      string            `json:"id"`
+	Request ProcessingRequest `json:"request"`
+	// Errors is the request's error history, oldest first. The last is
+	// the error it failed with.
+	Errors []AttemptError `json:"errors"`
+	// Created is when the request was dead-lettered; Updated is when a
+	// reprocessing attempt last failed, or Created.
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+	// Reprocessed counts the reprocessing attempts that failed.
+	Reprocessed int `json:"reprocessed,omitempty"`
+}
+
+// LastError returns the error the request last failed with.
+func (d DeadLetter) LastError() AttemptError {
+	if len(d.Errors) == 0 {
+		return AttemptError{}
+	}
+	return d.Errors[len(d.Errors)-1]
+}
+
+// DeadLetterStore keeps dead letters. Implementations must be safe for
+// concurrent use.
+type DeadLetterStore interface {
+	// Put adds d, or replaces the dead letter with the same ID.
+	Put(d DeadLetter) error
+	// Get returns the dead letter with the given ID, or
+	// ErrDeadLetterNotFound.
+	Get(id string) (DeadLetter, error)
+	// List returns the dead letters, oldest first.
+	List() ([]DeadLetter, error)
+	// Delete removes the dead letter with the given ID, if there is one.
+	Delete(id string) error
+}
+
+// WithDeadLetters keeps requests that fail for good in store: a
+// ProcessRequest that fails once the client's retries and any fallback
+// are spent, and a request that fails for good in a Pool, BatchRunner or
+// OfflineQueue after their own retries. Requests that fail because the
+// caller's context ended are not kept. Inspect and act on the store with
+// ListDeadLetters, ReprocessDeadLetter and PurgeDeadLetters.
+func WithDeadLetters(store DeadLetterStore) Option {
+	return func(c *Client) {
+		if store == nil {
+			c.setConfigErr(errors.New("strict: WithDeadLetters: nil store"))
+			return
+		}
+		c.deadLetters = store
+	}
+}
+
+// attemptLog collects a call's failed attempts for its dead letter. A
+// call that has one set doesn't dead-letter itself: whoever set it
+// decides when a failure is final.
+type attemptLog struct {
+	mu   sync.Mutex
+	errs []AttemptError
+	last error
+}
+
+// withAttemptLog records the call's failed attempts in l.
+func withAttemptLog(l *attemptLog) CallOption {
+	return func(co *callOptions) {
+		co.attempts = l
+	}
+}
+
+func (l *attemptLog) add(err error) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.errs = append(l.errs, newAttemptError(err))
+	l.last = err
+}
+
+// history returns the attempts so far, ending with final.
+func (l *attemptLog) history(final error) []AttemptError {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	errs := append([]AttemptError(nil), l.errs...)
+	// Errors that end a call before an attempt, such as an open circuit
+	// breaker, aren't in the log yet.
+	if final != nil && final != l.last {
+		errs = append(errs, newAttemptError(final))
+	}
+	return errs
+}
+
+// deadLetter keeps req in the client's dead-letter store, unless the
+// caller gave up.
+func (c *Client) deadLetter(ctx context.Context, req ProcessingRequest, history []AttemptError) {
+	if c.deadLetters == nil || ctx.Err() != nil {
+		return
+	}
+	if len(history) > maxDeadLetterErrors {
+		history = history[len(history)-maxDeadLetterErrors:]
+	}
+	now := time.Now()
+	d := DeadLetter{ID: newUUIDv7(), Request: req, Errors: history, Created: now, Updated: now}
+	if err := c.deadLetters.Put(d); err != nil {
+		c.log(ctx, slog.LevelError, "strict: could not store dead letter",
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// ListDeadLetters returns the client's dead letters, oldest first.
+func (c *Client) ListDeadLetters() ([]DeadLetter, error) {
+	if c.deadLetters == nil {
+		return nil, ErrNoDeadLetterStore
+	}
+	return c.deadLetters.List()
+}
+
+// ReprocessDeadLetter processes the dead letter with the given ID again.
+// On success it is removed from the store; on failure its history is
+// extended with the new errors.
+func (c *Client) ReprocessDeadLetter(ctx context.Context, id string, opts ...CallOption) (*OutputSchema, error) {
+	if c.deadLetters == nil {
+		return nil, ErrNoDeadLetterStore
+	}
+	d, err := c.deadLetters.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	log := new(attemptLog)
+	out, err := c.ProcessRequest(ctx, d.Request, append(opts[:len(opts):len(opts)], withAttemptLog(log))...)
+	if err == nil {
+		return out, c.deadLetters.Delete(id)
+	}
+	if ctx.Err() != nil {
+		return nil, err
+	}
+	d.Errors = append(d.Errors, log.history(err)...)
+	if len(d.Errors) > maxDeadLetterErrors {
+		d.Errors = d.Errors[len(d.Errors)-maxDeadLetterErrors:]
+	}
+	d.Updated = time.Now()
+	d.Reprocessed++
+	if perr := c.deadLetters.Put(d); perr != nil {
+		return nil, errors.Join(err, perr)
+	}
+	return nil, err
+}
+
+// DeleteDeadLetter removes the dead letter with the given ID.
+func (c *Client) DeleteDeadLetter(id string) error {
+	if c.deadLetters == nil {
+		return ErrNoDeadLetterStore
+	}
+	return c.deadLetters.Delete(id)
+}
+
+// PurgeDeadLetters removes the dead letters last updated before the
+// given time, and returns how many it removed. Pass time.Now() to remove them all.
+func (c *Client) PurgeDeadLetters(before time.Time) (int, error) {
+	if c.deadLetters == nil {
+		return 0, ErrNoDeadLetterStore
+	}
+	letters, err := c.deadLetters.List()
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, d := range letters {
+		if !d.Updated.Before(before) {
+			continue
+		}
+		if err := c.deadLetters.Delete(d.ID); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// MemoryDeadLetterStore is a DeadLetterStore that keeps dead letters in
+// memory, for tests and for processes that export them elsewhere.
+type MemoryDeadLetterStore struct {
+	mu      sync.Mutex
+	letters map[string]DeadLetter
+}
+
+// NewMemoryDeadLetterStore returns an empty MemoryDeadLetterStore.
+func NewMemoryDeadLetterStore() *MemoryDeadLetterStore {
+	return &MemoryDeadLetterStore{letters: make(map[string]DeadLetter)}
+}
+
+func (s *MemoryDeadLetterStore) Put(d DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.letters[d.ID] = d
+	return nil
+}
+
+func (s *MemoryDeadLetterStore) Get(id string) (DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.letters[id]
+	if !ok {
+		return DeadLetter{}, ErrDeadLetterNotFound
+	}
+	return d, nil
+}
+
+func (s *MemoryDeadLetterStore) List() ([]DeadLetter, error) {
+	s.mu.Lock()
+	letters := make([]DeadLetter, 0, len(s.letters))
+	for _, d := range s.letters {
+		letters = append(letters, d)
+	}
+	s.mu.Unlock()
+	sortDeadLetters(letters)
+	return letters, nil
+}
+
+func (s *MemoryDeadLetterStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.letters, id)
+	return nil
+}
+
+func sortDeadLetters(letters []DeadLetter) {
+	sort.Slice(letters, func(i, j int) bool {
+		if !letters[i].Created.Equal(letters[j].Created) {
+			return letters[i].Created.Before(letters[j].Created)
+		}
+		return letters[i].ID < letters[j].ID
+	})
+}
+
+const deadLetterFileExt = ".dead"
+
+// FileDeadLetterStore is a DeadLetterStore that keeps one JSON file per
+// dead letter in a directory, so they survive restarts and can be read
+// with ordinary tools.
+type FileDeadLetterStore struct {
+	dir string
+}
+
+// NewFileDeadLetterStore opens or creates a dead-letter store in dir.
+func NewFileDeadLetterStore(dir string) (*FileDeadLetterStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileDeadLetterStore{dir: dir}, nil
+}
+
+func (s *FileDeadLetterStore) Put(d DeadLetter) error {
+	return writeJSONFile(s.dir, hashedPath(s.dir, d.ID, deadLetterFileExt), d)
+}
+
+func (s *FileDeadLetterStore) Get(id string) (DeadLetter, error) {
+	data, err := os.ReadFile(hashedPath(s.dir, id, deadLetterFileExt))
+	if errors.Is(err, os.ErrNotExist) {
+		return DeadLetter{}, ErrDeadLetterNotFound
+	} else if err != nil {
+		return DeadLetter{}, err
+	}
+	var d DeadLetter
+	return d, json.Unmarshal(data, &d)
+}
+
+// List skips and removes entries that can't be decoded.
+func (s *FileDeadLetterStore) List() ([]DeadLetter, error) {
+	var letters []DeadLetter
+	err := readJSONDir(s.dir, deadLetterFileExt, func(data []byte) error {
+		var d DeadLetter
+		if err := json.Unmarshal(data, &d); err != nil {
+			return err
+		}
+		letters = append(letters, d)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortDeadLetters(letters)
+	return letters, nil
+}
+
+func (s *FileDeadLetterStore) Delete(id string) error {
+	return removeFile(hashedPath(s.dir, id, deadLetterFileExt))
+}
@@ -0,0 +1,182 @@
+package strict
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	redacted = "[REDACTED]"
+	// maxDebugBody caps how much of each body is written to the debug log.
+	maxDebugBody = 64 << 10
+)
+
+// defaultRedactedHeaders are always masked in debug output.
+var defaultRedactedHeaders = []string{"X-API-Key", "Authorization", "Cookie", "Set-Cookie"}
+
+// debugLogger dumps every HTTP exchange with credentials and configured
+// JSON fields masked.
+type debugLogger struct {
+	mu       sync.Mutex
+	w        io.Writer
+	fields   map[string]bool
+	redactor Redactor
+}
+
+func newDebugLogger(w io.Writer, fields []string) *debugLogger {
+	if w == nil {
+		w = os.Stderr
+	}
+	d := &debugLogger{w: w, fields: make(map[string]bool)}
+	for _, f := range fields {
+		d.fields[strings.ToLower(f)] = true
+	}
+	return d
+}
+
+func (d *debugLogger) middleware(next Handler) Handler {
+	return func(req *http.Request) (*http.Response, error) {
+		d.dumpRequest(req)
+		resp, err := next(req)
+		if err != nil {
+			d.write(fmt.Sprintf("<-- %s %s error: %v\n\n", req.Method, req.URL, err))
+			return resp, err
+		}
+		d.dumpResponse(resp)
+		return resp, nil
+	}
+}
+
+func (d *debugLogger) dumpRequest(req *http.Request) {
+	clone := req.Clone(req.Context())
+	redactHeaders(clone.Header)
+	clone.Body = nil
+	clone.ContentLength = 0
+	head, err := httputil.DumpRequestOut(clone, false)
+	if err != nil {
+		d.write(fmt.Sprintf("--> %s %s (dump failed: %v)\n\n", req.Method, req.URL, err))
+		return
+	}
+
+	var body []byte
+	if req.GetBody != nil {
+		if rc, err := req.GetBody(); err == nil {
+			body, _ = io.ReadAll(rc)
+			rc.Close()
+		}
+	}
+	d.write("--> " + string(head) + d.formatBody(req.Header, body) + "\n\n")
+}
+
+func (d *debugLogger) dumpResponse(resp *http.Response) {
+	// Event streams are consumed as they arrive; buffering one here
+	// would hold back every event until the stream ends.
+	streaming := false
+	if mt, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil && mt == "text/event-stream" {
+		streaming = true
+	}
+	var body []byte
+	var err error
+	if !streaming {
+		body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	if err != nil {
+		d.write(fmt.Sprintf("<-- %s (reading body failed: %v)\n\n", resp.Status, err))
+		return
+	}
+
+	head := *resp
+	head.Header = resp.Header.Clone()
+	redactHeaders(head.Header)
+	head.Body = http.NoBody
+	head.ContentLength = -1
+	dump, err := httputil.DumpResponse(&head, false)
+	if err != nil {
+		d.write(fmt.Sprintf("<-- %s (dump failed: %v)\n\n", resp.Status, err))
+		return
+	}
+	if streaming {
+		d.write("<-- " + string(dump) + "(event stream)\n\n")
+		return
+	}
+	d.write("<-- " + string(dump) + d.formatBody(resp.Header, body) + "\n\n")
+}
+
+func (d *debugLogger) write(s string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	io.WriteString(d.w, s)
+}
+
+// formatBody renders a body for the dump. Compressed and binary bodies
+// are summarized, since they can be neither read nor redacted.
+func (d *debugLogger) formatBody(h http.Header, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if enc := h.Get("Content-Encoding"); enc != "" && enc != "identity" {
+		return fmt.Sprintf("(%d bytes, %s-encoded)", len(body), enc)
+	}
+	if ct := h.Get("Content-Type"); ct != "" {
+		if mt, _, err := mime.ParseMediaType(ct); err == nil && !strings.HasPrefix(mt, "text/") && !strings.HasSuffix(mt, "json") {
+			return fmt.Sprintf("(%d bytes, %s)", len(body), mt)
+		}
+	}
+	return d.redactBody(body)
+}
+
+// redactBody masks configured fields in JSON bodies, then applies the
+// client's Redactor, if any.
+func (d *debugLogger) redactBody(body []byte) string {
+	if len(d.fields) > 0 {
+		var v interface{}
+		if json.Unmarshal(body, &v) == nil {
+			if out, err := json.Marshal(d.redactValue(v)); err == nil {
+				body = out
+			}
+		}
+	}
+	if d.redactor != nil {
+		body = d.redactor.Redact(body)
+	}
+	if len(body) > maxDebugBody {
+		return fmt.Sprintf("%s... (%d bytes truncated)", body[:maxDebugBody], len(body)-maxDebugBody)
+	}
+	return string(body)
+}
+
+func (d *debugLogger) redactValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if d.fields[strings.ToLower(k)] {
+				t[k] = redacted
+			} else {
+				t[k] = d.redactValue(val)
+			}
+		}
+	case []interface{}:
+		for i, val := range t {
+			t[i] = d.redactValue(val)
+		}
+	}
+	return v
+}
+
+func redactHeaders(h http.Header) {
+	for _, key := range defaultRedactedHeaders {
+		if h.Get(key) != "" {
+			h.Set(key, redacted)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package strict
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// WithRequestDeduplication collapses concurrent ProcessRequest calls with
+// identical requests into one HTTP request whose result is shared by
+// every caller. Each caller still honors its own
+// context while waiting; per-call options of the followers are ignored.
+func WithRequestDeduplication() Option {
+	return func(c *Client) {
+		c.flights = &flightGroup{m: make(map[string]*flight)}
+	}
+}
+
+// flightGroup is a minimal singleflight keyed by request identity.
+type flightGroup struct {
+	mu sync.Mutex
+	m  map[string]*flight
+}
+
+type flight struct {
+	done chan struct{}
+	out  *OutputSchema
+	err  error
+	// abandoned reports that the call failed because the leader's
+	// context ended, so the error isn't the followers' to share.
+	abandoned bool
+}
+
+// do runs fn once per key among concurrent callers. Followers wait for the
+// leader's result or for their own ctx to end. If the leader's ctx ends
+// first, a waiting follower runs fn itself rather than inherit the
+// leader's cancellation.
+func (g *flightGroup) do(ctx context.Context, key string, fn func() (*OutputSchema, error)) (*OutputSchema, error) {
+	for {
+		g.mu.Lock()
+		f, ok := g.m[key]
+		if !ok {
+			break
+		}
+		g.mu.Unlock()
+		select {
+		case <-f.done:
+			if f.abandoned && ctx.Err() == nil {
+				continue
+			}
+			return copyOutput(f.out), f.err
+		case <-ctx.Done():
+			return nil, &transportError{err: ctx.Err()}
+		}
+	}
+	f := &flight{done: make(chan struct{})}
+	g.m[key] = f
+	g.mu.Unlock()
+
+	f.out, f.err = fn()
+	f.abandoned = f.err != nil && ctx.Err() != nil
+	g.mu.Lock()
+	delete(g.m, key)
+	g.mu.Unlock()
+	close(f.done)
+	return copyOutput(f.out), f.err
+}
+
+// copyOutput gives each caller its own top-level struct. Result values are
+// shared and must be treated as read-only.
+func copyOutput(out *OutputSchema) *OutputSchema {
+	if out == nil {
+		return nil
+	}
+	cp := *out
+	cp.Validation.Errors = append([]string(nil), out.Validation.Errors...)
+	return &cp
+}
+
+// requestKey identifies req by its whole encoding, as cacheKey does, so
+// requests that differ in any field, such as DryRun, are not merged.
+func requestKey(req *ProcessingRequest, tenant string) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("strict: encode request: %w", err)
+	}
+	return cacheKey("", tenant, data), nil
+}
@@ -0,0 +1,111 @@
+package strict
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequestKey(t *testing.T) {
+	base := ProcessingRequest{InputData: "hello", ProcessorType: Local, InputTokens: 1}
+	tests := []struct {
+		name       string
+		req        ProcessingRequest
+		tenant     string
+		wantShared bool
+	}{
+		{"identical", base, "", true},
+		{"input", ProcessingRequest{InputData: "hello!", ProcessorType: Local, InputTokens: 1}, "", false},
+		{"processor", ProcessingRequest{InputData: "hello", ProcessorType: Cloud, InputTokens: 1}, "", false},
+		{"dry run", ProcessingRequest{InputData: "hello", ProcessorType: Local, InputTokens: 1, DryRun: true}, "", false},
+		{"timeout", ProcessingRequest{InputData: "hello", ProcessorType: Local, InputTokens: 1, TimeoutSeconds: 5}, "", false},
+		{"tenant", base, "acme", false},
+	}
+	want, err := requestKey(&base, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := requestKey(&tt.req, tt.tenant)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if shared := got == want; shared != tt.wantShared {
+				t.Errorf("requestKey(%+v, %q) shared = %v, want %v", tt.req, tt.tenant, shared, tt.wantShared)
+			}
+		})
+	}
+}
+
+func TestFlightGroupLeaderCanceled(t *testing.T) {
+	g := &flightGroup{m: make(map[string]*flight)}
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	var calls atomic.Int32
+	leaderErr := make(chan error)
+	go func() {
+		_, err := g.do(leaderCtx, "k", func() (*OutputSchema, error) {
+			calls.Add(1)
+			close(started)
+			<-leaderCtx.Done()
+			return nil, &transportError{err: leaderCtx.Err()}
+		})
+		leaderErr <- err
+	}()
+	<-started
+
+	followerOut := make(chan *OutputSchema)
+	go func() {
+		out, err := g.do(context.Background(), "k", func() (*OutputSchema, error) {
+			calls.Add(1)
+			return &OutputSchema{ProcessorUsed: Local}, nil
+		})
+		if err != nil {
+			t.Errorf("follower: %v", err)
+		}
+		followerOut <- out
+	}()
+	// Let the follower join the leader's flight before canceling it.
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	if err := <-leaderErr; err == nil {
+		t.Error("leader: want its context error")
+	}
+	if out := <-followerOut; out == nil || out.ProcessorUsed != Local {
+		t.Errorf("follower got %+v, want its own result", out)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("fn ran %d times, want 2", n)
+	}
+}
+
+func TestFlightGroupSharesResult(t *testing.T) {
+	g := &flightGroup{m: make(map[string]*flight)}
+	release := make(chan struct{})
+	var calls atomic.Int32
+	fn := func() (*OutputSchema, error) {
+		calls.Add(1)
+		<-release
+		return &OutputSchema{ProcessorUsed: Cloud}, nil
+	}
+	results := make(chan *OutputSchema, 3)
+	for range 3 {
+		go func() {
+			out, _ := g.do(context.Background(), "k", fn)
+			results <- out
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	for range 3 {
+		if out := <-results; out == nil || out.ProcessorUsed != Cloud {
+			t.Errorf("got %+v", out)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("fn ran %d times, want 1", n)
+	}
+}
@@ -0,0 +1,138 @@
+package strict
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DeprecationNotice is the server's warning, sent in Deprecation, Sunset
+// and Warning response headers, that an endpoint is deprecated or going
+// away.
+type DeprecationNotice struct {
+	// Method and Path identify the first endpoint the warning came from.
+	Method string
+	Path   string
+	// Deprecated is set when the server sent a Deprecation header.
+	Deprecated bool
+	// DeprecatedAt is when the endpoint was or will be deprecated, if the
+	// server said.
+	DeprecatedAt time.Time
+	// Sunset is when the endpoint will stop working, if the server said.
+	Sunset time.Time
+	// Link points to documentation about the change, if the server sent
+	// one.
+	Link string
+	// Warnings are the texts of any Warning headers.
+	Warnings []string
+}
+
+// WithDeprecationHandler calls fn with deprecation notices from the
+// server instead of logging them. Each distinct notice is reported once
+// per client, however many endpoints send it, from the goroutine making
+// the call.
+func WithDeprecationHandler(fn func(DeprecationNotice)) Option {
+	return func(c *Client) {
+		c.onDeprecation = fn
+	}
+}
+
+// noteDeprecation reports a deprecation notice in h, if there is one and
+// it hasn't been reported before.
+func (c *Client) noteDeprecation(ctx context.Context, cl *call, h http.Header) {
+	dep, sunset, warnings := h.Get("Deprecation"), h.Get("Sunset"), h.Values("Warning")
+	if dep == "" && sunset == "" && len(warnings) == 0 {
+		return
+	}
+	// Notices are keyed by their content, not the endpoint, so paths
+	// with IDs in them don't each get their own.
+	key := strings.Join(append([]string{dep, sunset}, warnings...), "\x00")
+	if _, seen := c.deprecations.LoadOrStore(key, true); seen {
+		return
+	}
+
+	path, _, _ := strings.Cut(cl.path, "?")
+	n := DeprecationNotice{Method: cl.method, Path: path, Deprecated: dep != ""}
+	n.DeprecatedAt = parseDeprecationDate(dep)
+	n.Sunset, _ = http.ParseTime(strings.TrimSpace(sunset))
+	n.Link = linkWithRel(h.Values("Link"), "deprecation", "sunset")
+	for _, w := range warnings {
+		n.Warnings = append(n.Warnings, warningText(w))
+	}
+
+	if c.onDeprecation != nil {
+		c.onDeprecation(n)
+		return
+	}
+	args := []any{
+		slog.String("method", n.Method),
+		slog.String("path", n.Path),
+	}
+	if !n.DeprecatedAt.IsZero() {
+		args = append(args, slog.Time("deprecated_at", n.DeprecatedAt))
+	}
+	if !n.Sunset.IsZero() {
+		args = append(args, slog.Time("sunset", n.Sunset))
+	}
+	if n.Link != "" {
+		args = append(args, slog.String("link", n.Link))
+	}
+	if len(n.Warnings) > 0 {
+		args = append(args, slog.String("warning", strings.Join(n.Warnings, "; ")))
+	}
+	c.log(ctx, slog.LevelWarn, "strict: server deprecation notice", args...)
+}
+
+// parseDeprecationDate parses a Deprecation header, either "@<unix
+// seconds>" (RFC 9745) or an HTTP date. "true", from earlier drafts,
+// carries no date.
+func parseDeprecationDate(v string) time.Time {
+	v = strings.TrimSpace(v)
+	if secs, ok := strings.CutPrefix(v, "@"); ok {
+		if n, err := strconv.ParseInt(secs, 10, 64); err == nil {
+			return time.Unix(n, 0)
+		}
+		return time.Time{}
+	}
+	t, _ := http.ParseTime(v)
+	return t
+}
+
+// linkWithRel returns the target of the first Link header entry with one
+// of rels.
+func linkWithRel(links []string, rels ...string) string {
+	for _, header := range links {
+		for _, entry := range strings.Split(header, ",") {
+			target, params, ok := strings.Cut(entry, ";")
+			if !ok {
+				continue
+			}
+			for _, p := range strings.Split(params, ";") {
+				name, value, _ := strings.Cut(strings.TrimSpace(p), "=")
+				if !strings.EqualFold(name, "rel") {
+					continue
+				}
+				for _, rel := range rels {
+					if strings.EqualFold(strings.Trim(value, `"`), rel) {
+						return strings.Trim(strings.TrimSpace(target), "<>")
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// warningText extracts the quoted text from a Warning header such as
+// `299 - "Deprecated API"`, or returns the header as is.
+func warningText(w string) string {
+	if i := strings.IndexByte(w, '"'); i >= 0 {
+		if j := strings.IndexByte(w[i+1:], '"'); j >= 0 {
+			return w[i+1 : i+1+j]
+		}
+	}
+	return strings.TrimSpace(w)
+}
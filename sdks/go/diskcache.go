@@ -0,0 +1,155 @@
+package strict
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const diskCacheExt = ".cache"
+
+// DiskCache is a Cache that stores one file per entry in a directory, so
+// cached responses survive process restarts. When the directory grows
+// past its size cap, the least recently used entries are evicted.
+type DiskCache struct {
+	dir      string
+	maxBytes int64
+
+	mu   sync.Mutex
+	size int64
+}
+
+// NewDiskCache opens or creates a cache in dir holding at most maxBytes of
+// entries. A maxBytes of zero or less means 100 MiB.
+func NewDiskCache(dir string, maxBytes int64) (*DiskCache, error) {
+	if maxBytes <= 0 {
+		maxBytes = 100 << 20
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	d := &DiskCache{dir: dir, maxBytes: maxBytes}
+	files, err := d.files()
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		d.size += f.size
+	}
+	return d, nil
+}
+
+func (d *DiskCache) path(key string) string {
+	return filepath.Join(d.dir, key+diskCacheExt)
+}
+
+func (d *DiskCache) Get(key string) (CacheEntry, bool) {
+	data, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return CacheEntry{}, false
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		d.Delete(key)
+		return CacheEntry{}, false
+	}
+	// The modification time doubles as the last-use time for eviction.
+	now := time.Now()
+	os.Chtimes(d.path(key), now, now)
+	return entry, true
+}
+
+func (d *DiskCache) Set(key string, entry CacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(d.dir, "tmp-*")
+	if err != nil {
+		return
+	}
+	_, werr := tmp.Write(data)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	path := d.path(key)
+	if fi, err := os.Stat(path); err == nil {
+		d.size -= fi.Size()
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	d.size += int64(len(data))
+	if d.size > d.maxBytes {
+		d.evict()
+	}
+}
+
+func (d *DiskCache) Delete(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	path := d.path(key)
+	if fi, err := os.Stat(path); err == nil {
+		if os.Remove(path) == nil {
+			d.size -= fi.Size()
+		}
+	}
+}
+
+// evict removes least recently used entries until the cache is at 90% of
+// its cap, leaving headroom so every Set doesn't trigger a scan.
+func (d *DiskCache) evict() {
+	files, err := d.files()
+	if err != nil {
+		return
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	target := d.maxBytes * 9 / 10
+	for _, f := range files {
+		if d.size <= target {
+			break
+		}
+		if os.Remove(f.path) == nil {
+			d.size -= f.size
+		}
+	}
+}
+
+type cacheFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+func (d *DiskCache) files() ([]cacheFile, error) {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []cacheFile
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), diskCacheExt) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cacheFile{
+			path:    filepath.Join(d.dir, e.Name()),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+	}
+	return files, nil
+}
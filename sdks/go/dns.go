@@ -0,0 +1,144 @@
+package strict
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// DNSCacheConfig configures the client's caching resolver.
+type DNSCacheConfig struct {
+	// TTL is how long a lookup is served without refreshing. Defaults to
+	// one minute.
+	TTL time.Duration
+	// Resolver performs the lookups. Defaults to net.DefaultResolver.
+	Resolver *net.Resolver
+}
+
+// WithDNSCache caches host lookups for the default transport. Once an
+// entry's TTL expires it keeps being served while a background lookup
+// refreshes it, so requests never wait on the resolver for a known host,
+// and a failed refresh leaves the last good addresses in place.
+func WithDNSCache(cfg DNSCacheConfig) Option {
+	return func(c *Client) {
+		c.dnsCache = newDNSCache(cfg)
+	}
+}
+
+type dnsCache struct {
+	resolver *net.Resolver
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*dnsEntry
+}
+
+type dnsEntry struct {
+	addrs      []string
+	expires    time.Time
+	refreshing bool
+}
+
+func newDNSCache(cfg DNSCacheConfig) *dnsCache {
+	if cfg.TTL <= 0 {
+		cfg.TTL = time.Minute
+	}
+	if cfg.Resolver == nil {
+		cfg.Resolver = net.DefaultResolver
+	}
+	return &dnsCache{
+		resolver: cfg.Resolver,
+		ttl:      cfg.TTL,
+		entries:  make(map[string]*dnsEntry),
+	}
+}
+
+func (d *dnsCache) lookup(ctx context.Context, host string) ([]string, error) {
+	d.mu.Lock()
+	e, ok := d.entries[host]
+	if ok {
+		addrs := e.addrs
+		if time.Now().After(e.expires) && !e.refreshing {
+			e.refreshing = true
+			go d.refresh(host)
+		}
+		d.mu.Unlock()
+		return addrs, nil
+	}
+	d.mu.Unlock()
+
+	addrs, err := d.resolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	d.store(host, addrs)
+	return addrs, nil
+}
+
+func (d *dnsCache) refresh(host string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	addrs, err := d.resolver.LookupHost(ctx, host)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e := d.entries[host]
+	e.refreshing = false
+	if err != nil || len(addrs) == 0 {
+		return
+	}
+	e.addrs = addrs
+	e.expires = time.Now().Add(d.ttl)
+}
+
+func (d *dnsCache) store(host string, addrs []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries[host] = &dnsEntry{addrs: addrs, expires: time.Now().Add(d.ttl)}
+}
+
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// dialer resolves hosts through the cache and tries each address in turn.
+func (d *dnsCache) dialer(dial dialFunc) dialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || net.ParseIP(host) != nil {
+			return dial(ctx, network, addr)
+		}
+		addrs, err := d.lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		var errs []error
+		for _, ip := range addrs {
+			conn, err := dial(ctx, network, net.JoinHostPort(ip, port))
+			if err == nil {
+				return conn, nil
+			}
+			errs = append(errs, err)
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		return nil, errors.Join(errs...)
+	}
+}
+
+// configureDNSCache routes the default transport's dials through the cache.
+func (c *Client) configureDNSCache() {
+	if c.dnsCache == nil || c.unixSocket != "" {
+		return
+	}
+	dial := c.httpTransport.DialContext
+	if dial == nil {
+		dial = defaultDialer().DialContext
+	}
+	c.httpTransport.DialContext = c.dnsCache.dialer(dial)
+}
+
+func defaultDialer() *net.Dialer {
+	return &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+}
@@ -0,0 +1,231 @@
+package strict
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultEndpointCooldown = 30 * time.Second
+
+// EndpointStatus reports the health of one configured base URL.
+type EndpointStatus struct {
+	URL     string
+	Healthy bool
+	// ConsecutiveFailures counts connection errors and 5xx responses since
+	// the last success.
+	ConsecutiveFailures int
+	// DownUntil is when an unhealthy endpoint becomes eligible again.
+	DownUntil time.Time
+	// Weight is the endpoint's relative share for weighted balancing.
+	Weight int
+	// Latency is a moving average of successful attempt latency, or zero
+	// if none has been measured yet.
+	Latency time.Duration
+}
+
+// Endpoint is a base URL with a relative weight for weighted balancing.
+// A Weight below 1 counts as 1.
+type Endpoint struct {
+	URL    string
+	Weight int
+}
+
+// WithBaseURLs configures a primary base URL followed by fallbacks. On a
+// connection error or 5xx response the request is resent to the next
+// healthy endpoint. A failed endpoint is skipped for the cooldown set by
+// WithEndpointCooldown, after which traffic fails back to it.
+func WithBaseURLs(urls ...string) Option {
+	return func(c *Client) {
+		if len(urls) == 0 {
+			return
+		}
+		eps := make([]Endpoint, len(urls))
+		for i, u := range urls {
+			eps[i] = Endpoint{URL: u}
+		}
+		WithEndpoints(eps...)(c)
+	}
+}
+
+// WithEndpoints is like WithBaseURLs but lets each endpoint carry a weight
+// for WeightedBalancer.
+func WithEndpoints(eps ...Endpoint) Option {
+	return func(c *Client) {
+		if len(eps) == 0 {
+			return
+		}
+		c.BaseURL = eps[0].URL
+		c.endpoints = newEndpointSet(eps, c.endpointCooldown(), c.balancer)
+	}
+}
+
+// WithBalancer sets how requests are spread across the endpoints given to
+// WithBaseURLs or WithEndpoints. The default sends everything to the
+// first healthy endpoint in configured order.
+func WithBalancer(b Balancer) Option {
+	return func(c *Client) {
+		c.balancer = b
+		if c.endpoints != nil {
+			c.endpoints.balancer = b
+		}
+	}
+}
+
+// WithEndpointCooldown sets how long a failed endpoint is skipped. Defaults
+// to 30 seconds.
+func WithEndpointCooldown(d time.Duration) Option {
+	return func(c *Client) {
+		c.cooldown = d
+		if c.endpoints != nil {
+			c.endpoints.cooldown = d
+		}
+	}
+}
+
+func (c *Client) endpointCooldown() time.Duration {
+	if c.cooldown > 0 {
+		return c.cooldown
+	}
+	return defaultEndpointCooldown
+}
+
+// Endpoints reports the health of each configured base URL.
+func (c *Client) Endpoints() []EndpointStatus {
+	if c.endpoints == nil {
+		return []EndpointStatus{{URL: c.BaseURL, Healthy: true}}
+	}
+	return c.endpoints.status()
+}
+
+type endpoint struct {
+	url       string
+	weight    int
+	failures  int
+	downUntil time.Time
+	latency   time.Duration
+}
+
+// latencyWeight is the smoothing factor of the latency moving average.
+const latencyWeight = 0.3
+
+type endpointSet struct {
+	cooldown time.Duration
+	balancer Balancer
+
+	mu  sync.Mutex
+	eps []*endpoint
+}
+
+func newEndpointSet(eps []Endpoint, cooldown time.Duration, b Balancer) *endpointSet {
+	s := &endpointSet{cooldown: cooldown, balancer: b}
+	for _, e := range eps {
+		w := e.Weight
+		if w < 1 {
+			w = 1
+		}
+		s.eps = append(s.eps, &endpoint{url: strings.TrimSuffix(e.URL, "/"), weight: w})
+	}
+	return s
+}
+
+// order returns endpoints to try: the balancer's pick, the remaining
+// healthy ones in configured order, then unhealthy ones so a request is
+// still attempted when all are down.
+func (s *endpointSet) order() []*endpoint {
+	s.mu.Lock()
+	now := time.Now()
+	healthy := make([]*endpoint, 0, len(s.eps))
+	var down []*endpoint
+	for _, ep := range s.eps {
+		if now.Before(ep.downUntil) {
+			down = append(down, ep)
+		} else {
+			healthy = append(healthy, ep)
+		}
+	}
+	var candidates []EndpointStatus
+	if s.balancer != nil && len(healthy) > 1 {
+		candidates = make([]EndpointStatus, len(healthy))
+		for i, ep := range healthy {
+			candidates[i] = ep.status(now)
+		}
+	}
+	s.mu.Unlock()
+
+	if candidates != nil {
+		if i := s.balancer.Pick(candidates); i > 0 && i < len(healthy) {
+			picked := healthy[i]
+			copy(healthy[1:i+1], healthy[:i])
+			healthy[0] = picked
+		}
+	}
+	return append(healthy, down...)
+}
+
+func (s *endpointSet) report(ep *endpoint, failed bool, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !failed {
+		ep.failures = 0
+		ep.downUntil = time.Time{}
+		if ep.latency == 0 {
+			ep.latency = latency
+		} else {
+			ep.latency = time.Duration(latencyWeight*float64(latency) + (1-latencyWeight)*float64(ep.latency))
+		}
+		return
+	}
+	ep.failures++
+	ep.downUntil = time.Now().Add(s.cooldown)
+}
+
+func (s *endpointSet) status() []EndpointStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	out := make([]EndpointStatus, len(s.eps))
+	for i, ep := range s.eps {
+		out[i] = ep.status(now)
+	}
+	return out
+}
+
+func (ep *endpoint) status(now time.Time) EndpointStatus {
+	return EndpointStatus{
+		URL:                 ep.url,
+		Healthy:             !now.Before(ep.downUntil),
+		ConsecutiveFailures: ep.failures,
+		DownUntil:           ep.downUntil,
+		Weight:              ep.weight,
+		Latency:             ep.latency,
+	}
+}
+
+// send performs one attempt, failing over across endpoints when several
+// are configured.
+func (c *Client) send(ctx context.Context, cl *call) error {
+	// Streamed bodies can't be sent twice, probes want one answer, and a
+	// kept response body would outlive the hedge that produced it.
+	if c.hedge != nil && !cl.singleAttempt() && !cl.keepBody {
+		return c.sendHedged(ctx, cl)
+	}
+	if c.endpoints == nil {
+		return c.attempt(ctx, cl, c.baseURL())
+	}
+	var err error
+	for _, ep := range c.endpoints.order() {
+		start := time.Now()
+		err = c.attempt(ctx, cl, ep.url)
+		if ctx.Err() != nil {
+			return err
+		}
+		failed := Classify(err) == ErrorClassTransient
+		c.endpoints.report(ep, failed, time.Since(start))
+		if !failed || cl.singleAttempt() {
+			return err
+		}
+	}
+	return err
+}
@@ -0,0 +1,117 @@
+package strict
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// The server's processor and signal types are open-ended: deployments
+// can add their own. Register custom values so the SDK treats them as
+// known; values the server sends that were never registered still
+// decode, and Known reports them.
+var (
+	enumMu          sync.RWMutex
+	customProcessor = map[ProcessorType]bool{}
+	customSignal    = map[SignalType]bool{}
+)
+
+// RegisterProcessorType adds a custom processor type. Names are 1 to 64
+// characters of lowercase letters, digits, '-' and '_'.
+func RegisterProcessorType(p ProcessorType) error {
+	if err := checkEnumName(string(p)); err != nil {
+		return fmt.Errorf("strict: RegisterProcessorType: %w", err)
+	}
+	enumMu.Lock()
+	customProcessor[p] = true
+	enumMu.Unlock()
+	return nil
+}
+
+// RegisterSignalType adds a custom signal type, with the same naming
+// rules as RegisterProcessorType.
+func RegisterSignalType(s SignalType) error {
+	if err := checkEnumName(string(s)); err != nil {
+		return fmt.Errorf("strict: RegisterSignalType: %w", err)
+	}
+	enumMu.Lock()
+	customSignal[s] = true
+	enumMu.Unlock()
+	return nil
+}
+
+func checkEnumName(name string) error {
+	if name == "" || len(name) > 64 {
+		return fmt.Errorf("name %q must be 1 to 64 characters", name)
+	}
+	for _, r := range name {
+		if !('a' <= r && r <= 'z' || '0' <= r && r <= '9' || r == '-' || r == '_') {
+			return fmt.Errorf("name %q contains %q", name, r)
+		}
+	}
+	return nil
+}
+
+// Known reports whether p is a built-in or registered processor type.
+func (p ProcessorType) Known() bool {
+	switch p {
+	case Cloud, Local, HybridProc:
+		return true
+	}
+	enumMu.RLock()
+	defer enumMu.RUnlock()
+	return customProcessor[p]
+}
+
+// Known reports whether s is a built-in or registered signal type.
+func (s SignalType) Known() bool {
+	switch s {
+	case Analog, Digital, Hybrid:
+		return true
+	}
+	enumMu.RLock()
+	defer enumMu.RUnlock()
+	return customSignal[s]
+}
+
+// ProcessorTypes returns the built-in and registered processor types,
+// sorted.
+func ProcessorTypes() []ProcessorType {
+	enumMu.RLock()
+	defer enumMu.RUnlock()
+	ps := []ProcessorType{Cloud, Local, HybridProc}
+	for p := range customProcessor {
+		ps = append(ps, p)
+	}
+	sort.Slice(ps, func(i, j int) bool { return ps[i] < ps[j] })
+	return ps
+}
+
+// UnmarshalJSON accepts any string, known or not, so a server running
+// newer processors doesn't break decoding; check Known. Anything other
+// than a string or null is an error.
+func (p *ProcessorType) UnmarshalJSON(data []byte) error {
+	s, err := unmarshalEnum(data, "processor type")
+	if err == nil && s != nil {
+		*p = ProcessorType(*s)
+	}
+	return err
+}
+
+// UnmarshalJSON behaves like ProcessorType.UnmarshalJSON.
+func (s *SignalType) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalEnum(data, "signal type")
+	if err == nil && v != nil {
+		*s = SignalType(*v)
+	}
+	return err
+}
+
+func unmarshalEnum(data []byte, what string) (*string, error) {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("strict: %s must be a string, got %s", what, data)
+	}
+	return s, nil
+}
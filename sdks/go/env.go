@@ -0,0 +1,148 @@
+package strict
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables read by NewClientFromEnv.
+const (
+	// EnvBaseURL is the API base URL, or a comma-separated list of base
+	// URLs to fail over between as with WithBaseURLs.
+	EnvBaseURL = "STRICT_BASE_URL"
+	EnvAPIKey  = "STRICT_API_KEY"
+	// EnvTimeout is the per-attempt timeout, as a Go duration ("45s") or
+	// a number of seconds.
+	EnvTimeout = "STRICT_TIMEOUT"
+	EnvProxy   = "STRICT_PROXY"
+	// EnvMaxRetries enables DefaultRetryPolicy with this many retries
+	// after the first attempt.
+	EnvMaxRetries      = "STRICT_MAX_RETRIES"
+	EnvAPIVersion      = "STRICT_API_VERSION"
+	EnvTenant          = "STRICT_TENANT"
+	EnvCACertFile      = "STRICT_CA_CERT_FILE"
+	EnvUserAgentSuffix = "STRICT_USER_AGENT_SUFFIX"
+	// EnvDebug enables WithDebug, writing to stderr, when set to a true
+	// value such as "1" or "true".
+	EnvDebug = "STRICT_DEBUG"
+	// EnvProfile names the config file profile to use; see Profile.
+	EnvProfile = "STRICT_PROFILE"
+	// EnvConfigFile overrides the config file location, ~/.strict/config.
+	EnvConfigFile = "STRICT_CONFIG_FILE"
+)
+
+// NewClientFromEnv returns a Client configured from the config file
+// profile named by STRICT_PROFILE and the STRICT_* environment variables
+// listed above. Without STRICT_PROFILE the "default" profile is used if
+// the config file has one. Unset or empty variables leave the profile's
+// settings, or the SDK defaults, in place. opts are applied last, so an
+// explicit option wins over a variable, a variable over the profile, and
+// the profile over a default.
+//
+// It fails if the profile or a variable can't be parsed, if an option is
+// invalid, or if no base URL is set by any of them.
+func NewClientFromEnv(opts ...Option) (*Client, error) {
+	all, err := envProfileOptions()
+	if err != nil {
+		return nil, err
+	}
+	envOpts, err := optionsFromEnv(os.Getenv)
+	if err != nil {
+		return nil, err
+	}
+	if urls := splitList(os.Getenv(EnvBaseURL)); len(urls) > 0 {
+		all = append(all, withBaseURLs(urls))
+	}
+	if key := os.Getenv(EnvAPIKey); key != "" {
+		all = append(all, withAPIKey(key))
+	}
+	all = append(append(all, envOpts...), opts...)
+	c := NewClient("", "", all...)
+	if c.configErr != nil {
+		return nil, c.configErr
+	}
+	if c.BaseURL == "" {
+		return nil, fmt.Errorf("strict: no base URL: set %s or a profile base_url", EnvBaseURL)
+	}
+	return c, nil
+}
+
+// optionsFromEnv returns the options the environment asks for, reading
+// variables with getenv.
+func optionsFromEnv(getenv func(string) string) ([]Option, error) {
+	var opts []Option
+	if v := getenv(EnvTimeout); v != "" {
+		d, err := parseEnvDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("strict: %s: %w", EnvTimeout, err)
+		}
+		opts = append(opts, WithTimeout(d))
+	}
+	if v := getenv(EnvProxy); v != "" {
+		opts = append(opts, WithProxy(v))
+	}
+	if v := getenv(EnvMaxRetries); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("strict: %s: want a non-negative integer, got %q", EnvMaxRetries, v)
+		}
+		p := DefaultRetryPolicy()
+		p.MaxAttempts = n + 1
+		opts = append(opts, WithRetryPolicy(p))
+	}
+	if v := getenv(EnvAPIVersion); v != "" {
+		opts = append(opts, WithAPIVersion(v))
+	}
+	if v := getenv(EnvTenant); v != "" {
+		opts = append(opts, WithTenant(v))
+	}
+	if v := getenv(EnvCACertFile); v != "" {
+		opts = append(opts, WithCACertFile(v))
+	}
+	if v := getenv(EnvUserAgentSuffix); v != "" {
+		opts = append(opts, WithUserAgentSuffix(v))
+	}
+	if v := getenv(EnvDebug); v != "" {
+		on, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("strict: %s: %w", EnvDebug, err)
+		}
+		if on {
+			opts = append(opts, WithDebug(nil))
+		}
+	}
+	return opts, nil
+}
+
+// parseEnvDuration parses a Go duration or a number of seconds.
+func parseEnvDuration(v string) (time.Duration, error) {
+	if secs, err := strconv.ParseFloat(v, 64); err == nil {
+		if secs < 0 {
+			return 0, errors.New("negative duration")
+		}
+		return time.Duration(secs * float64(time.Second)), nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("want a duration such as 30s, got %q", v)
+	}
+	if d < 0 {
+		return 0, errors.New("negative duration")
+	}
+	return d, nil
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(v string) []string {
+	var out []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,163 @@
+package strict
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// envelopePrefix marks an encrypted string. The rest is the wrapped data
+// key, nonce, and AES-GCM ciphertext, base64url-encoded and separated by
+// dots.
+const envelopePrefix = "strict-enc:v1:"
+
+// envelopeAAD binds ciphertexts to the envelope format version.
+var envelopeAAD = []byte("strict-enc:v1")
+
+// KeyWrapper encrypts and decrypts data keys, typically by calling a KMS
+// such as AWS KMS, Google Cloud KMS or Vault Transit. It must be safe for
+// concurrent use.
+type KeyWrapper interface {
+	WrapKey(ctx context.Context, dataKey []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// ErrEncryptionUnsupported is returned by calls that can't encrypt their
+// input, such as streamed uploads, on a client with envelope encryption.
+var ErrEncryptionUnsupported = errors.New("strict: call does not support envelope encryption")
+
+// WithEnvelopeEncryption encrypts InputData before it leaves the client,
+// so it is opaque to proxies and gateways. Each request gets a fresh
+// AES-256 data key, wrapped by kms and sent alongside the AES-GCM
+// ciphertext; the server unwraps it with the same KMS key. Requests carry
+// X-Payload-Encryption: strict-enc-v1.
+//
+// Results the server returns as an encrypted string are decrypted before
+// the call returns, so Result, RawResult and DecodeResult see plaintext.
+// A response cache (see WithCache) stores results as the server sent
+// them, so encrypted results stay encrypted at rest and are decrypted on
+// each cache hit.
+//
+// ProcessRequest, ProcessRequestWithProgress, SubmitJob, ProcessBatch and
+// ValidateBatch encrypt their input. Streamed calls (ProcessStream,
+// ProcessUpload and ProcessRequestStreamResult) fail with
+// ErrEncryptionUnsupported, and so does strictws.Dial. The strictgrpc
+// transport doesn't use it.
+func WithEnvelopeEncryption(kms KeyWrapper) Option {
+	return func(c *Client) {
+		if kms == nil {
+			c.setConfigErr(errors.New("strict: WithEnvelopeEncryption: nil key wrapper"))
+			return
+		}
+		c.envelope = kms
+		c.headers.Set("X-Payload-Encryption", "strict-enc-v1")
+	}
+}
+
+// Encrypted reports whether the client was built with
+// WithEnvelopeEncryption.
+func (c *Client) Encrypted() bool { return c.envelope != nil }
+
+// sealRequest returns req with its InputData encrypted, or req itself if
+// envelope encryption is off.
+func (c *Client) sealRequest(ctx context.Context, req *ProcessingRequest) (*ProcessingRequest, error) {
+	if c.envelope == nil {
+		return req, nil
+	}
+	sealed := *req
+	var err error
+	if sealed.InputData, err = sealEnvelope(ctx, c.envelope, []byte(req.InputData)); err != nil {
+		return nil, err
+	}
+	return &sealed, nil
+}
+
+// openOutput decrypts out.Result if the server returned it encrypted.
+func (c *Client) openOutput(ctx context.Context, out *OutputSchema) error {
+	if c.envelope == nil {
+		return nil
+	}
+	raw, ok := out.Result.(json.RawMessage)
+	if !ok {
+		return nil
+	}
+	var s string
+	if json.Unmarshal(raw, &s) != nil || !strings.HasPrefix(s, envelopePrefix) {
+		return nil
+	}
+	plain, err := openEnvelope(ctx, c.envelope, s)
+	if err != nil {
+		return err
+	}
+	if !json.Valid(plain) {
+		return errors.New("strict: decrypt result: plaintext is not JSON")
+	}
+	out.Result = json.RawMessage(plain)
+	return nil
+}
+
+func sealEnvelope(ctx context.Context, kms KeyWrapper, plaintext []byte) (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("strict: encrypt input: %w", err)
+	}
+	wrapped, err := kms.WrapKey(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("strict: encrypt input: wrap key: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", fmt.Errorf("strict: encrypt input: %w", err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("strict: encrypt input: %w", err)
+	}
+	enc := base64.RawURLEncoding
+	return envelopePrefix + enc.EncodeToString(wrapped) + "." + enc.EncodeToString(nonce) + "." +
+		enc.EncodeToString(gcm.Seal(nil, nonce, plaintext, envelopeAAD)), nil
+}
+
+func openEnvelope(ctx context.Context, kms KeyWrapper, s string) ([]byte, error) {
+	parts := strings.Split(strings.TrimPrefix(s, envelopePrefix), ".")
+	if len(parts) != 3 {
+		return nil, errors.New("strict: decrypt result: malformed envelope")
+	}
+	var fields [3][]byte
+	for i, p := range parts {
+		var err error
+		if fields[i], err = base64.RawURLEncoding.DecodeString(p); err != nil {
+			return nil, errors.New("strict: decrypt result: malformed envelope")
+		}
+	}
+	key, err := kms.UnwrapKey(ctx, fields[0])
+	if err != nil {
+		return nil, fmt.Errorf("strict: decrypt result: unwrap key: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, fmt.Errorf("strict: decrypt result: %w", err)
+	}
+	if len(fields[1]) != gcm.NonceSize() {
+		return nil, errors.New("strict: decrypt result: malformed envelope")
+	}
+	plain, err := gcm.Open(nil, fields[1], fields[2], envelopeAAD)
+	if err != nil {
+		return nil, fmt.Errorf("strict: decrypt result: %w", err)
+	}
+	return plain, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
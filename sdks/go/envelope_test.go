@@ -0,0 +1,195 @@
+package strict
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// xorWrapper is a toy KMS for tests.
+type xorWrapper byte
+
+func (w xorWrapper) WrapKey(_ context.Context, k []byte) ([]byte, error) {
+	out := make([]byte, len(k))
+	for i := range k {
+		out[i] = k[i] ^ byte(w)
+	}
+	return out, nil
+}
+
+func (w xorWrapper) UnwrapKey(ctx context.Context, k []byte) ([]byte, error) {
+	return w.WrapKey(ctx, k)
+}
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	for _, plain := range []string{"", "x", "secret input", strings.Repeat("long ", 10000), "café \U0001F600"} {
+		sealed, err := sealEnvelope(ctx, xorWrapper(0x5a), []byte(plain))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(sealed, envelopePrefix) || (len(plain) > 5 && strings.Contains(sealed, plain)) {
+			t.Fatalf("sealed %q = %q", plain, sealed)
+		}
+		got, err := openEnvelope(ctx, xorWrapper(0x5a), sealed)
+		if err != nil || string(got) != plain {
+			t.Errorf("openEnvelope() = %q, %v; want %q", got, err, plain)
+		}
+	}
+}
+
+func TestOpenEnvelopeRejects(t *testing.T) {
+	ctx := context.Background()
+	sealed, err := sealEnvelope(ctx, xorWrapper(0x5a), []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(strings.TrimPrefix(sealed, envelopePrefix), ".")
+	flip := func(s string) string {
+		b, _ := base64.RawURLEncoding.DecodeString(s)
+		b[len(b)-1] ^= 1
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	tests := []struct {
+		name string
+		kms  KeyWrapper
+		s    string
+	}{
+		{"wrong key", xorWrapper(0x33), sealed},
+		{"tampered ciphertext", xorWrapper(0x5a), envelopePrefix + parts[0] + "." + parts[1] + "." + flip(parts[2])},
+		{"tampered nonce", xorWrapper(0x5a), envelopePrefix + parts[0] + "." + flip(parts[1]) + "." + parts[2]},
+		{"short nonce", xorWrapper(0x5a), envelopePrefix + parts[0] + ".AAAA." + parts[2]},
+		{"missing part", xorWrapper(0x5a), envelopePrefix + parts[0] + "." + parts[1]},
+		{"extra part", xorWrapper(0x5a), sealed + ".x"},
+		{"bad base64", xorWrapper(0x5a), envelopePrefix + "!!." + parts[1] + "." + parts[2]},
+		{"bad key size", xorWrapper(0x5a), envelopePrefix + "AAAA." + parts[1] + "." + parts[2]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := openEnvelope(ctx, tt.kms, tt.s); err == nil {
+				t.Errorf("openEnvelope() = %q, want error", got)
+			}
+		})
+	}
+}
+
+func TestOpenOutput(t *testing.T) {
+	ctx := context.Background()
+	kms := xorWrapper(0x5a)
+	sealedJSON, _ := sealEnvelope(ctx, kms, []byte(`{"answer":42}`))
+	sealedText, _ := sealEnvelope(ctx, kms, []byte(`not json`))
+	quote := func(s string) json.RawMessage { b, _ := json.Marshal(s); return b }
+	tests := []struct {
+		name    string
+		result  interface{}
+		want    string
+		wantErr bool
+	}{
+		{"sealed", quote(sealedJSON), `{"answer":42}`, false},
+		{"plain object", json.RawMessage(`{"a":1}`), `{"a":1}`, false},
+		{"plain string", quote("hello"), `"hello"`, false},
+		{"not raw", 42, "", false},
+		{"sealed non-json", quote(sealedText), "", true},
+	}
+	c := NewClient("http://unused", "k", WithEnvelopeEncryption(kms))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &OutputSchema{Result: tt.result}
+			err := c.openOutput(ctx, out)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("openOutput() = %v, want error %v", err, tt.wantErr)
+			}
+			if raw, ok := out.Result.(json.RawMessage); ok && !tt.wantErr && string(raw) != tt.want {
+				t.Errorf("Result = %s, want %s", raw, tt.want)
+			}
+		})
+	}
+}
+
+// envelopeServer checks that input arrives encrypted and answers with an
+// encrypted result.
+func envelopeServer(t *testing.T, kms KeyWrapper, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		if bytes.Contains(body, []byte("secret input")) || r.Header.Get("X-Payload-Encryption") != "strict-enc-v1" {
+			t.Error("input sent in the clear")
+		}
+		var req struct {
+			InputData string `json:"input_data"`
+		}
+		json.Unmarshal(body, &req)
+		if plain, err := openEnvelope(r.Context(), kms, req.InputData); err != nil || string(plain) != "secret input" {
+			t.Errorf("server could not open input: %q, %v", plain, err)
+		}
+		enc, _ := sealEnvelope(r.Context(), kms, []byte(`{"answer":42}`))
+		res, _ := json.Marshal(enc)
+		w.Write([]byte(`{"result":` + string(res) + `}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestEnvelopeEncryption(t *testing.T) {
+	var hits atomic.Int32
+	kms := xorWrapper(0x5a)
+	srv := envelopeServer(t, kms, &hits)
+	c := NewClient(srv.URL, "k", WithEnvelopeEncryption(kms))
+	out, err := c.ProcessRequest(context.Background(), ProcessingRequest{InputData: "secret input"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct{ Answer int }
+	if err := out.DecodeResult(&got); err != nil || got.Answer != 42 {
+		t.Fatalf("DecodeResult() = %+v, %v", got, err)
+	}
+	if _, err := c.ProcessStream(context.Background(), strings.NewReader("x")); !errors.Is(err, ErrEncryptionUnsupported) {
+		t.Fatalf("ProcessStream() = %v, want ErrEncryptionUnsupported", err)
+	}
+	if !c.Encrypted() || NewClient(srv.URL, "k").Encrypted() {
+		t.Error("Encrypted() doesn't match the options")
+	}
+	if NewClient(srv.URL, "k", WithEnvelopeEncryption(nil)).configErr == nil {
+		t.Error("nil key wrapper accepted")
+	}
+}
+
+func TestEnvelopeEncryptionCache(t *testing.T) {
+	var hits atomic.Int32
+	kms := xorWrapper(0x5a)
+	srv := envelopeServer(t, kms, &hits)
+	cache := NewMemoryCache(0)
+	c := NewClient(srv.URL, "k", WithEnvelopeEncryption(kms), WithCache(CacheConfig{Backend: cache}))
+	ctx := context.Background()
+	req := ProcessingRequest{InputData: "secret input"}
+	for i := 0; i < 2; i++ {
+		out, err := c.ProcessRequest(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if raw, _ := out.RawResult(); string(raw) != `{"answer":42}` {
+			t.Fatalf("call %d: result = %s", i, raw)
+		}
+	}
+	if n := hits.Load(); n != 1 {
+		t.Fatalf("server hit %d times, want 1", n)
+	}
+	// The result is cached as the server sent it: encrypted.
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	for _, el := range cache.items {
+		v := el.Value.(*memoryItem).entry.Value
+		if bytes.Contains(v, []byte("answer")) || !bytes.Contains(v, []byte(envelopePrefix)) {
+			t.Errorf("cached entry holds plaintext: %s", v)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package strict
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors for use with errors.Is. Errors returned by the client
+// match at most one of these, and still unwrap to their underlying cause.
+var (
+	ErrUnauthorized      = errors.New("strict: unauthorized")
+	ErrRateLimited       = errors.New("strict: rate limited")
+	ErrValidationFailed  = errors.New("strict: validation failed")
+	ErrTimeout           = errors.New("strict: request timed out")
+	ErrServerUnavailable = errors.New("strict: server unavailable")
+)
+
+// maxErrorBody caps how much of an error response is read into memory.
+const maxErrorBody = 1 << 20
+
+// APIError is returned when the server responds with a non-success status.
+// It carries whatever structured detail the server put in the error body.
+type APIError struct {
+	StatusCode int
+	// Code is the machine-readable error code, if the server sent one.
+	Code string
+	// Message is the human-readable error message.
+	Message string
+	// RequestID identifies the failed request in server logs.
+	RequestID string
+	// Details lists per-field problems for validation failures.
+	Details []FieldError
+	// Body is the raw response body, truncated to 1 MiB.
+	Body []byte
+}
+
+// FieldError describes a single invalid field, in the shape the server
+// uses for validation errors.
+type FieldError struct {
+	// Loc is the path to the offending field, e.g. ["body", "input_tokens"].
+	Loc     []string `json:"loc"`
+	Message string   `json:"msg"`
+	Type    string   `json:"type"`
+}
+
+// Field returns the dotted field path without the leading "body" segment.
+func (f FieldError) Field() string {
+	loc := f.Loc
+	if len(loc) > 0 && loc[0] == "body" {
+		loc = loc[1:]
+	}
+	return strings.Join(loc, ".")
+}
+
+func (f FieldError) String() string {
+	if field := f.Field(); field != "" {
+		return field + ": " + f.Message
+	}
+	return f.Message
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "strict: status %d", e.StatusCode)
+	if e.Code != "" {
+		fmt.Fprintf(&b, " (%s)", e.Code)
+	}
+	if e.Message != "" {
+		b.WriteString(": ")
+		b.WriteString(e.Message)
+	}
+	for i, d := range e.Details {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		b.WriteString(d.String())
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " [request %s]", e.RequestID)
+	}
+	return b.String()
+}
+
+// fillRequestID sets RequestID if the error body didn't carry one.
+func (e *APIError) fillRequestID(id string) {
+	if e.RequestID == "" {
+		e.RequestID = id
+	}
+}
+
+// Is matches the sentinel error corresponding to the status code.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrValidationFailed:
+		return (e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity) &&
+			e.Code != errCodeUnsupportedVersion
+	case ErrUnsupportedVersion:
+		return e.Code == errCodeUnsupportedVersion
+	case ErrTimeout:
+		return e.StatusCode == http.StatusRequestTimeout || e.StatusCode == http.StatusGatewayTimeout
+	case ErrServerUnavailable:
+		return e.StatusCode == http.StatusBadGateway || e.StatusCode == http.StatusServiceUnavailable
+	}
+	return false
+}
+
+// transportError wraps failures that happened before a response was
+// received, such as connection errors and timeouts.
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string { return "strict: " + e.err.Error() }
+
+func (e *transportError) Unwrap() error { return e.err }
+
+func (e *transportError) Is(target error) bool {
+	switch target {
+	case ErrTimeout:
+		return e.timeout()
+	case ErrServerUnavailable:
+		return !e.timeout() && !errors.Is(e.err, context.Canceled)
+	}
+	return false
+}
+
+func (e *transportError) timeout() bool {
+	if errors.Is(e.err, context.DeadlineExceeded) {
+		return true
+	}
+	var te interface{ Timeout() bool }
+	return errors.As(e.err, &te) && te.Timeout()
+}
+
+// errorBody covers both the FastAPI default ({"detail": ...}) and the
+// gateway's envelope ({"error": {...}}).
+type errorBody struct {
+	Detail json.RawMessage `json:"detail"`
+	Error  *struct {
+		Code      string       `json:"code"`
+		Message   string       `json:"message"`
+		RequestID string       `json:"request_id"`
+		Details   []FieldError `json:"details"`
+	} `json:"error"`
+	RequestID string `json:"request_id"`
+}
+
+// rawFieldError accepts locations that mix strings and list indices.
+type rawFieldError struct {
+	Loc  []interface{} `json:"loc"`
+	Msg  string        `json:"msg"`
+	Type string        `json:"type"`
+}
+
+func newAPIError(resp *http.Response) *APIError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return parseAPIError(resp.StatusCode, resp.Header.Get("X-Request-ID"), body)
+}
+
+// parseAPIError builds an APIError from an error body.
+func parseAPIError(status int, requestID string, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: status,
+		RequestID:  requestID,
+		Body:       body,
+	}
+
+	var eb errorBody
+	if err := json.Unmarshal(body, &eb); err != nil {
+		e.Message = strings.TrimSpace(string(body))
+		if e.Message == "" {
+			e.Message = http.StatusText(status)
+		}
+		return e
+	}
+	if eb.RequestID != "" && e.RequestID == "" {
+		e.RequestID = eb.RequestID
+	}
+	if eb.Error != nil {
+		e.Code = eb.Error.Code
+		e.Message = eb.Error.Message
+		e.Details = eb.Error.Details
+		if eb.Error.RequestID != "" && e.RequestID == "" {
+			e.RequestID = eb.Error.RequestID
+		}
+	}
+	if len(eb.Detail) > 0 {
+		var msg string
+		var fields []rawFieldError
+		switch {
+		case json.Unmarshal(eb.Detail, &msg) == nil:
+			e.Message = msg
+		case json.Unmarshal(eb.Detail, &fields) == nil:
+			for _, f := range fields {
+				fe := FieldError{Message: f.Msg, Type: f.Type}
+				for _, part := range f.Loc {
+					fe.Loc = append(fe.Loc, fmt.Sprint(part))
+				}
+				e.Details = append(e.Details, fe)
+			}
+		}
+	}
+	if e.Message == "" && len(e.Details) == 0 {
+		e.Message = http.StatusText(status)
+	}
+	return e
+}
@@ -0,0 +1,98 @@
+package strict
+
+import (
+	"context"
+	"errors"
+)
+
+// FallbackPolicy retries requests that fail on one processor against
+// others. See WithFallback.
+type FallbackPolicy struct {
+	// Chains maps a processor type to the types to try, in order, when a
+	// request for it fails. Requests for types with no chain don't fall
+	// back.
+	Chains map[ProcessorType][]ProcessorType
+	// ShouldFallback reports whether err warrants trying the next
+	// processor. The default falls back on transient errors, such as
+	// timeouts and 5xx responses, and when the circuit breaker is open;
+	// not on validation errors, throttling or bad credentials, which
+	// another processor wouldn't fix.
+	ShouldFallback func(err error) bool
+	// OnFallback, if set, is called before each fallback attempt, for
+	// logging and metrics.
+	OnFallback func(from, to ProcessorType, err error)
+}
+
+// DefaultFallbackPolicy falls back from Cloud to Local.
+func DefaultFallbackPolicy() FallbackPolicy {
+	return FallbackPolicy{Chains: map[ProcessorType][]ProcessorType{Cloud: {Local}}}
+}
+
+// FallbackInfo describes a fallback that produced an output.
+type FallbackInfo struct {
+	// Requested is the processor type the request asked for.
+	Requested ProcessorType
+	// Tried lists the processor types that failed, starting with
+	// Requested.
+	Tried []ProcessorType
+	// Cause is the requested processor's failure.
+	Cause error
+}
+
+// WithFallback makes ProcessRequest retry a request against the
+// processors in p's chain for its type when it fails with an error p
+// allows, after the client's own retries are spent. An output produced
+// by a fallback has Fallback set, and ProcessorUsed names the processor
+// that answered. If every processor fails, the requested processor's
+// error is returned.
+//
+// Fallbacks respect WithRegion: processors the region doesn't host are
+// skipped.
+func WithFallback(p FallbackPolicy) Option {
+	return func(c *Client) {
+		chains := make(map[ProcessorType][]ProcessorType, len(p.Chains))
+		for from, to := range p.Chains {
+			chains[from] = append([]ProcessorType(nil), to...)
+		}
+		p.Chains = chains
+		c.fallback = &p
+	}
+}
+
+func (p *FallbackPolicy) should(err error) bool {
+	if p.ShouldFallback != nil {
+		return p.ShouldFallback(err)
+	}
+	return Classify(err) == ErrorClassTransient || errors.Is(err, ErrCircuitOpen)
+}
+
+// processFallback tries the processors after req's in the fallback
+// chain, once req has failed with cause.
+func (c *Client) processFallback(ctx context.Context, req ProcessingRequest, co callOptions, cause error) (*OutputSchema, error) {
+	p := c.fallback
+	info := &FallbackInfo{Requested: req.ProcessorType, Tried: []ProcessorType{req.ProcessorType}, Cause: cause}
+	err := cause
+	for _, next := range p.Chains[req.ProcessorType] {
+		if ctx.Err() != nil || !p.should(err) {
+			break
+		}
+		if c.checkRegion(next) != nil {
+			continue
+		}
+		if p.OnFallback != nil {
+			p.OnFallback(info.Tried[len(info.Tried)-1], next, err)
+		}
+		alt := req
+		alt.ProcessorType = next
+		var out *OutputSchema
+		if out, err = c.processOnce(ctx, alt, co); err == nil {
+			// Don't annotate an output shared with the cache or other
+			// callers.
+			annotated := *out
+			annotated.Fallback = info
+			return &annotated, nil
+		}
+		info.Tried = append(info.Tried, next)
+	}
+	return nil, cause
+}
@@ -0,0 +1,64 @@
+package strict
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// HealthStatus is the server's report from /health or /ready.
+type HealthStatus struct {
+	// Status is "ok" when the server is healthy. Other values, such as
+	// "degraded" or "unavailable", describe what is wrong.
+	Status  string `json:"status"`
+	Version string `json:"version,omitempty"`
+	// Components reports dependencies, such as processors or the cache,
+	// by name.
+	Components map[string]ComponentStatus `json:"components,omitempty"`
+}
+
+// ComponentStatus is the state of one server dependency.
+type ComponentStatus struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// OK reports whether the status is "ok".
+func (s *HealthStatus) OK() bool {
+	return s != nil && s.Status == "ok"
+}
+
+// Health reports whether the server is up, with GET /health.
+//
+// Health and Ready make a single attempt, without retries, failover or
+// hedging, so they report what the server says right now. When the
+// server answers 503 Service Unavailable with a status body, both the
+// decoded status and the *APIError are returned.
+func (c *Client) Health(ctx context.Context) (*HealthStatus, error) {
+	return c.probe(ctx, "Health", "/health")
+}
+
+// Ready reports whether the server can take traffic, with GET /ready.
+// See Health.
+func (c *Client) Ready(ctx context.Context) (*HealthStatus, error) {
+	return c.probe(ctx, "Ready", "/ready")
+}
+
+func (c *Client) probe(ctx context.Context, op, path string) (*HealthStatus, error) {
+	var st HealthStatus
+	cl, err := newJSONCall(op, http.MethodGet, path, nil, &st, callOptions{})
+	if err != nil {
+		return nil, err
+	}
+	cl.once = true
+	if err := c.invoke(ctx, cl); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusServiceUnavailable &&
+			json.Unmarshal(apiErr.Body, &st) == nil && st.Status != "" {
+			return &st, err
+		}
+		return nil, err
+	}
+	return &st, nil
+}
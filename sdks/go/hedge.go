@@ -0,0 +1,141 @@
+package strict
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// HedgeConfig enables hedged requests.
+type HedgeConfig struct {
+	// Delay is how long to wait for a response before sending a hedge.
+	Delay time.Duration
+	// MaxHedges is the number of extra requests allowed per attempt.
+	// Defaults to 1.
+	MaxHedges int
+}
+
+// WithHedging sends an extra copy of a request when the first has not
+// completed within cfg.Delay, and returns whichever finishes first,
+// cancelling the rest. Hedges go to the next endpoint when several are
+// configured, otherwise to the same one. All copies share the call's
+// Idempotency-Key, so the server can collapse duplicates.
+//
+// Each hedge takes a token from the rate limiter and is admitted by the
+// circuit breaker like any other request. A hedge that would have to
+// wait for either is held back until the next delay elapses.
+func WithHedging(cfg HedgeConfig) Option {
+	return func(c *Client) {
+		if cfg.MaxHedges < 1 {
+			cfg.MaxHedges = 1
+		}
+		c.hedge = &cfg
+	}
+}
+
+type hedgeResult struct {
+	cl  *call
+	err error
+}
+
+// sendHedged races up to MaxHedges+1 copies of one attempt.
+func (c *Client) sendHedged(ctx context.Context, cl *call) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var targets []*endpoint
+	if c.endpoints != nil {
+		targets = c.endpoints.order()
+	}
+	results := make(chan hedgeResult, c.hedge.MaxHedges+1)
+	// launch starts copy n, reporting false if a hedge was held back. The
+	// first copy was admitted by retryLoop.
+	launch := func(n int) bool {
+		if n > 0 && !c.admitHedge(cl) {
+			return false
+		}
+		hc := *cl
+		if cl.out != nil {
+			hc.out = reflect.New(reflect.TypeOf(cl.out).Elem()).Interface()
+		}
+		go func() {
+			var err error
+			if len(targets) == 0 {
+				err = c.attempt(ctx, &hc, c.baseURL())
+			} else {
+				ep := targets[n%len(targets)]
+				start := time.Now()
+				err = c.attempt(ctx, &hc, ep.url)
+				if ctx.Err() == nil {
+					c.endpoints.report(ep, Classify(err) == ErrorClassTransient, time.Since(start))
+				}
+			}
+			if n > 0 && c.breaker != nil {
+				// A copy cancelled because another won, or throttled, says
+				// nothing about the server's health.
+				if ctx.Err() != nil || Classify(err) == ErrorClassThrottled {
+					c.breaker.abandon()
+				} else {
+					c.breaker.done(Classify(err) == ErrorClassTransient)
+				}
+			}
+			results <- hedgeResult{cl: &hc, err: err}
+		}()
+		return true
+	}
+
+	launched, pending := 1, 1
+	launch(0)
+	timer := time.NewTimer(c.hedge.Delay)
+	defer timer.Stop()
+
+	var lastErr error
+	for {
+		select {
+		case <-timer.C:
+			if launched <= c.hedge.MaxHedges {
+				if launch(launched) {
+					launched++
+					pending++
+				}
+				timer.Reset(c.hedge.Delay)
+			}
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				c.adoptHedge(cl, r.cl)
+				return nil
+			}
+			lastErr = r.err
+			// A failed copy is replaced immediately rather than after the
+			// delay, as long as hedges remain.
+			if launched <= c.hedge.MaxHedges && IsRetryable(r.err) && launch(launched) {
+				launched++
+				pending++
+			} else if pending == 0 {
+				c.adoptHedge(cl, r.cl)
+				return lastErr
+			}
+		}
+	}
+}
+
+// admitHedge takes a rate-limit token and a circuit breaker admission for
+// a hedge of cl, without waiting for either.
+func (c *Client) admitHedge(cl *call) bool {
+	if limiter := c.limiterFor(cl.tenant); limiter != nil && !limiter.Allow() {
+		return false
+	}
+	return c.breaker == nil || c.breaker.allow() == nil
+}
+
+// adoptHedge copies the winning copy's response state back onto cl.
+func (c *Client) adoptHedge(cl, winner *call) {
+	if cl.out != nil {
+		reflect.ValueOf(cl.out).Elem().Set(reflect.ValueOf(winner.out).Elem())
+	}
+	cl.status = winner.status
+	cl.serverRequestID = winner.serverRequestID
+	cl.etag = winner.etag
+	cl.notModified = winner.notModified
+}
@@ -0,0 +1,72 @@
+package strict
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHedgeAdmission(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		// halfOpen trips the breaker and lets it cool down, so it admits
+		// one request at a time.
+		halfOpen bool
+		want     int32
+	}{
+		{"unlimited", nil, false, 3},
+		{"limiter has tokens", []Option{WithRateLimit(RateLimitConfig{RequestsPerSecond: 1, Burst: 3})}, false, 3},
+		{"limiter exhausted", []Option{WithRateLimit(RateLimitConfig{RequestsPerSecond: 0.001, Burst: 1})}, false, 1},
+		{"breaker closed", []Option{WithCircuitBreaker(BreakerConfig{})}, false, 3},
+		{"breaker half-open", []Option{WithCircuitBreaker(BreakerConfig{FailureThreshold: 1, CoolDown: time.Millisecond})}, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				select {
+				case <-time.After(100 * time.Millisecond):
+				case <-r.Context().Done():
+					return
+				}
+				w.Write([]byte(`{"version":"1"}`))
+			}))
+			defer srv.Close()
+			opts := append([]Option{WithHedging(HedgeConfig{Delay: 5 * time.Millisecond, MaxHedges: 2})}, tt.opts...)
+			c := NewClient(srv.URL, "k", opts...)
+			if tt.halfOpen {
+				c.breaker.allow()
+				c.breaker.done(true)
+				time.Sleep(2 * time.Millisecond)
+			}
+			if _, err := c.ServerInfo(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if got := requests.Load(); got != tt.want {
+				t.Errorf("server saw %d copies, want %d", got, tt.want)
+			}
+			if c.breaker == nil {
+				return
+			}
+			// Losing copies release the breaker as their cancellation
+			// lands, after the call returns.
+			var inFlight int
+			for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+				c.breaker.mu.Lock()
+				inFlight = c.breaker.inFlight
+				c.breaker.mu.Unlock()
+				if inFlight == 0 {
+					break
+				}
+			}
+			if inFlight != 0 {
+				t.Errorf("breaker has %d requests in flight after the call", inFlight)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package strict
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestInfo describes an HTTP attempt about to be sent.
+type RequestInfo struct {
+	// Operation is the client method making the attempt, e.g.
+	// "ProcessRequest" or "GetJobStatus".
+	Operation string
+	Method    string
+	URL       string
+	Header    http.Header
+	// Body is the serialized request body before compression, or nil for
+	// streamed calls. It is not redacted, even with WithRedactor. Hooks
+	// must not modify it.
+	Body []byte
+	// Attempt is 1 for the first try and increases with each retry.
+	Attempt int
+	// RequestID is the X-Request-ID generated for the call. Retries share
+	// it.
+	RequestID string
+	// CorrelationID is the caller's correlation ID, if one was set with
+	// WithCorrelationID.
+	CorrelationID string
+	// Tenant is the tenant the call is made for, if any.
+	Tenant string
+}
+
+// ResponseInfo describes the outcome of an HTTP attempt.
+type ResponseInfo struct {
+	Request *RequestInfo
+	// StatusCode is zero if no response was received.
+	StatusCode int
+	Header     http.Header
+	// ServerRequestID is the request ID the server reported, falling back
+	// to the client-generated one.
+	ServerRequestID string
+	Latency         time.Duration
+	// Err is the error the attempt produced, if any.
+	Err error
+}
+
+// Hooks are lifecycle callbacks invoked around every HTTP attempt. Either
+// field may be nil. Hooks run synchronously on the calling goroutine.
+type Hooks struct {
+	OnRequest  func(ctx context.Context, req *RequestInfo)
+	OnResponse func(ctx context.Context, resp *ResponseInfo)
+}
+
+func (c *Client) fireRequest(ctx context.Context, info *RequestInfo) {
+	for _, h := range c.hooks {
+		if h.OnRequest != nil {
+			h.OnRequest(ctx, info)
+		}
+	}
+}
+
+func (c *Client) fireResponse(ctx context.Context, info *ResponseInfo) {
+	for _, h := range c.hooks {
+		if h.OnResponse != nil {
+			h.OnResponse(ctx, info)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package strict
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// ServerInfo describes the server's version and capabilities.
+type ServerInfo struct {
+	Version string    `json:"version"`
+	Build   BuildInfo `json:"build"`
+	// APIVersions lists the API versions the server serves.
+	APIVersions []string `json:"api_versions,omitempty"`
+	// Features names the optional features enabled on this server, such
+	// as "batch" or "jobs".
+	Features []string `json:"features"`
+	// ProcessorTypes lists the processor types the server accepts,
+	// including any custom ones.
+	ProcessorTypes []ProcessorType `json:"processor_types"`
+}
+
+// BuildInfo identifies the server build.
+type BuildInfo struct {
+	Commit string    `json:"commit,omitempty"`
+	Date   time.Time `json:"date"`
+}
+
+// HasFeature reports whether the server has the named feature enabled.
+func (i *ServerInfo) HasFeature(name string) bool {
+	for _, f := range i.Features {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsProcessor reports whether the server accepts processor type p.
+func (i *ServerInfo) SupportsProcessor(p ProcessorType) bool {
+	for _, t := range i.ProcessorTypes {
+		if t == p {
+			return true
+		}
+	}
+	return false
+}
+
+// ServerInfo fetches the server's version and capabilities with GET
+// /info, so differences between environments can be detected up front.
+func (c *Client) ServerInfo(ctx context.Context, opts ...CallOption) (*ServerInfo, error) {
+	var info ServerInfo
+	cl, err := newJSONCall("ServerInfo", http.MethodGet, "/info", nil, &info, newCallOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.invoke(ctx, cl); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
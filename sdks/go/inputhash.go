@@ -0,0 +1,80 @@
+package strict
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// minInputHashLen is the shortest server hash that is compared. The
+// server truncates ValidationResult.InputHash to 16 hex digits.
+const minInputHashLen = 16
+
+// ComputeInputHash returns the hex-encoded SHA-256 of req.InputData as
+// the server computes it: over the UTF-8 text it receives, with each
+// invalid byte replaced by U+FFFD as JSON encoding does. The server's
+// ValidationResult.InputHash is a prefix of this value.
+func ComputeInputHash(req ProcessingRequest) string {
+	h := sha256.New()
+	s, start := req.InputData, 0
+	for i, r := range s {
+		if r == utf8.RuneError && !strings.HasPrefix(s[i:], "\uFFFD") {
+			h.Write([]byte(s[start:i]))
+			h.Write([]byte("\uFFFD"))
+			start = i + 1
+		}
+	}
+	h.Write([]byte(s[start:]))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// WithInputHashVerification checks that the input hash the server
+// returns in ValidationResult.InputHash matches the input that was sent,
+// failing calls with *InputHashMismatchError when it doesn't. Responses
+// with no hash are accepted.
+func WithInputHashVerification() Option {
+	return func(c *Client) {
+		c.verifyHash = true
+	}
+}
+
+// InputHashMismatchError reports a response whose input hash doesn't
+// match the input that was sent, meaning the server processed different
+// input than the client intended.
+type InputHashMismatchError struct {
+	// Want is the hash of the input that was sent.
+	Want string
+	// Got is the hash the server returned.
+	Got       string
+	RequestID string
+}
+
+func (e *InputHashMismatchError) Error() string {
+	msg := fmt.Sprintf("strict: input hash mismatch: server returned %s, want %s", e.Got, e.Want)
+	if e.RequestID != "" {
+		msg += " [request " + e.RequestID + "]"
+	}
+	return msg
+}
+
+// matchInputHash reports whether got, which may be truncated, matches the
+// full hash want.
+func matchInputHash(want, got string) bool {
+	got = strings.ToLower(got)
+	if len(got) < minInputHashLen || len(got) > len(want) {
+		return false
+	}
+	return strings.HasPrefix(want, got)
+}
+
+// verifyInputHash checks out against want, the full hash of the input
+// sent, if WithInputHashVerification is set.
+func (c *Client) verifyInputHash(want string, out *OutputSchema) error {
+	got := out.Validation.InputHash
+	if !c.verifyHash || got == "" || matchInputHash(want, got) {
+		return nil
+	}
+	return &InputHashMismatchError{Want: want, Got: got, RequestID: out.RequestID}
+}
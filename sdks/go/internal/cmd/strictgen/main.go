@@ -0,0 +1,666 @@
+// Command strictgen generates the strict client's wire types and endpoint
+// methods from the server's OpenAPI spec. It is run by go generate in the
+// strict package:
+//
+//	STRICT_OPENAPI_SPEC=http://localhost:8000/openapi.json go generate
+//
+// Hand-written code always wins. strictgen reads the package first and
+// skips every operation already listed in wireContracts, every schema
+// those operations send or receive, directly or nested, and every schema
+// whose name matches a Go type in the package ignoring case, so
+// unexported wire types such as batchRequest count too. Types with
+// methods or custom encoding, and endpoints with client-side logic such
+// as uploads and jobs, stay as they are. Everything else is written to
+// one generated file, which also registers the generated endpoints with
+// CheckContract. To hand over a hand-written type to the generator,
+// delete it and regenerate; to take one over, do the reverse.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mohitmishra786/strict/sdks/go/internal/openapi"
+)
+
+func main() {
+	spec := flag.String("spec", os.Getenv("STRICT_OPENAPI_SPEC"), "OpenAPI spec `file or URL` (default $STRICT_OPENAPI_SPEC)")
+	out := flag.String("o", "api_gen.go", "output `file`, relative to -dir")
+	dir := flag.String("dir", ".", "package `directory`")
+	flag.Parse()
+	if *spec == "" {
+		fatalf("no spec: pass -spec or set STRICT_OPENAPI_SPEC")
+	}
+	data, err := readSpec(*spec)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	doc, err := openapi.Parse(data)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	outPath := filepath.Join(*dir, *out)
+	pkg, err := scanPackage(*dir, outPath)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	g := newGenerator(doc, pkg)
+	src, err := g.generate()
+	if err != nil {
+		fatalf("%v", err)
+	}
+	if err := os.WriteFile(outPath, src, 0o644); err != nil {
+		fatalf("%v", err)
+	}
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "strictgen: "+format+"\n", args...)
+	os.Exit(1)
+}
+
+func readSpec(source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return os.ReadFile(source)
+	}
+	hc := &http.Client{Timeout: 30 * time.Second}
+	resp, err := hc.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s: %s", source, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// handWritten is what the package already declares outside the
+// generated file.
+type handWritten struct {
+	name      string
+	types     map[string]string // lower-cased name to name
+	structs   map[string]bool   // lower-cased
+	methods   map[string]bool   // on *Client
+	endpoints map[string]bool   // "METHOD /path", with parameter names blanked
+}
+
+// scanPackage reads the declarations of the package in dir, ignoring
+// tests and the generated file itself.
+func scanPackage(dir, generated string) (*handWritten, error) {
+	fset := token.NewFileSet()
+	skip := func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && filepath.Join(dir, fi.Name()) != generated
+	}
+	pkgs, err := parser.ParseDir(fset, dir, skip, 0)
+	if err != nil {
+		return nil, err
+	}
+	hw := &handWritten{types: map[string]string{}, structs: map[string]bool{}, methods: map[string]bool{}, endpoints: map[string]bool{}}
+	for name, pkg := range pkgs {
+		if strings.HasSuffix(name, "_test") {
+			continue
+		}
+		hw.name = name
+		for _, f := range pkg.Files {
+			for _, decl := range f.Decls {
+				hw.scanDecl(decl)
+			}
+		}
+	}
+	if hw.name == "" {
+		return nil, fmt.Errorf("no Go package in %s", dir)
+	}
+	return hw, nil
+}
+
+func (hw *handWritten) scanDecl(decl ast.Decl) {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv != nil && len(d.Recv.List) == 1 {
+			if star, ok := d.Recv.List[0].Type.(*ast.StarExpr); ok {
+				if id, ok := star.X.(*ast.Ident); ok && id.Name == "Client" {
+					hw.methods[d.Name.Name] = true
+				}
+			}
+		}
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				name := strings.ToLower(s.Name.Name)
+				hw.types[name] = s.Name.Name
+				if _, ok := s.Type.(*ast.StructType); ok {
+					hw.structs[name] = true
+				}
+			case *ast.ValueSpec:
+				for i, n := range s.Names {
+					if n.Name == "wireContracts" && i < len(s.Values) {
+						hw.scanContracts(s.Values[i])
+					}
+				}
+			}
+		}
+	}
+}
+
+// scanContracts records the endpoints in the wireContracts literal.
+func (hw *handWritten) scanContracts(v ast.Expr) {
+	list, ok := v.(*ast.CompositeLit)
+	if !ok {
+		return
+	}
+	for _, elt := range list.Elts {
+		wc, ok := elt.(*ast.CompositeLit)
+		if !ok || len(wc.Elts) < 2 {
+			continue
+		}
+		sel, ok := wc.Elts[0].(*ast.SelectorExpr)
+		lit, ok2 := wc.Elts[1].(*ast.BasicLit)
+		if !ok || !ok2 {
+			continue
+		}
+		path, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			continue
+		}
+		method := strings.ToUpper(strings.TrimPrefix(sel.Sel.Name, "Method"))
+		hw.endpoints[endpointKey(method, path)] = true
+	}
+}
+
+func endpointKey(method, path string) string {
+	for _, p := range openapi.PathParams(path) {
+		path = strings.Replace(path, "{"+p+"}", "{}", 1)
+	}
+	return method + " " + strings.TrimSuffix(path, "/")
+}
+
+type generator struct {
+	doc  *openapi.Document
+	hw   *handWritten
+	body bytes.Buffer
+
+	imports map[string]bool
+	// named maps generated type names to their schemas, and pending
+	// lists those not written yet.
+	named   map[string]*openapi.Schema
+	pending []string
+	// covered holds the component schemas the hand-written endpoints
+	// use, which their hand-written types model.
+	covered map[string]bool
+}
+
+func newGenerator(doc *openapi.Document, hw *handWritten) *generator {
+	return &generator{doc: doc, hw: hw, imports: map[string]bool{}, named: map[string]*openapi.Schema{}, covered: map[string]bool{}}
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.body, format, args...)
+}
+
+func (g *generator) generate() ([]byte, error) {
+	g.doc.Operations(func(method, path string, op *openapi.Operation) {
+		if !g.hw.endpoints[endpointKey(method, path)] {
+			return
+		}
+		g.cover(op.RequestBody.JSONSchema())
+		if resp, _ := op.SuccessResponse(); resp != nil {
+			g.cover(resp.JSONSchema())
+		}
+	})
+	names := make([]string, 0, len(g.doc.Components.Schemas))
+	for name := range g.doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !g.covered[name] {
+			g.need(goName(name), g.doc.Components.Schemas[name])
+		}
+	}
+
+	var contracts []string
+	var err error
+	g.doc.Operations(func(method, path string, op *openapi.Operation) {
+		if err != nil || g.hw.endpoints[endpointKey(method, path)] {
+			return
+		}
+		var wc string
+		wc, err = g.endpoint(method, path, op)
+		if wc != "" {
+			contracts = append(contracts, wc)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(contracts) > 0 {
+		g.printf("func init() {\n\twireContracts = append(wireContracts,\n")
+		for _, wc := range contracts {
+			g.printf("\t\t%s,\n", wc)
+		}
+		g.printf("\t)\n}\n\n")
+	}
+	for len(g.pending) > 0 {
+		name := g.pending[0]
+		g.pending = g.pending[1:]
+		if err := g.typeDecl(name, g.named[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	var file bytes.Buffer
+	title := strings.TrimSpace(g.doc.Info.Title + " " + g.doc.Info.Version)
+	fmt.Fprintf(&file, "// Code generated by strictgen from the OpenAPI spec of %s. DO NOT EDIT.\n\n", title)
+	fmt.Fprintf(&file, "package %s\n\n", g.hw.name)
+	if len(g.imports) > 0 {
+		paths := make([]string, 0, len(g.imports))
+		for p := range g.imports {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+		file.WriteString("import (\n")
+		for _, p := range paths {
+			fmt.Fprintf(&file, "\t%q\n", p)
+		}
+		file.WriteString(")\n\n")
+	}
+	file.Write(g.body.Bytes())
+	src, err := format.Source(file.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return src, nil
+}
+
+// cover marks the component schemas s refers to, and those they refer
+// to in turn, as modelled by hand.
+func (g *generator) cover(s *openapi.Schema) {
+	if s == nil {
+		return
+	}
+	if name, ok := openapi.RefName(s.Ref); ok {
+		if g.covered[name] {
+			return
+		}
+		g.covered[name] = true
+		g.cover(g.doc.Components.Schemas[name])
+		return
+	}
+	for _, p := range s.Properties {
+		g.cover(p)
+	}
+	g.cover(s.Items)
+	if extra, _ := s.AdditionalSchema(); extra != nil {
+		g.cover(extra)
+	}
+	for _, list := range [][]*openapi.Schema{s.AllOf, s.AnyOf, s.OneOf} {
+		for _, b := range list {
+			g.cover(b)
+		}
+	}
+}
+
+// need queues a named type for s unless the package or the generator
+// already has one.
+func (g *generator) need(name string, s *openapi.Schema) {
+	if g.hw.types[strings.ToLower(name)] != "" || g.named[name] != nil {
+		return
+	}
+	g.named[name] = s
+	g.pending = append(g.pending, name)
+}
+
+// typeDecl writes the declaration of a generated named type.
+func (g *generator) typeDecl(name string, s *openapi.Schema) error {
+	r, err := g.doc.Resolve(s)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	writeComment(&g.body, name, strings.TrimSpace("is generated from the OpenAPI spec. "+sentence(r.Description)))
+	if r.Type.Main() == "string" && len(r.Enum) > 0 {
+		g.printf("type %s string\n\n", name)
+		g.printf("const (\n")
+		for _, v := range r.Enum {
+			if str, ok := v.(string); ok {
+				g.printf("\t%s %s = %q\n", name+goName(str), name, str)
+			}
+		}
+		g.printf(")\n\n")
+		return nil
+	}
+	if !isObject(r) {
+		g.printf("type %s %s\n\n", name, g.goType(r, name))
+		return nil
+	}
+	g.printf("type %s struct {\n", name)
+	props := make([]string, 0, len(r.Properties))
+	for p := range r.Properties {
+		props = append(props, p)
+	}
+	sort.Strings(props)
+	for _, p := range props {
+		prop := r.Properties[p]
+		typ := g.goType(prop, name+goName(p))
+		tag := p
+		if !r.IsRequired(p) {
+			tag += ",omitempty"
+			if g.isStruct(typ) {
+				typ = "*" + typ
+			}
+		}
+		if pr, err := g.doc.Resolve(prop); err == nil && pr != nil && pr.Description != "" && pr.Ref == "" {
+			writeComment(&g.body, "\t", pr.Description)
+		}
+		g.printf("\t%s %s `json:%q`\n", goName(p), typ, tag)
+	}
+	g.printf("}\n\n")
+	return nil
+}
+
+func isObject(s *openapi.Schema) bool {
+	if len(s.Properties) > 0 {
+		return true
+	}
+	_, extra := s.AdditionalSchema()
+	return s.Type.Main() == "object" && !extra
+}
+
+// isStruct reports whether typ names a struct type, generated or
+// hand-written, which optional fields hold by pointer so they can be
+// omitted.
+func (g *generator) isStruct(typ string) bool {
+	if typ == "time.Time" || g.hw.structs[strings.ToLower(typ)] {
+		return true
+	}
+	if s, ok := g.named[typ]; ok {
+		r, err := g.doc.Resolve(s)
+		return err == nil && isObject(r)
+	}
+	return false
+}
+
+// goType returns the Go type for s, queuing named types for inline
+// objects as hint. A schema that allows any value is kept as raw JSON.
+func (g *generator) goType(s *openapi.Schema, hint string) string {
+	if s == nil {
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	}
+	if s.Ref != "" {
+		if name, ok := openapi.RefName(s.Ref); ok {
+			gn := goName(name)
+			if hand := g.hw.types[strings.ToLower(gn)]; hand != "" {
+				return hand
+			}
+			if target, ok := g.doc.Components.Schemas[name]; ok {
+				g.need(gn, target)
+			}
+			return gn
+		}
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	}
+	if len(s.AllOf) == 1 && len(s.Properties) == 0 {
+		return g.goType(s.AllOf[0], hint)
+	}
+	if len(s.AllOf) > 0 {
+		r, err := g.doc.Resolve(s)
+		if err != nil {
+			g.imports["encoding/json"] = true
+			return "json.RawMessage"
+		}
+		return g.goType(r, hint)
+	}
+	if branches := append(append([]*openapi.Schema(nil), s.AnyOf...), s.OneOf...); len(branches) > 0 {
+		var real []*openapi.Schema
+		nullable := false
+		for _, b := range branches {
+			if len(b.Type) == 1 && b.Type[0] == "null" {
+				nullable = true
+				continue
+			}
+			real = append(real, b)
+		}
+		if len(real) != 1 {
+			g.imports["encoding/json"] = true
+			return "json.RawMessage"
+		}
+		typ := g.goType(real[0], hint)
+		if nullable && isScalar(typ) {
+			typ = "*" + typ
+		}
+		return typ
+	}
+	typ := ""
+	switch s.Type.Main() {
+	case "string":
+		typ = "string"
+		switch s.Format {
+		case "date-time":
+			g.imports["time"] = true
+			typ = "time.Time"
+		case "byte", "binary":
+			typ = "[]byte"
+		}
+	case "integer":
+		typ = "int"
+		if s.Format == "int64" {
+			typ = "int64"
+		}
+	case "number":
+		typ = "float64"
+	case "boolean":
+		typ = "bool"
+	case "array":
+		return "[]" + g.goType(s.Items, hint+"Item")
+	case "object", "":
+		if len(s.Properties) > 0 {
+			g.need(hint, s)
+			return hint
+		}
+		if extra, _ := s.AdditionalSchema(); extra != nil {
+			return "map[string]" + g.goType(extra, hint+"Value")
+		}
+		if s.Type.Main() == "object" {
+			return "map[string]interface{}"
+		}
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	default:
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	}
+	if s.Nullable || s.Type.Has("null") {
+		typ = "*" + typ
+	}
+	return typ
+}
+
+func isScalar(typ string) bool {
+	switch typ {
+	case "string", "int", "int64", "float64", "bool", "time.Time":
+		return true
+	}
+	return false
+}
+
+// endpoint writes a *Client method for op and returns its wireContracts
+// entry.
+func (g *generator) endpoint(method, path string, op *openapi.Operation) (string, error) {
+	name := op.GoName
+	if name == "" {
+		name = goName(op.OperationID)
+	}
+	if name == "" {
+		name = goName(strings.ToLower(method) + " " + path)
+	}
+	if g.hw.methods[name] {
+		fmt.Fprintf(os.Stderr, "strictgen: %s %s: Client.%s is hand-written; add the endpoint to wireContracts or set x-go-name\n", method, path, name)
+		return "", nil
+	}
+	g.hw.methods[name] = true
+
+	var reqType, respType string
+	if s := op.RequestBody.JSONSchema(); s != nil {
+		reqType = g.goType(s, name+"Request")
+	}
+	if resp, _ := op.SuccessResponse(); resp.JSONSchema() != nil {
+		respType = g.goType(resp.JSONSchema(), name+"Response")
+	}
+
+	params := openapi.PathParams(path)
+	args := []string{"ctx context.Context"}
+	pathExpr := strconv.Quote(path)
+	for _, p := range params {
+		arg := lowerFirst(goName(p))
+		args = append(args, arg+" string")
+		pathExpr = strings.Replace(pathExpr, "{"+p+"}", `"+url.PathEscape(`+arg+`)+"`, 1)
+		g.imports["net/url"] = true
+	}
+	pathExpr = strings.TrimSuffix(strings.TrimPrefix(pathExpr, `""+`), `+""`)
+	in := "nil"
+	if reqType != "" {
+		args = append(args, "in "+pointerTo(g, reqType))
+		in = "in"
+	}
+	args = append(args, "opts ...CallOption")
+	g.imports["context"] = true
+	g.imports["net/http"] = true
+
+	summary := "calls the server's " + method + " " + path + " endpoint."
+	if op.Summary != "" {
+		summary = "calls " + method + " " + path + ": " + strings.TrimSuffix(lowerFirst(sentence(op.Summary)), ".") + "."
+	}
+	writeComment(&g.body, name, summary)
+	httpMethod := "http.Method" + strings.ToUpper(method[:1]) + strings.ToLower(method[1:])
+	if respType == "" {
+		g.printf("func (c *Client) %s(%s) error {\n", name, strings.Join(args, ", "))
+		g.printf("\tcl, err := newJSONCall(%q, %s, %s, %s, nil, newCallOptions(opts))\n", name, httpMethod, pathExpr, in)
+		g.printf("\tif err != nil {\n\t\treturn err\n\t}\n\treturn c.invoke(ctx, cl)\n}\n\n")
+	} else {
+		g.printf("func (c *Client) %s(%s) (%s, error) {\n", name, strings.Join(args, ", "), pointerTo(g, respType))
+		g.printf("\tvar out %s\n", respType)
+		g.printf("\tcl, err := newJSONCall(%q, %s, %s, %s, &out, newCallOptions(opts))\n", name, httpMethod, pathExpr, in)
+		g.printf("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+		g.printf("\tif err := c.invoke(ctx, cl); err != nil {\n\t\treturn nil, err\n\t}\n")
+		if pointerTo(g, respType) != respType {
+			g.printf("\treturn &out, nil\n}\n\n")
+		} else {
+			g.printf("\treturn out, nil\n}\n\n")
+		}
+	}
+	return fmt.Sprintf("wireContract{%s, %q, %s, %s}", httpMethod, path, zeroValue(reqType), zeroValue(respType)), nil
+}
+
+// pointerTo returns how typ is passed and returned: named types and
+// scalars by pointer, slices and maps as they are.
+func pointerTo(g *generator, typ string) string {
+	if strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") || strings.HasPrefix(typ, "*") ||
+		typ == "interface{}" || typ == "json.RawMessage" {
+		return typ
+	}
+	return "*" + typ
+}
+
+// zeroValue returns an expression for the zero value of typ, for
+// wireContracts.
+func zeroValue(typ string) string {
+	switch {
+	case typ == "":
+		return "nil"
+	case strings.HasPrefix(typ, "*"), typ == "interface{}", typ == "json.RawMessage":
+		return "new(" + typ + ")"
+	case isScalar(typ) && typ != "time.Time":
+		return "new(" + typ + ")"
+	}
+	return typ + "{}"
+}
+
+// initialisms are written in capitals in Go names.
+var initialisms = map[string]bool{
+	"api": true, "http": true, "id": true, "ip": true, "json": true,
+	"sql": true, "ttl": true, "uri": true, "url": true, "uuid": true,
+}
+
+// goName converts a schema, property or operation name to an exported
+// Go identifier: "input_hash" becomes "InputHash" and "job_id" "JobID".
+func goName(s string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	}) {
+		if initialisms[strings.ToLower(word)] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	name := b.String()
+	if name != "" && '0' <= name[0] && name[0] <= '9' {
+		name = "X" + name
+	}
+	return name
+}
+
+func lowerFirst(s string) string {
+	for i, r := range s {
+		if 'a' <= r && r <= 'z' {
+			if i <= 1 {
+				return strings.ToLower(s[:1]) + s[1:]
+			}
+			// A leading initialism: "JobID" stays "jobID", "URLPath"
+			// becomes "urlPath".
+			return strings.ToLower(s[:i-1]) + s[i-1:]
+		}
+	}
+	return strings.ToLower(s)
+}
+
+// sentence trims text and ends it with a full stop.
+func sentence(text string) string {
+	text = strings.TrimSpace(text)
+	if text != "" && !strings.HasSuffix(text, ".") {
+		text += "."
+	}
+	return text
+}
+
+// writeComment writes a doc comment starting with name, wrapped at 72
+// columns. A name of "\t" writes an indented field comment instead.
+func writeComment(w *bytes.Buffer, name, text string) {
+	text = strings.Join(strings.Fields(text), " ")
+	indent := ""
+	if name == "\t" {
+		indent, name = "\t", ""
+		if text == "" {
+			return
+		}
+	}
+	if name != "" {
+		text = name + " " + text
+	}
+	line := indent + "//"
+	for _, word := range strings.Fields(text) {
+		if len(line)+1+len(word) > 72 && line != indent+"//" {
+			w.WriteString(line + "\n")
+			line = indent + "//"
+		}
+		line += " " + word
+	}
+	w.WriteString(line + "\n")
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mohitmishra786/strict/sdks/go/internal/openapi"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// pkgDir is the strict package, which the generator runs against.
+const pkgDir = "../../.."
+
+func TestGenerateGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   string
+		golden string
+	}{
+		// The test server's spec is all hand-written already.
+		{"stricttest", filepath.Join(pkgDir, "stricttest", "openapi.json"), "testdata/stricttest.golden"},
+		{"reports", "testdata/reports.json", "testdata/reports.golden"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.ReadFile(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			doc, err := openapi.Parse(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pkg, err := scanPackage(pkgDir, filepath.Join(pkgDir, "api_gen.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := newGenerator(doc, pkg).generate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *update {
+				if err := os.WriteFile(tt.golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(tt.golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("generated code differs from %s (run with -update to accept):\n%s", tt.golden, got)
+			}
+		})
+	}
+}
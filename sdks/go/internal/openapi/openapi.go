@@ -0,0 +1,339 @@
+// Package openapi reads the parts of an OpenAPI 3.0 or 3.1 document that
+// the SDK's contract checks and code generator need: operations, their
+// JSON request and response bodies, and component schemas. Documents must
+// be JSON, as the server serves them at /openapi.json.
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Document is an OpenAPI document.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// Info is the document's metadata.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem holds the operations on one path.
+type PathItem struct {
+	Get    *Operation `json:"get"`
+	Put    *Operation `json:"put"`
+	Post   *Operation `json:"post"`
+	Delete *Operation `json:"delete"`
+	Patch  *Operation `json:"patch"`
+}
+
+// Operation is one method on a path.
+type Operation struct {
+	OperationID string `json:"operationId"`
+	// GoName, from the x-go-name extension, overrides the generated
+	// method name.
+	GoName      string           `json:"x-go-name"`
+	Summary     string           `json:"summary"`
+	Parameters  []Parameter      `json:"parameters"`
+	RequestBody *Body            `json:"requestBody"`
+	Responses   map[string]*Body `json:"responses"`
+	Tags        []string         `json:"tags"`
+}
+
+// Parameter is a path, query or header parameter.
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+// Body is a request body or a response.
+type Body struct {
+	Description string               `json:"description"`
+	Required    bool                 `json:"required"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// MediaType is a body's schema for one content type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is a JSON Schema, as far as OpenAPI uses it.
+type Schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	Type        Types              `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Enum        []interface{}      `json:"enum,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	// AdditionalProperties is false, true, or a schema.
+	AdditionalProperties json.RawMessage `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema       `json:"allOf,omitempty"`
+	AnyOf                []*Schema       `json:"anyOf,omitempty"`
+	OneOf                []*Schema       `json:"oneOf,omitempty"`
+	Nullable             bool            `json:"nullable,omitempty"`
+	Default              interface{}     `json:"default,omitempty"`
+}
+
+// Types is a schema's type: one name in OpenAPI 3.0, or a list in 3.1,
+// where "null" marks a nullable value.
+type Types []string
+
+// UnmarshalJSON accepts a single type name or a list.
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = Types{one}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("openapi: type must be a string or list: %w", err)
+	}
+	*t = list
+	return nil
+}
+
+// Has reports whether name is one of the types.
+func (t Types) Has(name string) bool {
+	for _, s := range t {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Main returns the first type other than "null", or "" if there is none.
+func (t Types) Main() string {
+	for _, s := range t {
+		if s != "null" {
+			return s
+		}
+	}
+	return ""
+}
+
+// IsRequired reports whether the schema requires property name.
+func (s *Schema) IsRequired(name string) bool {
+	for _, r := range s.Required {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AdditionalSchema returns the schema of additional properties, and
+// whether they are allowed at all. Without a schema, any value is.
+func (s *Schema) AdditionalSchema() (*Schema, bool) {
+	raw := strings.TrimSpace(string(s.AdditionalProperties))
+	switch raw {
+	case "", "false":
+		return nil, false
+	case "true":
+		return nil, true
+	}
+	var extra Schema
+	if err := json.Unmarshal(s.AdditionalProperties, &extra); err != nil {
+		return nil, true
+	}
+	return &extra, true
+}
+
+// Parse decodes a JSON OpenAPI document.
+func Parse(data []byte) (*Document, error) {
+	var d Document
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("openapi: parse document: %w", err)
+	}
+	if !strings.HasPrefix(d.OpenAPI, "3.") {
+		return nil, fmt.Errorf("openapi: unsupported version %q; want 3.x", d.OpenAPI)
+	}
+	return &d, nil
+}
+
+// Operation returns the operation for method on path. Path parameters
+// match whatever they are named, so "/jobs/{id}" finds "/jobs/{job_id}".
+func (d *Document) Operation(method, path string) (*Operation, string) {
+	want := normalizePath(path)
+	for p, item := range d.Paths {
+		if normalizePath(p) != want || item == nil {
+			continue
+		}
+		if op := item.method(method); op != nil {
+			return op, p
+		}
+	}
+	return nil, ""
+}
+
+func (item *PathItem) method(m string) *Operation {
+	switch strings.ToUpper(m) {
+	case "GET":
+		return item.Get
+	case "PUT":
+		return item.Put
+	case "POST":
+		return item.Post
+	case "DELETE":
+		return item.Delete
+	case "PATCH":
+		return item.Patch
+	}
+	return nil
+}
+
+// Operations calls fn for each operation, in path and method order.
+func (d *Document) Operations(fn func(method, path string, op *Operation)) {
+	paths := make([]string, 0, len(d.Paths))
+	for p := range d.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		item := d.Paths[p]
+		if item == nil {
+			continue
+		}
+		for _, m := range []string{"GET", "PUT", "POST", "DELETE", "PATCH"} {
+			if op := item.method(m); op != nil {
+				fn(m, p, op)
+			}
+		}
+	}
+}
+
+// PathParams returns the names of path's parameters, in order.
+func PathParams(path string) []string {
+	var names []string
+	for {
+		i := strings.IndexByte(path, '{')
+		if i < 0 {
+			return names
+		}
+		j := strings.IndexByte(path[i:], '}')
+		if j < 0 {
+			return names
+		}
+		names = append(names, path[i+1:i+j])
+		path = path[i+j+1:]
+	}
+}
+
+// normalizePath blanks out path parameter names.
+func normalizePath(p string) string {
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(p, '{')
+		if i < 0 {
+			b.WriteString(p)
+			break
+		}
+		j := strings.IndexByte(p[i:], '}')
+		if j < 0 {
+			b.WriteString(p)
+			break
+		}
+		b.WriteString(p[:i] + "{}")
+		p = p[i+j+1:]
+	}
+	return strings.TrimSuffix(b.String(), "/")
+}
+
+// JSONSchema returns the application/json schema of b, or nil.
+func (b *Body) JSONSchema() *Schema {
+	if b == nil {
+		return nil
+	}
+	for ct, mt := range b.Content {
+		if ct == "application/json" || strings.HasSuffix(ct, "+json") {
+			return mt.Schema
+		}
+	}
+	return nil
+}
+
+// SuccessResponse returns the operation's first 2xx response, in status
+// order, or nil.
+func (op *Operation) SuccessResponse() (*Body, string) {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return nil, ""
+	}
+	sort.Strings(codes)
+	return op.Responses[codes[0]], codes[0]
+}
+
+// ErrUnresolved is returned for a $ref the document doesn't define.
+var ErrUnresolved = errors.New("openapi: unresolved reference")
+
+// Resolve follows s's $ref, if any, and merges allOf into a single
+// object schema. It returns s itself when there is nothing to do.
+func (d *Document) Resolve(s *Schema) (*Schema, error) {
+	for depth := 0; s != nil && s.Ref != ""; depth++ {
+		if depth > 32 {
+			return nil, fmt.Errorf("openapi: reference cycle at %s", s.Ref)
+		}
+		name, ok := RefName(s.Ref)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnresolved, s.Ref)
+		}
+		next, ok := d.Components.Schemas[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnresolved, s.Ref)
+		}
+		s = next
+	}
+	if s == nil || len(s.AllOf) == 0 {
+		return s, nil
+	}
+	merged := *s
+	merged.AllOf = nil
+	if merged.Properties == nil {
+		merged.Properties = make(map[string]*Schema)
+	}
+	for _, part := range s.AllOf {
+		r, err := d.Resolve(part)
+		if err != nil {
+			return nil, err
+		}
+		if len(r.Type) > 0 && len(merged.Type) == 0 {
+			merged.Type = r.Type
+		}
+		for name, p := range r.Properties {
+			merged.Properties[name] = p
+		}
+		merged.Required = append(merged.Required, r.Required...)
+	}
+	return &merged, nil
+}
+
+// RefName returns the component name a local schema reference points to.
+func RefName(ref string) (string, bool) {
+	const prefix = "#/components/schemas/"
+	if !strings.HasPrefix(ref, prefix) {
+		return "", false
+	}
+	return ref[len(prefix):], true
+}
@@ -0,0 +1,144 @@
+package strict
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// JobFilter narrows ListJobs. Zero fields match everything.
+type JobFilter struct {
+	// States keeps jobs in any of the given states.
+	States []JobState
+	// ProcessorType keeps jobs run by one processor.
+	ProcessorType ProcessorType
+	// CreatedAfter and CreatedBefore bound when jobs were submitted.
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// PageSize is how many jobs to fetch per request. Zero leaves it to
+	// the server.
+	PageSize int
+}
+
+func (f JobFilter) query(cursor string) url.Values {
+	q := make(url.Values)
+	for _, s := range f.States {
+		q.Add("status", string(s))
+	}
+	if f.ProcessorType != "" {
+		q.Set("processor_type", string(f.ProcessorType))
+	}
+	if !f.CreatedAfter.IsZero() {
+		q.Set("created_after", f.CreatedAfter.UTC().Format(time.RFC3339Nano))
+	}
+	if !f.CreatedBefore.IsZero() {
+		q.Set("created_before", f.CreatedBefore.UTC().Format(time.RFC3339Nano))
+	}
+	if f.PageSize > 0 {
+		q.Set("limit", strconv.Itoa(f.PageSize))
+	}
+	if cursor != "" {
+		q.Set("cursor", cursor)
+	}
+	return q
+}
+
+// maxEmptyJobPages bounds how many empty pages in a row a JobIterator
+// fetches before giving up, so a server that keeps handing out cursors
+// without jobs can't keep it looping.
+const maxEmptyJobPages = 16
+
+// jobPage is one page of GET /jobs.
+type jobPage struct {
+	Jobs       []JobStatus `json:"jobs"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+// JobIterator walks the jobs ListJobs matches, fetching pages as needed.
+//
+//	it := client.ListJobs(ctx, strict.JobFilter{States: []strict.JobState{strict.JobFailed}})
+//	for it.Next() {
+//		job := it.Job()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type JobIterator struct {
+	c      *Client
+	ctx    context.Context
+	filter JobFilter
+	opts   []CallOption
+
+	page    []JobStatus
+	cur     *JobStatus
+	cursor  string
+	fetched bool
+	empty   int
+	err     error
+}
+
+// ListJobs lists submitted jobs, newest first, with GET /jobs. No request
+// is made until the first call to Next.
+func (c *Client) ListJobs(ctx context.Context, filter JobFilter, opts ...CallOption) *JobIterator {
+	return &JobIterator{c: c, ctx: ctx, filter: filter, opts: opts}
+}
+
+// Next advances to the next job, fetching another page if needed. It
+// returns false when the jobs run out or a request fails; check Err. It
+// also fails if the server repeats a cursor or sends many empty pages in
+// a row.
+func (it *JobIterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.err != nil || (it.fetched && it.cursor == "") {
+			it.cur = nil
+			return false
+		}
+		it.fetch()
+	}
+	it.cur = &it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+func (it *JobIterator) fetch() {
+	var page jobPage
+	path := "/jobs?" + it.filter.query(it.cursor).Encode()
+	cl, err := newJSONCall("ListJobs", http.MethodGet, path, nil, &page, newCallOptions(it.opts))
+	if err == nil {
+		err = it.c.invoke(it.ctx, cl)
+	}
+	if err != nil {
+		it.err = err
+		return
+	}
+	if page.NextCursor != "" && page.NextCursor == it.cursor {
+		it.err = fmt.Errorf("strict: ListJobs: server returned cursor %q again", page.NextCursor)
+		return
+	}
+	if len(page.Jobs) == 0 {
+		it.empty++
+	} else {
+		it.empty = 0
+	}
+	if it.empty >= maxEmptyJobPages && page.NextCursor != "" {
+		it.err = fmt.Errorf("strict: ListJobs: %d empty pages in a row", it.empty)
+		return
+	}
+	it.fetched = true
+	it.page = page.Jobs
+	it.cursor = page.NextCursor
+}
+
+// Job returns the job Next advanced to.
+func (it *JobIterator) Job() *JobStatus {
+	return it.cur
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *JobIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,86 @@
+package strict
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestJobIteratorPaging(t *testing.T) {
+	tests := []struct {
+		name string
+		// pages maps each cursor to the page served for it.
+		pages    map[string]string
+		want     []JobID
+		wantErr  string
+		maxFetch int
+	}{
+		{
+			name: "pages",
+			pages: map[string]string{
+				"":  `{"jobs":[{"job_id":"1"},{"job_id":"2"}],"next_cursor":"a"}`,
+				"a": `{"jobs":[{"job_id":"3"}]}`,
+			},
+			want: []JobID{"1", "2", "3"},
+		},
+		{
+			name: "empty page with cursor",
+			pages: map[string]string{
+				"":  `{"jobs":[{"job_id":"1"}],"next_cursor":"a"}`,
+				"a": `{"jobs":[],"next_cursor":"b"}`,
+				"b": `{"jobs":[{"job_id":"2"}]}`,
+			},
+			want: []JobID{"1", "2"},
+		},
+		{
+			name: "repeated cursor",
+			pages: map[string]string{
+				"":  `{"jobs":[{"job_id":"1"}],"next_cursor":"a"}`,
+				"a": `{"jobs":[],"next_cursor":"a"}`,
+			},
+			want:    []JobID{"1"},
+			wantErr: `cursor "a" again`,
+		},
+		{
+			name:     "endless empty pages",
+			pages:    nil, // every cursor gets an empty page and a new cursor
+			wantErr:  "empty pages in a row",
+			maxFetch: maxEmptyJobPages,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetches := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fetches++
+				if tt.pages == nil {
+					w.Write([]byte(`{"jobs":[],"next_cursor":"c` + strconv.Itoa(fetches) + `"}`))
+					return
+				}
+				w.Write([]byte(tt.pages[r.URL.Query().Get("cursor")]))
+			}))
+			defer srv.Close()
+			it := NewClient(srv.URL, "k").ListJobs(context.Background(), JobFilter{})
+			var got []JobID
+			for it.Next() {
+				got = append(got, it.Job().ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("jobs = %v, want %v", got, tt.want)
+			}
+			switch err := it.Err(); {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Err() = %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Err() = %v, want %q", err, tt.wantErr)
+			}
+			if tt.maxFetch > 0 && fetches > tt.maxFetch {
+				t.Errorf("fetched %d pages, want at most %d", fetches, tt.maxFetch)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package strict
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// JobID identifies an asynchronous processing job.
+type JobID string
+
+// JobState is the lifecycle state of a job.
+type JobState string
+
+const (
+	JobQueued    JobState = "queued"
+	JobRunning   JobState = "running"
+	JobSucceeded JobState = "succeeded"
+	JobFailed    JobState = "failed"
+	JobCancelled JobState = "cancelled"
+)
+
+// Done reports whether the job has stopped, successfully or not.
+func (s JobState) Done() bool {
+	return s == JobSucceeded || s == JobFailed || s == JobCancelled
+}
+
+// JobStatus describes a job as the server last saw it.
+type JobStatus struct {
+	ID            JobID         `json:"job_id"`
+	State         JobState      `json:"status"`
+	ProcessorType ProcessorType `json:"processor_type,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+	// Error explains why a failed job failed.
+	Error string `json:"error,omitempty"`
+}
+
+// SubmitJob queues req for asynchronous processing with POST /jobs and
+// returns the job's ID. Use it for work that would outlast an HTTP
+// request; poll with GetJobStatus and fetch the outcome with
+// GetJobResult.
+func (c *Client) SubmitJob(ctx context.Context, req ProcessingRequest, opts ...CallOption) (JobID, error) {
+	co := newCallOptions(opts)
+	if err := c.prepareRequest(ctx, &req, co); err != nil {
+		return "", err
+	}
+	in, err := c.sealRequest(ctx, &req)
+	if err != nil {
+		return "", err
+	}
+	var st JobStatus
+	cl, err := newJSONCall("SubmitJob", http.MethodPost, "/jobs", in, &st, co)
+	if err != nil {
+		return "", err
+	}
+	cl.request = &req
+	if err := c.invoke(ctx, cl); err != nil {
+		return "", err
+	}
+	if st.ID == "" {
+		return "", errors.New("strict: server did not return a job ID")
+	}
+	return st.ID, nil
+}
+
+// GetJobStatus fetches a job's current state with GET /jobs/{id}.
+func (c *Client) GetJobStatus(ctx context.Context, id JobID, opts ...CallOption) (*JobStatus, error) {
+	var st JobStatus
+	cl, err := newJSONCall("GetJobStatus", http.MethodGet, jobPath(id), nil, &st, newCallOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.invoke(ctx, cl); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// GetJobResult fetches a finished job's output with GET
+// /jobs/{id}/result. Asking before the job has succeeded returns the
+// server's error, typically a 409 Conflict.
+func (c *Client) GetJobResult(ctx context.Context, id JobID, opts ...CallOption) (*OutputSchema, error) {
+	var output OutputSchema
+	cl, err := newJSONCall("GetJobResult", http.MethodGet, jobPath(id)+"/result", nil, &output, newCallOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.invoke(ctx, cl); err != nil {
+		return nil, err
+	}
+	output.RequestID = cl.serverRequestID
+	if err := c.openOutput(ctx, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+// CancelJob asks the server to stop a job with POST /jobs/{id}/cancel
+// and returns the job's status afterwards. Cancelling a job that has
+// already stopped is not an error; the returned status shows how it
+// ended.
+func (c *Client) CancelJob(ctx context.Context, id JobID, opts ...CallOption) (*JobStatus, error) {
+	var st JobStatus
+	cl, err := newJSONCall("CancelJob", http.MethodPost, jobPath(id)+"/cancel", nil, &st, newCallOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.invoke(ctx, cl); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// cancelJobTimeout bounds the CancelJob call AwaitJob makes after its
+// context is done.
+const cancelJobTimeout = 10 * time.Second
+
+// JobError reports a job that stopped without succeeding.
+type JobError struct {
+	ID    JobID
+	State JobState
+	// Message is the server's explanation, if any.
+	Message string
+}
+
+func (e *JobError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("strict: job %s %s", e.ID, e.State)
+	}
+	return fmt.Sprintf("strict: job %s %s: %s", e.ID, e.State, e.Message)
+}
+
+// AwaitOptions controls how AwaitJob polls. The zero value polls after
+// 500ms, backing off by 2x with 20% jitter up to every 10s.
+type AwaitOptions struct {
+	// PollInterval is the delay before the first status check.
+	PollInterval time.Duration
+	// MaxPollInterval caps the delay between checks.
+	MaxPollInterval time.Duration
+	// Multiplier grows the delay after each check.
+	Multiplier float64
+	// Jitter randomizes each delay by up to this fraction. Negative
+	// disables jitter.
+	Jitter float64
+	// OnStatus, if set, is called with every status AwaitJob sees.
+	OnStatus func(*JobStatus)
+	// CallOptions apply to every status and result call.
+	CallOptions []CallOption
+	// CancelOnDone makes AwaitJob cancel the job on the server when ctx
+	// is done, so abandoned work stops using processor quota.
+	CancelOnDone bool
+}
+
+func (o AwaitOptions) policy() RetryPolicy {
+	p := RetryPolicy{
+		InitialBackoff: o.PollInterval,
+		MaxBackoff:     o.MaxPollInterval,
+		Multiplier:     o.Multiplier,
+		Jitter:         o.Jitter,
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = 500 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 10 * time.Second
+	}
+	if p.Jitter == 0 {
+		p.Jitter = 0.2
+	} else if p.Jitter < 0 {
+		p.Jitter = 0
+	}
+	return p
+}
+
+// AwaitJob polls a job until it stops and returns its result. A job that
+// fails or is cancelled yields a *JobError. Retryable errors while
+// polling are tolerated, since the job carries on regardless; AwaitJob
+// gives up only on a permanent error or when ctx is done.
+func (c *Client) AwaitJob(ctx context.Context, id JobID, opts AwaitOptions) (*OutputSchema, error) {
+	out, err := c.awaitJob(ctx, id, opts)
+	if err != nil && opts.CancelOnDone && ctx.Err() != nil {
+		// ctx is done, so the cancel runs on a detached context of its
+		// own. Its outcome is best-effort and doesn't change err.
+		cctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cancelJobTimeout)
+		c.CancelJob(cctx, id, opts.CallOptions...)
+		cancel()
+	}
+	return out, err
+}
+
+func (c *Client) awaitJob(ctx context.Context, id JobID, opts AwaitOptions) (*OutputSchema, error) {
+	policy := opts.policy()
+	var lastErr error
+	for n := 1; ; n++ {
+		if err := sleepContext(ctx, policy.backoff(n)); err != nil {
+			if lastErr != nil {
+				return nil, lastErr
+			}
+			return nil, &transportError{err: err}
+		}
+		st, err := c.GetJobStatus(ctx, id, opts.CallOptions...)
+		if err != nil {
+			if !IsRetryable(err) || ctx.Err() != nil {
+				return nil, err
+			}
+			lastErr = err
+			continue
+		}
+		lastErr = nil
+		if opts.OnStatus != nil {
+			opts.OnStatus(st)
+		}
+		switch st.State {
+		case JobSucceeded:
+			return c.GetJobResult(ctx, id, opts.CallOptions...)
+		case JobFailed, JobCancelled:
+			return nil, &JobError{ID: id, State: st.State, Message: st.Error}
+		}
+	}
+}
+
+func jobPath(id JobID) string {
+	return "/jobs/" + url.PathEscape(string(id))
+}
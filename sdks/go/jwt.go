@@ -0,0 +1,138 @@
+package strict
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const defaultJWTLifetime = 15 * time.Minute
+
+// JWTConfig describes the JWTs WithJWT signs.
+type JWTConfig struct {
+	Issuer   string
+	Subject  string
+	Audience string
+	// KeyID is sent as the kid header so the server can pick the
+	// verification key.
+	KeyID string
+	// Lifetime is how long each token is valid. Default 15m.
+	Lifetime time.Duration
+	// Claims are added to every token. They can't override the
+	// registered claims set from the fields above.
+	Claims map[string]interface{}
+	// Key signs the tokens: a []byte secret for HS256, an
+	// *rsa.PrivateKey for RS256, or a P-256 *ecdsa.PrivateKey for ES256.
+	Key interface{}
+}
+
+// WithJWT authenticates with JWTs the client signs itself, like
+// WithTokenSource. Each token gets a fresh jti, iat and exp, and a new
+// one is signed before the last expires.
+func WithJWT(cfg JWTConfig) Option {
+	return func(c *Client) {
+		alg, err := jwtAlg(cfg.Key)
+		if err != nil {
+			c.setConfigErr(fmt.Errorf("strict: WithJWT: %w", err))
+			return
+		}
+		if cfg.Lifetime <= 0 {
+			cfg.Lifetime = defaultJWTLifetime
+		}
+		c.tokens = &tokenCache{src: &jwtSigner{cfg: cfg, alg: alg}}
+	}
+}
+
+func jwtAlg(key interface{}) (string, error) {
+	switch k := key.(type) {
+	case []byte:
+		if len(k) == 0 {
+			return "", errors.New("empty HMAC key")
+		}
+		return "HS256", nil
+	case *rsa.PrivateKey:
+		return "RS256", nil
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return "", errors.New("ECDSA key must use P-256")
+		}
+		return "ES256", nil
+	default:
+		return "", fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+type jwtSigner struct {
+	cfg JWTConfig
+	alg string
+}
+
+func (s *jwtSigner) Token(context.Context) (Token, error) {
+	now := time.Now()
+	exp := now.Add(s.cfg.Lifetime)
+	header := map[string]string{"alg": s.alg, "typ": "JWT"}
+	if s.cfg.KeyID != "" {
+		header["kid"] = s.cfg.KeyID
+	}
+	claims := make(map[string]interface{}, len(s.cfg.Claims)+6)
+	for k, v := range s.cfg.Claims {
+		claims[k] = v
+	}
+	for k, v := range map[string]string{"iss": s.cfg.Issuer, "sub": s.cfg.Subject, "aud": s.cfg.Audience} {
+		if v != "" {
+			claims[k] = v
+		}
+	}
+	claims["iat"] = now.Unix()
+	claims["exp"] = exp.Unix()
+	claims["jti"] = newUUIDv7()
+
+	h, err := json.Marshal(header)
+	if err != nil {
+		return Token{}, fmt.Errorf("strict: jwt: %w", err)
+	}
+	p, err := json.Marshal(claims)
+	if err != nil {
+		return Token{}, fmt.Errorf("strict: jwt: encode claims: %w", err)
+	}
+	enc := base64.RawURLEncoding
+	signing := enc.EncodeToString(h) + "." + enc.EncodeToString(p)
+	sig, err := s.sign([]byte(signing))
+	if err != nil {
+		return Token{}, fmt.Errorf("strict: jwt: sign: %w", err)
+	}
+	return Token{AccessToken: signing + "." + enc.EncodeToString(sig), Expiry: exp}, nil
+}
+
+func (s *jwtSigner) sign(data []byte) ([]byte, error) {
+	sum := sha256.Sum256(data)
+	switch k := s.cfg.Key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write(data)
+		return mac.Sum(nil), nil
+	case *rsa.PrivateKey:
+		return rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, sum[:])
+	case *ecdsa.PrivateKey:
+		r, ss, err := ecdsa.Sign(rand.Reader, k, sum[:])
+		if err != nil {
+			return nil, err
+		}
+		// JWS uses the fixed-width r || s form, not ASN.1.
+		sig := make([]byte, 64)
+		r.FillBytes(sig[:32])
+		ss.FillBytes(sig[32:])
+		return sig, nil
+	}
+	return nil, errors.New("unsupported key")
+}
@@ -0,0 +1,68 @@
+package strict
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// log emits a structured event if the client has a logger. Levels are
+// filtered by the logger's handler, so callers choose verbosity there:
+// per-attempt events are Debug, retries Info, and failures Warn.
+func (c *Client) log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	if c.logger == nil || !c.logger.Enabled(ctx, level) {
+		return
+	}
+	c.logger.Log(ctx, level, msg, args...)
+}
+
+func (c *Client) logRequestStart(ctx context.Context, info *RequestInfo) {
+	c.log(ctx, slog.LevelDebug, "strict: request started",
+		slog.String("method", info.Method),
+		slog.String("url", info.URL),
+		slog.Int("attempt", info.Attempt),
+		slog.Int("body_bytes", len(info.Body)),
+		slog.String("request_id", info.RequestID),
+		slog.String("correlation_id", info.CorrelationID),
+	)
+}
+
+func (c *Client) logRequestEnd(ctx context.Context, info *ResponseInfo) {
+	args := []any{
+		slog.String("method", info.Request.Method),
+		slog.String("url", info.Request.URL),
+		slog.Int("attempt", info.Request.Attempt),
+		slog.Int("status", info.StatusCode),
+		slog.Duration("latency", info.Latency),
+		slog.String("request_id", info.ServerRequestID),
+		slog.String("correlation_id", info.Request.CorrelationID),
+	}
+	if info.Err != nil {
+		args = append(args,
+			slog.String("error", info.Err.Error()),
+			slog.String("error_class", Classify(info.Err).String()),
+		)
+		c.log(ctx, slog.LevelWarn, "strict: request failed", args...)
+		return
+	}
+	c.log(ctx, slog.LevelDebug, "strict: request finished", args...)
+}
+
+func (c *Client) logRetry(ctx context.Context, attempt int, delay time.Duration, err error) {
+	c.log(ctx, slog.LevelInfo, "strict: retrying request",
+		slog.Int("attempt", attempt),
+		slog.Duration("delay", delay),
+		slog.String("error", err.Error()),
+	)
+}
+
+func (c *Client) logBreakerTransition(from, to BreakerState) {
+	level := slog.LevelInfo
+	if to == BreakerOpen {
+		level = slog.LevelWarn
+	}
+	c.log(context.Background(), level, "strict: circuit breaker state changed",
+		slog.String("from", from.String()),
+		slog.String("to", to.String()),
+	)
+}
@@ -0,0 +1,30 @@
+package strict
+
+import "net/http"
+
+// Handler sends an HTTP request and returns its response.
+type Handler func(*http.Request) (*http.Response, error)
+
+// Middleware wraps a Handler to observe or modify every HTTP request the
+// client sends, including retries.
+type Middleware func(next Handler) Handler
+
+// Use appends middleware to the client's chain. The first middleware added
+// is the outermost. Use is not safe to call concurrently with requests;
+// register middleware before sharing the client.
+func (c *Client) Use(mw ...Middleware) {
+	c.middleware = append(c.middleware, mw...)
+}
+
+// handler builds the middleware chain around the HTTP client. Debug
+// logging sits innermost so it shows exactly what goes over the wire.
+func (c *Client) handler() Handler {
+	h := Handler(c.httpClient.Do)
+	if c.debug != nil {
+		h = c.debug.middleware(h)
+	}
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		h = c.middleware[i](h)
+	}
+	return h
+}
@@ -0,0 +1,112 @@
+package strict
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// WithOAuth2 authenticates with OAuth2 bearer tokens from the client
+// credentials grant at tokenURL, like WithTokenSource.
+func WithOAuth2(clientID, clientSecret, tokenURL string, scopes ...string) Option {
+	return func(c *Client) {
+		if clientID == "" || tokenURL == "" {
+			c.setConfigErr(errors.New("strict: WithOAuth2: client ID and token URL are required"))
+			return
+		}
+		if _, err := url.Parse(tokenURL); err != nil {
+			c.setConfigErr(fmt.Errorf("strict: WithOAuth2: %w", err))
+			return
+		}
+		c.tokens = &tokenCache{src: &oauth2Source{
+			client:       c,
+			clientID:     clientID,
+			clientSecret: clientSecret,
+			tokenURL:     tokenURL,
+			scopes:       scopes,
+		}}
+	}
+}
+
+// OAuth2Error is returned when the token endpoint refuses to issue a
+// token.
+type OAuth2Error struct {
+	StatusCode int
+	// Code is the OAuth2 error code, such as "invalid_client".
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *OAuth2Error) Error() string {
+	msg := fmt.Sprintf("strict: oauth2 token request failed with status %d", e.StatusCode)
+	if e.Code != "" {
+		msg += ": " + e.Code
+	}
+	if e.Description != "" {
+		msg += ": " + e.Description
+	}
+	return msg
+}
+
+type oauth2Source struct {
+	client       *Client
+	clientID     string
+	clientSecret string
+	tokenURL     string
+	scopes       []string
+}
+
+type oauth2Token struct {
+	AccessToken string  `json:"access_token"`
+	TokenType   string  `json:"token_type"`
+	ExpiresIn   float64 `json:"expires_in"`
+}
+
+func (s *oauth2Source) Token(ctx context.Context) (Token, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(s.scopes) > 0 {
+		form.Set("scope", strings.Join(s.scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Token{}, fmt.Errorf("strict: oauth2: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", jsonContentType)
+	req.SetBasicAuth(url.QueryEscape(s.clientID), url.QueryEscape(s.clientSecret))
+	resp, err := s.client.httpClient.Do(req)
+	if err != nil {
+		return Token{}, &transportError{err: err}
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return Token{}, &transportError{err: err}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		oe := &OAuth2Error{StatusCode: resp.StatusCode}
+		json.Unmarshal(body, oe)
+		return Token{}, oe
+	}
+	var tok oauth2Token
+	if err := json.Unmarshal(body, &tok); err != nil {
+		return Token{}, fmt.Errorf("strict: oauth2: decode token: %w", err)
+	}
+	if tok.AccessToken == "" {
+		return Token{}, errors.New("strict: oauth2: token response has no access_token")
+	}
+	if tok.TokenType != "" && !strings.EqualFold(tok.TokenType, "bearer") {
+		return Token{}, fmt.Errorf("strict: oauth2: unsupported token type %q", tok.TokenType)
+	}
+	out := Token{AccessToken: tok.AccessToken}
+	if tok.ExpiresIn > 0 {
+		out.Expiry = time.Now().Add(time.Duration(tok.ExpiresIn * float64(time.Second)))
+	}
+	return out, nil
+}
@@ -0,0 +1,168 @@
+package strict
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Option configures a Client. Options are passed to NewClient.
+type Option func(*Client)
+
+// WithTimeout bounds each HTTP attempt. A zero duration disables the
+// per-attempt timeout and leaves cancellation to the caller's context.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithHTTPClient replaces the underlying *http.Client. Transport options
+// given alongside it apply to a copy; hc itself is never modified.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc != nil {
+			c.httpClient = hc
+		}
+	}
+}
+
+// WithTransport replaces the SDK's default transport with rt. Options that
+// configure the default transport, such as proxy or TLS settings, have no
+// effect on a custom transport.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.transport = rt
+	}
+}
+
+// WithTransportWrapper wraps the client's transport, whether the SDK
+// default or a custom one. Wrappers are applied in order, so the last one
+// added is outermost.
+func WithTransportWrapper(w TransportWrapper) Option {
+	return func(c *Client) {
+		c.transportWrappers = append(c.transportWrappers, w)
+	}
+}
+
+// WithHeaders adds headers that are sent with every request.
+func WithHeaders(h http.Header) Option {
+	return func(c *Client) {
+		for key, values := range h {
+			for _, v := range values {
+				c.headers.Add(key, v)
+			}
+		}
+	}
+}
+
+// WithHeader adds a single header that is sent with every request.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.headers.Add(key, value)
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// WithDefaultProcessor sends requests that don't name a processor type
+// to p. WithProcessorOverride and ProcessingRequest.ProcessorType take
+// precedence.
+func WithDefaultProcessor(p ProcessorType) Option {
+	return func(c *Client) {
+		c.processor = p
+	}
+}
+
+// WithCircuitBreaker enables a circuit breaker so that calls fail fast with
+// ErrCircuitOpen while the server is unhealthy.
+func WithCircuitBreaker(cfg BreakerConfig) Option {
+	return func(c *Client) {
+		onChange := cfg.OnStateChange
+		cfg.OnStateChange = func(from, to BreakerState) {
+			c.logBreakerTransition(from, to)
+			if onChange != nil {
+				onChange(from, to)
+			}
+		}
+		c.breaker = NewCircuitBreaker(cfg)
+	}
+}
+
+// WithRateLimit throttles requests with a client-side token bucket so that
+// bursts are smoothed before they reach the server's quota.
+func WithRateLimit(cfg RateLimitConfig) Option {
+	return func(c *Client) {
+		c.limiter = NewRateLimiter(cfg)
+		c.tenantLimiters.cfg = cfg
+	}
+}
+
+// WithMiddleware adds middleware to the client; see Client.Use.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *Client) {
+		c.Use(mw...)
+	}
+}
+
+// WithHooks registers lifecycle hooks. It may be given more than once;
+// hooks run in registration order.
+func WithHooks(h Hooks) Option {
+	return func(c *Client) {
+		c.hooks = append(c.hooks, h)
+	}
+}
+
+// WithDebug writes a dump of every HTTP request and response to w, or to
+// stderr if w is nil. Credentials headers are always redacted; values of
+// the named JSON fields (for example "input_data") are redacted as well,
+// and bodies pass through the Redactor set with WithRedactor.
+func WithDebug(w io.Writer, redactFields ...string) Option {
+	return func(c *Client) {
+		c.debug = newDebugLogger(w, redactFields)
+	}
+}
+
+// WithLogger sets a structured logger for request, retry, and circuit
+// breaker events. The client logs nothing by default.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithTracer instruments every API call with t.
+func WithTracer(t Tracer) Option {
+	return func(c *Client) {
+		c.tracer = t
+	}
+}
+
+// WithAutoIdempotencyKey controls whether POST calls without an explicit
+// key get a generated UUIDv7 Idempotency-Key. It is enabled by default.
+func WithAutoIdempotencyKey(enabled bool) Option {
+	return func(c *Client) {
+		c.autoIdempotency = enabled
+	}
+}
+
+// WithUserAgentSuffix appends an application identifier, such as
+// "billing-etl/2.3", to the SDK's User-Agent.
+func WithUserAgentSuffix(suffix string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent(suffix)
+	}
+}
+
+// setConfigErr keeps the first option error.
+func (c *Client) setConfigErr(err error) {
+	if c.configErr == nil {
+		c.configErr = err
+	}
+}
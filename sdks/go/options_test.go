@@ -0,0 +1,40 @@
+package strict
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		callOpts []CallOption
+		want     []string
+	}{
+		{"none", nil, nil, nil},
+		{"client", []Option{WithHeader("X-Team", "a")}, nil, []string{"a"}},
+		{"client repeated", []Option{WithHeader("X-Team", "a"), WithHeaders(http.Header{"X-Team": {"b"}})}, nil, []string{"a", "b"}},
+		{"call", nil, []CallOption{WithCallHeader("X-Team", "c")}, []string{"c"}},
+		{"call replaces client", []Option{WithHeader("X-Team", "a")}, []CallOption{WithCallHeader("X-Team", "c")}, []string{"c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Values("X-Team")
+				w.Write([]byte(`{"version":"1"}`))
+			}))
+			defer srv.Close()
+			if _, err := NewClient(srv.URL, "k", tt.opts...).ServerInfo(context.Background(), tt.callOpts...); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("X-Team = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
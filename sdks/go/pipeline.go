@@ -0,0 +1,282 @@
+package strict
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Pipeline chains processing stages, feeding each stage's result to the
+// next as its input:
+//
+//	p := strict.NewPipeline(c,
+//		strict.ValidateStage("validate", strict.Cloud),
+//		strict.ProcessStage("preprocess", strict.Local),
+//		strict.ProcessStage("process", strict.Cloud).Or(strict.ProcessStage("process-local", strict.Local)),
+//		strict.CheckStage("post-validate", func(out *strict.OutputSchema) error { ... }),
+//	)
+//	res, err := p.Run(ctx, input)
+//
+// A process stage's result becomes the next stage's input data: the value
+// of a JSON string result, or the result's JSON otherwise. Validate and
+// check stages pass their input on unchanged. A failed stage stops the
+// run with a *StageError unless it is Optional or has an Or fallback.
+//
+// A Pipeline is safe for concurrent use; Stats aggregates every run.
+type Pipeline struct {
+	// OnStage, if set, is called after every stage of every run, for
+	// metrics and tracing.
+	OnStage func(ctx context.Context, r StageResult)
+
+	client *Client
+	stages []Stage
+	stats  []*stageCounters
+}
+
+// Stage is one step of a Pipeline. Build stages with ProcessStage,
+// ValidateStage, CheckStage and FuncStage.
+type Stage struct {
+	Name string
+
+	run      stageFunc
+	optional bool
+	fallback *Stage
+}
+
+// stageFunc runs a stage on the current input data and the previous
+// stage's output, nil before the first process stage. It returns the
+// output to carry on with, or nil to keep prev.
+type stageFunc func(ctx context.Context, c *Client, input string, prev *OutputSchema) (*OutputSchema, error)
+
+// ProcessStage processes its input with processor p, or the client's
+// default processor if p is empty.
+func ProcessStage(name string, p ProcessorType, opts ...CallOption) Stage {
+	return Stage{Name: name, run: func(ctx context.Context, c *Client, input string, _ *OutputSchema) (*OutputSchema, error) {
+		return c.ProcessRequest(ctx, ProcessingRequest{InputData: input, ProcessorType: p}, opts...)
+	}}
+}
+
+// ValidateStage validates its input for processor p without processing
+// it, failing with a *ValidationError if the server rejects it.
+func ValidateStage(name string, p ProcessorType, opts ...CallOption) Stage {
+	return Stage{Name: name, run: func(ctx context.Context, c *Client, input string, _ *OutputSchema) (*OutputSchema, error) {
+		results, err := c.ValidateBatch(ctx, []ProcessingRequest{{InputData: input, ProcessorType: p}}, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if len(results) != 1 {
+			return nil, fmt.Errorf("strict: server returned %d validation results for 1 request", len(results))
+		}
+		if r := results[0]; !r.IsValid {
+			ve := &ValidationError{}
+			for _, msg := range r.Errors {
+				ve.Details = append(ve.Details, FieldError{Loc: []string{"body", "input_data"}, Message: msg, Type: "value_error"})
+			}
+			if len(ve.Details) == 0 {
+				ve.Details = []FieldError{{Loc: []string{"body", "input_data"}, Message: "rejected with status " + r.Status, Type: "value_error"}}
+			}
+			return nil, ve
+		}
+		return nil, nil
+	}}
+}
+
+// CheckStage calls fn with the latest output, failing the stage if fn
+// returns an error. Use it to validate results before they are used.
+func CheckStage(name string, fn func(*OutputSchema) error) Stage {
+	return Stage{Name: name, run: func(_ context.Context, _ *Client, _ string, prev *OutputSchema) (*OutputSchema, error) {
+		if prev == nil {
+			return nil, errors.New("strict: no output to check")
+		}
+		return nil, fn(prev)
+	}}
+}
+
+// FuncStage transforms the input data in-process with fn, without calling
+// the server. Its output has the transformed data as the result, which
+// feeds the next stage.
+func FuncStage(name string, fn func(ctx context.Context, input string) (string, error)) Stage {
+	return Stage{Name: name, run: func(ctx context.Context, _ *Client, input string, _ *OutputSchema) (*OutputSchema, error) {
+		out, err := fn(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		return &OutputSchema{Result: out, Validation: ValidationResult{Status: "ok", IsValid: true}}, nil
+	}}
+}
+
+// Optional returns a copy of s whose failure doesn't stop the run: the
+// failure is recorded and the stage's input passes to the next stage.
+func (s Stage) Optional() Stage {
+	s.optional = true
+	return s
+}
+
+// Or returns a copy of s that runs alt, with the same input, if s fails.
+// The stage fails only if alt fails too. alt's own Or and Optional are
+// ignored; chain fallbacks by making s Optional instead.
+func (s Stage) Or(alt Stage) Stage {
+	s.fallback = &alt
+	return s
+}
+
+// NewPipeline returns a pipeline running stages in order with c.
+func NewPipeline(c *Client, stages ...Stage) *Pipeline {
+	p := &Pipeline{client: c, stages: stages, stats: make([]*stageCounters, len(stages))}
+	for i := range p.stats {
+		p.stats[i] = new(stageCounters)
+	}
+	return p
+}
+
+// PipelineResult is the outcome of one run.
+type PipelineResult struct {
+	// Output is the last output produced, or nil if no stage produced one.
+	Output *OutputSchema
+	// Stages has a result for each stage that ran, in order.
+	Stages []StageResult
+}
+
+// StageResult is the outcome of one stage in one run.
+type StageResult struct {
+	Name string
+	// Index is the stage's position in the pipeline.
+	Index int
+	// Output is the stage's output, nil for validate and check stages
+	// and failed stages.
+	Output *OutputSchema
+	// Err is the stage's failure. It is set for skipped Optional stages
+	// too.
+	Err error
+	// Skipped is set when an Optional stage failed and the run carried
+	// on without it.
+	Skipped bool
+	// FellBack is set when the stage failed and its Or fallback ran.
+	// Cause is the stage's own failure; Err is nil if the fallback
+	// succeeded.
+	FellBack bool
+	Cause    error
+	Duration time.Duration
+}
+
+// StageError reports the stage that stopped a run.
+type StageError struct {
+	Stage string
+	Index int
+	Err   error
+}
+
+func (e *StageError) Error() string {
+	return fmt.Sprintf("strict: pipeline stage %d (%s): %v", e.Index, e.Stage, e.Err)
+}
+
+func (e *StageError) Unwrap() error { return e.Err }
+
+// Run runs the pipeline on input. On failure it returns the results so
+// far along with a *StageError.
+func (p *Pipeline) Run(ctx context.Context, input string) (*PipelineResult, error) {
+	res := &PipelineResult{}
+	for i, s := range p.stages {
+		sr := p.runStage(ctx, i, s, input, res.Output)
+		res.Stages = append(res.Stages, sr)
+		if p.OnStage != nil {
+			p.OnStage(ctx, sr)
+		}
+		switch {
+		case sr.Err != nil && !sr.Skipped:
+			return res, &StageError{Stage: s.Name, Index: i, Err: sr.Err}
+		case sr.Output != nil:
+			res.Output = sr.Output
+			next, err := nextInput(sr.Output)
+			if err != nil {
+				return res, &StageError{Stage: s.Name, Index: i, Err: err}
+			}
+			input = next
+		}
+		if err := ctx.Err(); err != nil && i < len(p.stages)-1 {
+			return res, &StageError{Stage: p.stages[i+1].Name, Index: i + 1, Err: err}
+		}
+	}
+	return res, nil
+}
+
+func (p *Pipeline) runStage(ctx context.Context, i int, s Stage, input string, prev *OutputSchema) StageResult {
+	start := time.Now()
+	sr := StageResult{Name: s.Name, Index: i}
+	sr.Output, sr.Err = s.run(ctx, p.client, input, prev)
+	if sr.Err != nil && s.fallback != nil {
+		sr.FellBack, sr.Cause = true, sr.Err
+		sr.Output, sr.Err = s.fallback.run(ctx, p.client, input, prev)
+		if sr.Err != nil {
+			sr.Err = fmt.Errorf("%w; fallback %s: %v", sr.Cause, s.fallback.Name, sr.Err)
+		}
+	}
+	sr.Skipped = sr.Err != nil && s.optional
+	sr.Duration = time.Since(start)
+	p.stats[i].observe(sr)
+	return sr
+}
+
+// nextInput derives the next stage's input data from out's result.
+func nextInput(out *OutputSchema) (string, error) {
+	raw, err := out.RawResult()
+	if err != nil {
+		return "", err
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s, nil
+	}
+	return string(raw), nil
+}
+
+// StageStats is one stage's part of Pipeline.Stats.
+type StageStats struct {
+	Name      string
+	Runs      int64
+	Failures  int64
+	Skipped   int64
+	Fallbacks int64
+	Latency   LatencySummary
+}
+
+type stageCounters struct {
+	runs, failures, skipped, fallbacks atomic.Int64
+	latency                            latencyHistogram
+}
+
+func (sc *stageCounters) observe(sr StageResult) {
+	sc.runs.Add(1)
+	if sr.Err != nil || sr.FellBack {
+		sc.failures.Add(1)
+	}
+	if sr.Skipped {
+		sc.skipped.Add(1)
+	}
+	if sr.FellBack {
+		sc.fallbacks.Add(1)
+	}
+	sc.latency.observe(sr.Duration)
+}
+
+// Stats returns each stage's counters across all runs, in stage order.
+// A failure is counted even when the run carried on past it, whether by
+// a fallback or by skipping the stage.
+func (p *Pipeline) Stats() []StageStats {
+	out := make([]StageStats, len(p.stages))
+	for i, s := range p.stages {
+		sc := p.stats[i]
+		out[i] = StageStats{
+			Name:      s.Name,
+			Runs:      sc.runs.Load(),
+			Failures:  sc.failures.Load(),
+			Skipped:   sc.skipped.Load(),
+			Fallbacks: sc.fallbacks.Load(),
+			Latency:   sc.latency.summary(),
+		}
+	}
+	return out
+}
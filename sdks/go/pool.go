@@ -0,0 +1,118 @@
+package strict
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PoolConfig tunes the default transport's connection pool. Zero fields
+// keep the net/http defaults.
+type PoolConfig struct {
+	// MaxIdleConns caps idle connections across all hosts.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost caps idle connections per host. net/http
+	// defaults to 2, which forces constant reconnects under concurrency.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost caps total connections per host; requests beyond it
+	// wait for a free connection.
+	MaxConnsPerHost int
+	// IdleConnTimeout closes connections idle for longer than this.
+	IdleConnTimeout time.Duration
+	// KeepAlive is the TCP keep-alive probe interval. Negative disables
+	// TCP keep-alives.
+	KeepAlive time.Duration
+	// DisableKeepAlives closes each connection after one request.
+	DisableKeepAlives bool
+}
+
+// WithConnectionPool applies cfg to the SDK's default transport.
+func WithConnectionPool(cfg PoolConfig) Option {
+	return func(c *Client) {
+		t := c.httpTransport
+		if cfg.MaxIdleConns > 0 {
+			t.MaxIdleConns = cfg.MaxIdleConns
+		}
+		if cfg.MaxIdleConnsPerHost > 0 {
+			t.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+		}
+		if cfg.MaxConnsPerHost > 0 {
+			t.MaxConnsPerHost = cfg.MaxConnsPerHost
+		}
+		if cfg.IdleConnTimeout > 0 {
+			t.IdleConnTimeout = cfg.IdleConnTimeout
+		}
+		if cfg.KeepAlive != 0 {
+			d := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: cfg.KeepAlive}
+			t.DialContext = d.DialContext
+		}
+		t.DisableKeepAlives = cfg.DisableKeepAlives
+	}
+}
+
+// PoolStats reports connection pool utilization. Counts are only tracked
+// for the SDK's default transport.
+type PoolStats struct {
+	// OpenConns is the number of connections dialed and not yet closed.
+	OpenConns int64
+	// InUse approximates busy connections by the number of HTTP attempts
+	// in flight. With HTTP/2 several attempts share one connection.
+	InUse int64
+	// Idle is OpenConns minus InUse, floored at zero.
+	Idle int64
+
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+}
+
+// PoolStats returns current pool utilization.
+func (c *Client) PoolStats() PoolStats {
+	s := PoolStats{
+		OpenConns:           c.pool.open.Load(),
+		InUse:               c.pool.inUse.Load(),
+		MaxIdleConns:        c.httpTransport.MaxIdleConns,
+		MaxIdleConnsPerHost: c.httpTransport.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     c.httpTransport.MaxConnsPerHost,
+	}
+	if s.Idle = s.OpenConns - s.InUse; s.Idle < 0 {
+		s.Idle = 0
+	}
+	return s
+}
+
+type poolCounters struct {
+	open  atomic.Int64
+	inUse atomic.Int64
+}
+
+// countConns wraps the default transport's dialer so open connections can
+// be counted.
+func (c *Client) countConns() {
+	dial := c.httpTransport.DialContext
+	if dial == nil {
+		dial = defaultDialer().DialContext
+	}
+	open := &c.pool.open
+	c.httpTransport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		open.Add(1)
+		return &countedConn{Conn: conn, open: open}, nil
+	}
+}
+
+type countedConn struct {
+	net.Conn
+	open *atomic.Int64
+	once sync.Once
+}
+
+func (c *countedConn) Close() error {
+	c.once.Do(func() { c.open.Add(-1) })
+	return c.Conn.Close()
+}
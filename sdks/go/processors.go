@@ -0,0 +1,59 @@
+package strict
+
+import (
+	"context"
+	"net/http"
+)
+
+// ProcessorInfo describes a processor the server can route to.
+type ProcessorInfo struct {
+	Type        ProcessorType `json:"type"`
+	Description string        `json:"description,omitempty"`
+	// Available is false while the processor is down or disabled.
+	Available bool `json:"available"`
+	// MaxTokens is the largest input, in tokens, the processor accepts.
+	// Zero means no limit.
+	MaxTokens   int          `json:"max_tokens"`
+	SignalTypes []SignalType `json:"signal_types"`
+	// ExpectedLatencyMs is the server's estimate of a typical request's
+	// processing time.
+	ExpectedLatencyMs float64 `json:"expected_latency_ms"`
+	// CostTier ranks the processor's cost, such as "free", "standard" or
+	// "premium".
+	CostTier string `json:"cost_tier,omitempty"`
+}
+
+// Accepts reports whether the processor is available and can take an
+// input of the given size.
+func (p ProcessorInfo) Accepts(tokens int) bool {
+	return p.Available && (p.MaxTokens == 0 || tokens <= p.MaxTokens)
+}
+
+// SupportsSignal reports whether the processor handles signal type s.
+func (p ProcessorInfo) SupportsSignal(s SignalType) bool {
+	for _, t := range p.SignalTypes {
+		if t == s {
+			return true
+		}
+	}
+	return false
+}
+
+type processorList struct {
+	Processors []ProcessorInfo `json:"processors"`
+}
+
+// ListProcessors lists the server's processors and their capabilities
+// with GET /processors, for routing on data rather than on the built-in
+// processor types.
+func (c *Client) ListProcessors(ctx context.Context, opts ...CallOption) ([]ProcessorInfo, error) {
+	var list processorList
+	cl, err := newJSONCall("ListProcessors", http.MethodGet, "/processors", nil, &list, newCallOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.invoke(ctx, cl); err != nil {
+		return nil, err
+	}
+	return list.Processors, nil
+}
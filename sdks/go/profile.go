@@ -0,0 +1,405 @@
+package strict
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultProfile is the profile used when none is named.
+const DefaultProfile = "default"
+
+// ErrNoProfile is returned by LoadProfile when the config file has no
+// profile by the requested name.
+var ErrNoProfile = errors.New("strict: no such profile")
+
+// Profile is one named section of the config file, ~/.strict/config.
+// The file is TOML, with a table per profile:
+//
+//	[default]
+//	base_url = "https://strict.example.com"
+//	api_key = "sk_live_..."
+//	timeout = "30s"
+//
+//	[staging]
+//	base_urls = ["https://staging-a.example.com", "https://staging-b.example.com"]
+//	api_key = "sk_test_..."
+//	max_retries = 3
+//
+// Other keys are proxy, api_version, tenant, ca_cert_file and
+// user_agent_suffix, with the same meaning as the STRICT_* environment
+// variables. Unknown keys are an error, to catch typos.
+type Profile struct {
+	Name string
+	// BaseURLs is the base URL followed by any fallbacks.
+	BaseURLs   []string
+	APIKey     string
+	Timeout    time.Duration
+	MaxRetries int // -1 if unset
+	Proxy      string
+	APIVersion string
+	Tenant     string
+	CACertFile string
+	// UserAgentSuffix is passed to WithUserAgentSuffix.
+	UserAgentSuffix string
+}
+
+// DefaultConfigPath returns $STRICT_CONFIG_FILE, or ~/.strict/config.
+func DefaultConfigPath() (string, error) {
+	if p := os.Getenv(EnvConfigFile); p != "" {
+		return p, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("strict: locate config file: %w", err)
+	}
+	return filepath.Join(home, ".strict", "config"), nil
+}
+
+// LoadProfile reads profile name from the config file at path. An empty
+// path means DefaultConfigPath, and an empty name means $STRICT_PROFILE,
+// or "default".
+func LoadProfile(path, name string) (*Profile, error) {
+	if path == "" {
+		var err error
+		if path, err = DefaultConfigPath(); err != nil {
+			return nil, err
+		}
+	}
+	if name == "" {
+		name = os.Getenv(EnvProfile)
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("strict: read config: %w", err)
+	}
+	tables, err := parseTOML(data)
+	if err != nil {
+		return nil, fmt.Errorf("strict: %s: %w", path, err)
+	}
+	table, ok := tables[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q in %s", ErrNoProfile, name, path)
+	}
+	p, err := profileFromTable(name, table)
+	if err != nil {
+		return nil, fmt.Errorf("strict: %s: profile %q: %w", path, name, err)
+	}
+	return p, nil
+}
+
+// ProfileNames returns the names of the profiles in the config file at
+// path, sorted. An empty path means DefaultConfigPath.
+func ProfileNames(path string) ([]string, error) {
+	if path == "" {
+		var err error
+		if path, err = DefaultConfigPath(); err != nil {
+			return nil, err
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("strict: read config: %w", err)
+	}
+	tables, err := parseTOML(data)
+	if err != nil {
+		return nil, fmt.Errorf("strict: %s: %w", path, err)
+	}
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// WithProfile applies the named profile from the default config file,
+// replacing the base URL and API key given to NewClient if the profile
+// sets them. Options after it override the profile's settings.
+func WithProfile(name string) Option {
+	return func(c *Client) {
+		p, err := LoadProfile("", name)
+		if err != nil {
+			c.setConfigErr(err)
+			return
+		}
+		for _, opt := range p.Options() {
+			opt(c)
+		}
+	}
+}
+
+// Options returns the client options that apply p.
+func (p *Profile) Options() []Option {
+	var opts []Option
+	if len(p.BaseURLs) > 0 {
+		opts = append(opts, withBaseURLs(p.BaseURLs))
+	}
+	if p.APIKey != "" {
+		opts = append(opts, withAPIKey(p.APIKey))
+	}
+	if p.Timeout > 0 {
+		opts = append(opts, WithTimeout(p.Timeout))
+	}
+	if p.MaxRetries >= 0 {
+		rp := DefaultRetryPolicy()
+		rp.MaxAttempts = p.MaxRetries + 1
+		opts = append(opts, WithRetryPolicy(rp))
+	}
+	if p.Proxy != "" {
+		opts = append(opts, WithProxy(p.Proxy))
+	}
+	if p.APIVersion != "" {
+		opts = append(opts, WithAPIVersion(p.APIVersion))
+	}
+	if p.Tenant != "" {
+		opts = append(opts, WithTenant(p.Tenant))
+	}
+	if p.CACertFile != "" {
+		opts = append(opts, WithCACertFile(p.CACertFile))
+	}
+	if p.UserAgentSuffix != "" {
+		opts = append(opts, WithUserAgentSuffix(p.UserAgentSuffix))
+	}
+	return opts
+}
+
+// envProfileOptions returns the options for the profile NewClientFromEnv
+// uses: the one named by STRICT_PROFILE, which must exist, or else the
+// default profile if there is a config file.
+func envProfileOptions() ([]Option, error) {
+	name := os.Getenv(EnvProfile)
+	p, err := LoadProfile("", name)
+	if name == "" && (errors.Is(err, fs.ErrNotExist) || errors.Is(err, ErrNoProfile)) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.Options(), nil
+}
+
+// withBaseURLs replaces the base URL, and any endpoints set before it,
+// with urls.
+func withBaseURLs(urls []string) Option {
+	return func(c *Client) {
+		if len(urls) > 1 {
+			WithBaseURLs(urls...)(c)
+			return
+		}
+		c.BaseURL = urls[0]
+		c.endpoints = nil
+	}
+}
+
+// withAPIKey replaces the API key given to NewClient.
+func withAPIKey(key string) Option {
+	return func(c *Client) { c.APIKey = key }
+}
+
+func profileFromTable(name string, t map[string]interface{}) (*Profile, error) {
+	p := &Profile{Name: name, MaxRetries: -1}
+	for k, v := range t {
+		var err error
+		switch k {
+		case "base_url":
+			var s string
+			if s, err = tomlString(k, v); err == nil {
+				p.BaseURLs = append([]string{s}, p.BaseURLs...)
+			}
+		case "base_urls":
+			list, ok := v.([]string)
+			if !ok {
+				err = fmt.Errorf("%s: want an array of strings", k)
+			}
+			p.BaseURLs = append(p.BaseURLs, list...)
+		case "api_key":
+			p.APIKey, err = tomlString(k, v)
+		case "timeout":
+			switch d := v.(type) {
+			case string:
+				p.Timeout, err = parseEnvDuration(d)
+			case int64:
+				p.Timeout = time.Duration(d) * time.Second
+			case float64:
+				p.Timeout = time.Duration(d * float64(time.Second))
+			default:
+				err = fmt.Errorf("%s: want a duration", k)
+			}
+		case "max_retries":
+			n, ok := v.(int64)
+			if !ok || n < 0 {
+				err = fmt.Errorf("%s: want a non-negative integer", k)
+			}
+			p.MaxRetries = int(n)
+		case "proxy":
+			p.Proxy, err = tomlString(k, v)
+		case "api_version":
+			p.APIVersion, err = tomlString(k, v)
+		case "tenant":
+			p.Tenant, err = tomlString(k, v)
+		case "ca_cert_file":
+			p.CACertFile, err = tomlString(k, v)
+		case "user_agent_suffix":
+			p.UserAgentSuffix, err = tomlString(k, v)
+		default:
+			err = fmt.Errorf("unknown key %q", k)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func tomlString(key string, v interface{}) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%s: want a string", key)
+	}
+	return s, nil
+}
+
+// parseTOML parses the subset of TOML the config file uses: tables of
+// key = value pairs, where values are strings, integers, floats,
+// booleans, or single-line arrays of strings. Dotted and inline tables
+// aren't supported.
+func parseTOML(data []byte) (map[string]map[string]interface{}, error) {
+	tables := make(map[string]map[string]interface{})
+	var table map[string]interface{}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(stripTOMLComment(sc.Text()))
+		if line == "" {
+			continue
+		}
+		if line[0] == '[' {
+			if !strings.HasSuffix(line, "]") || strings.HasPrefix(line, "[[") {
+				return nil, fmt.Errorf("line %d: malformed table header", n)
+			}
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if unq, err := strconv.Unquote(name); err == nil {
+				name = unq
+			}
+			if _, dup := tables[name]; dup {
+				return nil, fmt.Errorf("line %d: table %q defined twice", n, name)
+			}
+			table = make(map[string]interface{})
+			tables[name] = table
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", n)
+		}
+		if table == nil {
+			return nil, fmt.Errorf("line %d: key outside a profile table", n)
+		}
+		val, err := parseTOMLValue(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		table[strings.TrimSpace(k)] = val
+	}
+	return tables, sc.Err()
+}
+
+func parseTOMLValue(v string) (interface{}, error) {
+	switch {
+	case v == "true" || v == "false":
+		return v == "true", nil
+	case strings.HasPrefix(v, `"`):
+		return strconv.Unquote(v)
+	case strings.HasPrefix(v, "'"):
+		if len(v) < 2 || !strings.HasSuffix(v, "'") {
+			return nil, errors.New("unterminated string")
+		}
+		return v[1 : len(v)-1], nil
+	case strings.HasPrefix(v, "["):
+		if !strings.HasSuffix(v, "]") {
+			return nil, errors.New("arrays must be on one line")
+		}
+		var list []string
+		for _, item := range splitTOMLArray(v[1 : len(v)-1]) {
+			s, err := parseTOMLValue(item)
+			if err != nil {
+				return nil, err
+			}
+			str, ok := s.(string)
+			if !ok {
+				return nil, errors.New("only arrays of strings are supported")
+			}
+			list = append(list, str)
+		}
+		return list, nil
+	}
+	clean := strings.ReplaceAll(v, "_", "")
+	if i, err := strconv.ParseInt(clean, 10, 64); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(clean, 64); err == nil {
+		return f, nil
+	}
+	return nil, fmt.Errorf("unsupported value %q", v)
+}
+
+// splitTOMLArray splits array items at commas outside strings.
+func splitTOMLArray(s string) []string {
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			items = append(items, s[start:i])
+			start = i + 1
+		}
+	}
+	items = append(items, s[start:])
+	out := items[:0]
+	for _, it := range items {
+		if it = strings.TrimSpace(it); it != "" {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+// stripTOMLComment removes a trailing # comment outside strings.
+func stripTOMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,154 @@
+package strict
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// Progress is an intermediate update on a request being processed.
+type Progress struct {
+	// Percent is how much of the work is done, from 0 to 100.
+	Percent float64 `json:"percent"`
+	// Stage names the current processing step.
+	Stage string `json:"stage"`
+	// Partial is the result so far, if the server reports one.
+	Partial interface{} `json:"partial_result,omitempty"`
+}
+
+// ProcessRequestWithProgress is like ProcessRequest, but asks the server
+// to stream Server-Sent Events and sends each progress update to
+// progress before returning the final result. Sends block until received
+// or ctx is done, so read progress from another goroutine. progress is
+// closed before the call returns.
+//
+// The server sends "progress" events, then a "result" event with the
+// OutputSchema or an "error" event shaped like an error response body,
+// with an optional "status_code". A server that answers with plain JSON
+// is handled like ProcessRequest, with no progress updates. Responses
+// are not cached, deduplicated or hedged.
+func (c *Client) ProcessRequestWithProgress(ctx context.Context, req ProcessingRequest, progress chan<- Progress, opts ...CallOption) (*OutputSchema, error) {
+	defer close(progress)
+	co := newCallOptions(opts)
+	if err := c.prepareRequest(ctx, &req, co); err != nil {
+		return nil, err
+	}
+	in, err := c.sealRequest(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		return nil, fmt.Errorf("strict: encode request: %w", err)
+	}
+	if timeout := callTimeout(req, co); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	co.headers = co.headers.Clone()
+	co.headers.Set("Accept", "text/event-stream, application/json;q=0.9")
+
+	cl := &call{
+		op:       "ProcessRequestWithProgress",
+		method:   http.MethodPost,
+		path:     "/process/request",
+		body:     data,
+		opts:     co,
+		request:  &req,
+		keepBody: true,
+	}
+	if err := c.do(ctx, cl); err != nil {
+		return nil, err
+	}
+	defer cl.respBody.Close()
+
+	var output OutputSchema
+	if mt, _, _ := mime.ParseMediaType(cl.respHeader.Get("Content-Type")); mt != "text/event-stream" {
+		data, err := io.ReadAll(cl.respBody)
+		if err == nil {
+			err = c.decodeJSON(data, &output)
+		}
+		if _, ok := err.(*UnknownFieldsError); ok {
+			return nil, err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("strict: decode response: %w", err)
+		}
+		output.RequestID = cl.serverRequestID
+		if err := c.verifyInputHash(ComputeInputHash(req), &output); err != nil {
+			return nil, err
+		}
+		if err := c.openOutput(ctx, &output); err != nil {
+			return nil, err
+		}
+		return &output, nil
+	}
+
+	events := newSSEReader(cl.respBody)
+	for {
+		ev, err := events.next()
+		if err == io.EOF {
+			return nil, errors.New("strict: event stream ended without a result")
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, &transportError{err: ctx.Err()}
+			}
+			return nil, &transportError{err: err}
+		}
+		switch ev.Event {
+		case "progress":
+			var p Progress
+			if err := json.Unmarshal([]byte(ev.Data), &p); err != nil {
+				return nil, fmt.Errorf("strict: decode progress event: %w", err)
+			}
+			select {
+			case progress <- p:
+			case <-ctx.Done():
+				return nil, &transportError{err: ctx.Err()}
+			}
+		case "result":
+			if err := c.decodeJSON([]byte(ev.Data), &output); err != nil {
+				if _, ok := err.(*UnknownFieldsError); ok {
+					return nil, err
+				}
+				return nil, fmt.Errorf("strict: decode response: %w", err)
+			}
+			output.RequestID = cl.serverRequestID
+			if err := c.verifyInputHash(ComputeInputHash(req), &output); err != nil {
+				return nil, err
+			}
+			if err := c.openOutput(ctx, &output); err != nil {
+				return nil, err
+			}
+			return &output, nil
+		case "error":
+			return nil, eventError(ev, cl.serverRequestID)
+		}
+	}
+}
+
+// eventError converts an "error" event into an APIError, as if its data
+// had been an error response body.
+func eventError(ev sseEvent, requestID string) error {
+	var status struct {
+		StatusCode int `json:"status_code"`
+	}
+	json.Unmarshal([]byte(ev.Data), &status)
+	if status.StatusCode == 0 {
+		status.StatusCode = http.StatusInternalServerError
+	}
+	apiErr := newAPIError(&http.Response{
+		StatusCode: status.StatusCode,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(bytes.NewReader([]byte(ev.Data))),
+	})
+	apiErr.fillRequestID(requestID)
+	return apiErr
+}
@@ -0,0 +1,86 @@
+package strict
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// protocolRetryAfter is how long a failed preferred protocol is skipped
+// before the client tries it again.
+const protocolRetryAfter = 5 * time.Minute
+
+// WithHTTP2PriorKnowledge speaks HTTP/2 without an upgrade handshake to
+// http:// endpoints (h2c), which gateways inside a trusted network often
+// expose. https:// endpoints already negotiate HTTP/2 via ALPN. If the
+// server rejects h2c the request is resent over HTTP/1.1.
+func WithHTTP2PriorKnowledge() Option {
+	return func(c *Client) {
+		c.h2PriorKnowledge = true
+	}
+}
+
+// WithHTTP3 sends requests through rt, an HTTP/3 (QUIC) round tripper such
+// as the one provided by quic-go, falling back to the default transport
+// when a QUIC connection cannot be established.
+func WithHTTP3(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.http3 = rt
+	}
+}
+
+// defaultRoundTripper returns the SDK's transport with any preferred
+// protocols layered in front of it.
+func (c *Client) defaultRoundTripper() http.RoundTripper {
+	var rt http.RoundTripper = c.httpTransport
+	if c.h2PriorKnowledge {
+		h2c := c.httpTransport.Clone()
+		h2c.Protocols = new(http.Protocols)
+		h2c.Protocols.SetUnencryptedHTTP2(true)
+		h2c.Protocols.SetHTTP2(true)
+		rt = &fallbackTransport{preferred: h2c, fallback: rt}
+	}
+	if c.http3 != nil {
+		rt = &fallbackTransport{preferred: c.http3, fallback: rt}
+	}
+	return rt
+}
+
+// fallbackTransport tries a preferred transport and resends on the
+// fallback if it fails before producing a response. After a failure the
+// preferred transport is skipped for protocolRetryAfter.
+type fallbackTransport struct {
+	preferred http.RoundTripper
+	fallback  http.RoundTripper
+	// skipUntil holds a Unix nanosecond timestamp.
+	skipUntil atomic.Int64
+}
+
+func (t *fallbackTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if time.Now().UnixNano() < t.skipUntil.Load() {
+		return t.fallback.RoundTrip(req)
+	}
+	resp, err := t.preferred.RoundTrip(req)
+	if err == nil {
+		return resp, nil
+	}
+	if req.Context().Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return nil, err
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return nil, err
+	}
+	t.skipUntil.Store(time.Now().Add(protocolRetryAfter).UnixNano())
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, bodyErr := req.GetBody()
+		if bodyErr != nil {
+			return nil, err
+		}
+		retry.Body = body
+	}
+	return t.fallback.RoundTrip(retry)
+}
@@ -0,0 +1,35 @@
+package strict
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// WithProxy routes requests through the proxy at proxyURL. Supported
+// schemes are http, https, socks5 and socks5h. An empty string disables
+// proxying entirely, including proxies configured through HTTPS_PROXY.
+//
+// Without this option the client honors HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY from the environment. The option only affects the SDK's default
+// transport.
+func WithProxy(proxyURL string) Option {
+	return func(c *Client) {
+		if proxyURL == "" {
+			c.httpTransport.Proxy = nil
+			return
+		}
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			c.setConfigErr(fmt.Errorf("strict: invalid proxy URL: %w", err))
+			return
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			c.setConfigErr(fmt.Errorf("strict: unsupported proxy scheme %q", u.Scheme))
+			return
+		}
+		c.httpTransport.Proxy = http.ProxyURL(u)
+	}
+}
@@ -0,0 +1,317 @@
+package strict
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// QueuedRequest is a request waiting in an OfflineQueue.
+type QueuedRequest struct {
+	// ID identifies the request in the queue's store. It is also sent as
+	// the request's idempotency key, so a request resent after a crash
+	// isn't processed twice.
+	ID       string            `json:"id"`
+	Request  ProcessingRequest `json:"request"`
+	Enqueued time.Time         `json:"enqueued"`
+	// Attempts counts the failed attempts to send the request.
+	Attempts int `json:"attempts,omitempty"`
+	// Errors is the request's recent error history, oldest first.
+	Errors []AttemptError `json:"errors,omitempty"`
+}
+
+// QueueStore persists an OfflineQueue's requests. Implementations must be
+// safe for concurrent use.
+type QueueStore interface {
+	// Put adds item, or replaces the item with the same ID.
+	Put(item QueuedRequest) error
+	// List returns the queued items, oldest first.
+	List() ([]QueuedRequest, error)
+	// Delete removes the item with the given ID, if there is one.
+	Delete(id string) error
+}
+
+// OfflineQueue accepts requests whether or not the server is reachable,
+// persists them, and sends them in order once it is, for edge deployments
+// with unreliable connectivity:
+//
+//	store, err := strict.NewFileQueueStore("/var/lib/app/queue")
+//	...
+//	q := &strict.OfflineQueue{Client: c, Store: store, OnResult: handle}
+//	go q.Run(ctx)
+//	id, err := q.Enqueue(req)
+//
+// A request that fails with a retryable error, such as a network error or
+// a 503, stays at the head of the queue and Run backs off before trying
+// again; it is the queue's probe for connectivity. Call Notify when the
+// platform reports the network is back to try again at once. Requests
+// left in the store when the process exits are sent by the next Run.
+// Requests that fail for good are also kept in the client's dead-letter
+// store, if it has one; see WithDeadLetters.
+//
+// The fields must not change after the first Enqueue or Run.
+type OfflineQueue struct {
+	Client *Client
+	Store  QueueStore
+	// OnResult is called with each request's outcome once it is
+	// processed or fails for good, from Run's goroutine.
+	OnResult func(item QueuedRequest, out *OutputSchema, err error)
+	// MaxAttempts bounds the attempts to send a request, after which it
+	// fails with its last error. Zero means no limit: requests are kept
+	// until they succeed or fail with an error that isn't retryable.
+	MaxAttempts int
+	// Backoff spaces attempts while the server is unreachable; only its
+	// backoff fields are used. The default starts at a second and grows
+	// to a minute.
+	Backoff RetryPolicy
+	// CallOptions apply to every request.
+	CallOptions []CallOption
+
+	once sync.Once
+	wake chan struct{}
+}
+
+var defaultQueueBackoff = RetryPolicy{
+	InitialBackoff: time.Second,
+	MaxBackoff:     time.Minute,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+func (q *OfflineQueue) init() {
+	q.wake = make(chan struct{}, 1)
+}
+
+// Enqueue stores req to be sent and returns its ID. It fails only if the
+// store does.
+func (q *OfflineQueue) Enqueue(req ProcessingRequest) (string, error) {
+	if q.Store == nil {
+		return "", errors.New("strict: OfflineQueue has no Store")
+	}
+	item := QueuedRequest{ID: newUUIDv7(), Request: req, Enqueued: time.Now()}
+	if err := q.Store.Put(item); err != nil {
+		return "", err
+	}
+	q.Notify()
+	return item.ID, nil
+}
+
+// Notify wakes Run to try sending queued requests now, rather than when
+// its backoff ends.
+func (q *OfflineQueue) Notify() {
+	q.once.Do(q.init)
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run sends queued requests until ctx is done, when it returns ctx.Err().
+// It also returns if the store fails. Run must not be called
+// concurrently with itself.
+func (q *OfflineQueue) Run(ctx context.Context) error {
+	switch {
+	case q.Client == nil:
+		return errors.New("strict: OfflineQueue has no Client")
+	case q.Store == nil:
+		return errors.New("strict: OfflineQueue has no Store")
+	}
+	q.once.Do(q.init)
+	backoff := q.Backoff
+	if backoff.InitialBackoff <= 0 {
+		backoff = defaultQueueBackoff
+	}
+	failures := 0
+	for {
+		offline, err := q.drain(ctx)
+		if err != nil {
+			return err
+		}
+		// Online, wait for the next Enqueue; offline, for the backoff too.
+		var (
+			t     *time.Timer
+			retry <-chan time.Time
+		)
+		if offline {
+			failures++
+			t = time.NewTimer(backoff.backoff(failures))
+			retry = t.C
+		} else {
+			failures = 0
+		}
+		select {
+		case <-ctx.Done():
+		case <-q.wake:
+		case <-retry:
+		}
+		if t != nil {
+			t.Stop()
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// drain sends queued requests in order until the queue is empty or one
+// fails with a retryable error, when it reports the server offline.
+func (q *OfflineQueue) drain(ctx context.Context) (offline bool, err error) {
+	items, err := q.Store.List()
+	if err != nil {
+		return false, err
+	}
+	for _, item := range items {
+		log := new(attemptLog)
+		opts := append(append([]CallOption(nil), q.CallOptions...), WithIdempotencyKey(item.ID), withAttemptLog(log))
+		out, err := q.Client.ProcessRequest(ctx, item.Request, opts...)
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if err == nil {
+			if err := q.Store.Delete(item.ID); err != nil {
+				return false, err
+			}
+			q.deliver(item, out, nil)
+			continue
+		}
+		item.Attempts++
+		item.Errors = append(item.Errors, log.history(err)...)
+		if len(item.Errors) > maxDeadLetterErrors {
+			item.Errors = item.Errors[len(item.Errors)-maxDeadLetterErrors:]
+		}
+		if IsRetryable(err) && (q.MaxAttempts <= 0 || item.Attempts < q.MaxAttempts) {
+			return true, q.Store.Put(item)
+		}
+		q.Client.deadLetter(ctx, item.Request, item.Errors)
+		if err := q.Store.Delete(item.ID); err != nil {
+			return false, err
+		}
+		q.deliver(item, nil, err)
+	}
+	return false, nil
+}
+
+func (q *OfflineQueue) deliver(item QueuedRequest, out *OutputSchema, err error) {
+	if q.OnResult != nil {
+		q.OnResult(item, out, err)
+	}
+}
+
+const queueFileExt = ".queued"
+
+// FileQueueStore is a QueueStore that keeps one file per request in a
+// directory. Writes are atomic, so a crash never leaves a partial entry.
+type FileQueueStore struct {
+	dir string
+}
+
+// NewFileQueueStore opens or creates a queue store in dir.
+func NewFileQueueStore(dir string) (*FileQueueStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileQueueStore{dir: dir}, nil
+}
+
+func (s *FileQueueStore) Put(item QueuedRequest) error {
+	return writeJSONFile(s.dir, hashedPath(s.dir, item.ID, queueFileExt), item)
+}
+
+// List skips and removes entries that can't be decoded.
+func (s *FileQueueStore) List() ([]QueuedRequest, error) {
+	var items []QueuedRequest
+	err := readJSONDir(s.dir, queueFileExt, func(data []byte) error {
+		var item QueuedRequest
+		if err := json.Unmarshal(data, &item); err != nil {
+			return err
+		}
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].Enqueued.Equal(items[j].Enqueued) {
+			return items[i].Enqueued.Before(items[j].Enqueued)
+		}
+		return items[i].ID < items[j].ID
+	})
+	return items, nil
+}
+
+func (s *FileQueueStore) Delete(id string) error {
+	return removeFile(hashedPath(s.dir, id, queueFileExt))
+}
+
+// hashedPath hashes id into a safe file name in dir.
+func hashedPath(dir, id, ext string) string {
+	sum := sha256.Sum256([]byte(id))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+ext)
+}
+
+// writeJSONFile atomically replaces path, in dir, with v as JSON.
+func writeJSONFile(dir, path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	_, werr := tmp.Write(data)
+	if werr == nil {
+		werr = tmp.Sync()
+	}
+	cerr := tmp.Close()
+	if err := errors.Join(werr, cerr); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// readJSONDir calls decode with the contents of each file in dir with
+// extension ext, removing those decode fails on.
+func readJSONDir(dir, ext string, decode func(data []byte) error) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ext) {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if decode(data) != nil {
+			os.Remove(path)
+		}
+	}
+	return nil
+}
+
+func removeFile(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package strict
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Quota is the client's latest view of its limits, taken from the
+// headers of the most recent response that carried them.
+type Quota struct {
+	// RateLimit is the short-window request rate limit, from
+	// X-RateLimit-* (or RateLimit-*) headers.
+	RateLimit RateLimitInfo
+	// Usage is the longer-term usage quota, from X-Quota-* headers.
+	Usage RateLimitInfo
+	// UpdatedAt is when the limits were last reported. It is zero until
+	// a response carries any limit headers.
+	UpdatedAt time.Time
+}
+
+type quotaTracker struct {
+	mu sync.Mutex
+	q  Quota
+}
+
+// update records the limits in h. Each group of headers is kept until a
+// later response reports it again, so a response that only carries rate
+// limit headers doesn't clear the usage quota.
+func (t *quotaTracker) update(h http.Header, now time.Time) {
+	rate, rateOK := parseLimitHeaders(h, now, "X-RateLimit-", "RateLimit-")
+	usage, usageOK := parseLimitHeaders(h, now, "X-Quota-")
+	if !rateOK && !usageOK {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if rateOK {
+		t.q.RateLimit = rate
+	}
+	if usageOK {
+		t.q.Usage = usage
+	}
+	t.q.UpdatedAt = now
+}
+
+// Quota returns the limits reported by the server on recent responses,
+// so callers can throttle themselves before being rejected. It is safe
+// for concurrent use.
+func (c *Client) Quota() Quota {
+	c.quota.mu.Lock()
+	defer c.quota.mu.Unlock()
+	return c.quota.q
+}
+
+// UsagePeriod selects the window GetUsage reports on.
+type UsagePeriod string
+
+const (
+	UsageHour  UsagePeriod = "hour"
+	UsageDay   UsagePeriod = "day"
+	UsageMonth UsagePeriod = "month"
+)
+
+// Usage is the caller's consumption over one period.
+type Usage struct {
+	Period      UsagePeriod `json:"period"`
+	Start       time.Time   `json:"start"`
+	End         time.Time   `json:"end"`
+	Requests    int64       `json:"requests"`
+	InputTokens int64       `json:"input_tokens"`
+	Errors      int64       `json:"errors"`
+	// Quota is the number of requests allowed in the period. Zero means
+	// unlimited.
+	Quota       int64                            `json:"quota"`
+	ByProcessor map[ProcessorType]ProcessorUsage `json:"by_processor,omitempty"`
+}
+
+// ProcessorUsage is the per-processor-type part of Usage.
+type ProcessorUsage struct {
+	Requests    int64 `json:"requests"`
+	InputTokens int64 `json:"input_tokens"`
+}
+
+// GetUsage fetches the caller's usage for the current period with GET
+// /usage. An empty period uses the server's default.
+func (c *Client) GetUsage(ctx context.Context, period UsagePeriod, opts ...CallOption) (*Usage, error) {
+	path := "/usage"
+	if period != "" {
+		path += "?" + url.Values{"period": {string(period)}}.Encode()
+	}
+	var usage Usage
+	cl, err := newJSONCall("GetUsage", http.MethodGet, path, nil, &usage, newCallOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.invoke(ctx, cl); err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
@@ -0,0 +1,113 @@
+package strict
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRateLimitExceeded is returned when the client-side rate limiter is in
+// reject mode and has no tokens available.
+var ErrRateLimitExceeded = errors.New("strict: client rate limit exceeded")
+
+// RateLimitConfig configures a client-side token bucket.
+type RateLimitConfig struct {
+	// RequestsPerSecond is the refill rate of the bucket.
+	RequestsPerSecond float64
+	// Burst is the bucket capacity. Defaults to 1.
+	Burst int
+	// Reject makes requests fail with ErrRateLimitExceeded instead of
+	// waiting for a token.
+	Reject bool
+}
+
+// RateLimiter is a token bucket shared by all requests made through a
+// Client. It is safe for concurrent use.
+type RateLimiter struct {
+	rate   float64
+	burst  float64
+	reject bool
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a full bucket.
+func NewRateLimiter(cfg RateLimitConfig) *RateLimiter {
+	burst := cfg.Burst
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   cfg.RequestsPerSecond,
+		burst:  float64(burst),
+		reject: cfg.Reject,
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Allow takes a token if one is available without waiting.
+func (l *RateLimiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(time.Now())
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// Wait blocks until a token is available or ctx is done. In reject mode it
+// returns ErrRateLimitExceeded instead of blocking.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l.reject {
+		if !l.Allow() {
+			return ErrRateLimitExceeded
+		}
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.refill(now)
+	l.tokens--
+	var wait time.Duration
+	if l.tokens < 0 {
+		if l.rate <= 0 {
+			l.tokens++
+			l.mu.Unlock()
+			return ErrRateLimitExceeded
+		}
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		l.cancel()
+		return context.DeadlineExceeded
+	}
+	if err := sleepContext(ctx, wait); err != nil {
+		l.cancel()
+		return err
+	}
+	return nil
+}
+
+// cancel returns a reserved token that was not used.
+func (l *RateLimiter) cancel() {
+	l.mu.Lock()
+	l.tokens++
+	l.mu.Unlock()
+}
+
+func (l *RateLimiter) refill(now time.Time) {
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+}
@@ -0,0 +1,123 @@
+package strict
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitInfo is the server's view of the caller's quota, parsed from
+// X-RateLimit-* (or IETF RateLimit-*) response headers. Fields the server
+// did not send are left at their zero value.
+type RateLimitInfo struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// RateLimitError is returned when the server rejects a request with 429
+// Too Many Requests. It unwraps to the underlying *APIError.
+type RateLimitError struct {
+	*APIError
+	// RetryAfter is how long the server asked the caller to wait. Zero if
+	// no Retry-After header was sent.
+	RetryAfter time.Duration
+	Limits     RateLimitInfo
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s (retry after %s)", e.APIError.Error(), e.RetryAfter)
+	}
+	return e.APIError.Error()
+}
+
+func (e *RateLimitError) Unwrap() error { return e.APIError }
+
+// parseRetryAfter parses a Retry-After header in either delay-seconds or
+// HTTP-date form.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		d := t.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+func parseRateLimitInfo(h http.Header, now time.Time) RateLimitInfo {
+	info, _ := parseLimitHeaders(h, now, "X-RateLimit-", "RateLimit-")
+	return info
+}
+
+// parseLimitHeaders parses the Limit, Remaining and Reset headers under
+// the first of prefixes that has each, reporting whether any were found.
+func parseLimitHeaders(h http.Header, now time.Time, prefixes ...string) (RateLimitInfo, bool) {
+	var info RateLimitInfo
+	keys := func(name string) []string {
+		out := make([]string, len(prefixes))
+		for i, p := range prefixes {
+			out[i] = p + name
+		}
+		return out
+	}
+	found := false
+	if v, ok := firstHeaderInt(h, keys("Limit")...); ok {
+		info.Limit = v
+		found = true
+	}
+	if v, ok := firstHeaderInt(h, keys("Remaining")...); ok {
+		info.Remaining = v
+		found = true
+	}
+	if v, ok := firstHeaderInt(h, keys("Reset")...); ok {
+		// Servers send either a Unix timestamp or seconds until reset.
+		if v > 1_000_000_000 {
+			info.Reset = time.Unix(int64(v), 0)
+		} else {
+			info.Reset = now.Add(time.Duration(v) * time.Second)
+		}
+		found = true
+	}
+	return info, found
+}
+
+func firstHeaderInt(h http.Header, keys ...string) (int, bool) {
+	for _, key := range keys {
+		if v := h.Get(key); v != "" {
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			if err == nil {
+				return n, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func newRateLimitError(resp *http.Response) *RateLimitError {
+	now := time.Now()
+	e := &RateLimitError{
+		APIError: newAPIError(resp),
+		Limits:   parseRateLimitInfo(resp.Header, now),
+	}
+	if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now); ok {
+		e.RetryAfter = d
+	} else if !e.Limits.Reset.IsZero() && e.Limits.Remaining == 0 {
+		e.RetryAfter = e.Limits.Reset.Sub(now)
+	}
+	return e
+}
@@ -0,0 +1,90 @@
+package strict
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"0", 0, true},
+		{"120", 2 * time.Minute, true},
+		{" 5 ", 5 * time.Second, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{"1.5", 0, false},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second, true},
+		{now.Add(-time.Hour).Format(http.TimeFormat), 0, true},
+		{"Fri, 01 Mar 2024 12:00:30 GMT", 30 * time.Second, true},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestParseRateLimitInfo(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    RateLimitInfo
+	}{
+		{"none", nil, RateLimitInfo{}},
+		{"x-ratelimit", map[string]string{"X-RateLimit-Limit": "100", "X-RateLimit-Remaining": "7", "X-RateLimit-Reset": "30"},
+			RateLimitInfo{Limit: 100, Remaining: 7, Reset: now.Add(30 * time.Second)}},
+		{"unix reset", map[string]string{"X-RateLimit-Reset": "1700000600"}, RateLimitInfo{Reset: time.Unix(1_700_000_600, 0)}},
+		{"ietf", map[string]string{"RateLimit-Limit": "10", "RateLimit-Remaining": "0"}, RateLimitInfo{Limit: 10}},
+		{"x- wins", map[string]string{"X-RateLimit-Limit": "5", "RateLimit-Limit": "10"}, RateLimitInfo{Limit: 5}},
+		{"bad value skipped", map[string]string{"X-RateLimit-Limit": "many", "RateLimit-Limit": "10"}, RateLimitInfo{Limit: 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := make(http.Header)
+			for k, v := range tt.headers {
+				h.Set(k, v)
+			}
+			if got := parseRateLimitInfo(h, now); got != tt.want {
+				t.Errorf("parseRateLimitInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRateLimitError(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		min     time.Duration
+		max     time.Duration
+	}{
+		{"retry-after", map[string]string{"Retry-After": "7"}, 7 * time.Second, 7 * time.Second},
+		{"reset when exhausted", map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "20"}, 19 * time.Second, 20 * time.Second},
+		{"reset ignored with tokens left", map[string]string{"X-RateLimit-Remaining": "3", "X-RateLimit-Reset": "20"}, 0, 0},
+		{"retry-after wins", map[string]string{"Retry-After": "2", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "20"}, 2 * time.Second, 2 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: make(http.Header), Body: http.NoBody}
+			for k, v := range tt.headers {
+				resp.Header.Set(k, v)
+			}
+			e := newRateLimitError(resp)
+			if e.RetryAfter < tt.min || e.RetryAfter > tt.max {
+				t.Errorf("RetryAfter = %v, want between %v and %v", e.RetryAfter, tt.min, tt.max)
+			}
+			if e.StatusCode != http.StatusTooManyRequests {
+				t.Errorf("StatusCode = %d", e.StatusCode)
+			}
+		})
+	}
+}
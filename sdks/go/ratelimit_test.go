@@ -0,0 +1,84 @@
+package strict
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterRefill(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     RateLimitConfig
+		take    int
+		elapsed time.Duration
+		want    float64
+	}{
+		{"starts full", RateLimitConfig{RequestsPerSecond: 1, Burst: 3}, 0, 0, 3},
+		{"burst defaults to one", RateLimitConfig{RequestsPerSecond: 1}, 0, 0, 1},
+		{"takes tokens", RateLimitConfig{RequestsPerSecond: 1, Burst: 3}, 2, 0, 1},
+		{"refills at rate", RateLimitConfig{RequestsPerSecond: 2, Burst: 5}, 5, time.Second, 2},
+		{"caps at burst", RateLimitConfig{RequestsPerSecond: 10, Burst: 2}, 2, time.Minute, 2},
+		{"no rate never refills", RateLimitConfig{Burst: 2}, 2, time.Hour, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewRateLimiter(tt.cfg)
+			for i := 0; i < tt.take; i++ {
+				if !l.Allow() {
+					t.Fatalf("Allow() %d = false", i)
+				}
+			}
+			l.mu.Lock()
+			l.refill(l.last.Add(tt.elapsed))
+			got := l.tokens
+			l.mu.Unlock()
+			if got < tt.want-0.01 || got > tt.want+0.01 {
+				t.Errorf("tokens = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	ctx := context.Background()
+
+	reject := NewRateLimiter(RateLimitConfig{RequestsPerSecond: 0.001, Reject: true})
+	if err := reject.Wait(ctx); err != nil {
+		t.Fatalf("first Wait() = %v", err)
+	}
+	if err := reject.Wait(ctx); !errors.Is(err, ErrRateLimitExceeded) {
+		t.Fatalf("reject Wait() = %v, want ErrRateLimitExceeded", err)
+	}
+
+	// A wait that would outlast the deadline fails at once and returns
+	// its reservation.
+	slow := NewRateLimiter(RateLimitConfig{RequestsPerSecond: 0.001})
+	slow.Allow()
+	dctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := slow.Wait(dctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() = %v, want DeadlineExceeded", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("Wait() blocked past the deadline")
+	}
+	slow.mu.Lock()
+	tokens := slow.tokens
+	slow.mu.Unlock()
+	if tokens < -0.01 {
+		t.Errorf("tokens after cancelled wait = %v, want about 0", tokens)
+	}
+
+	fast := NewRateLimiter(RateLimitConfig{RequestsPerSecond: 100})
+	fast.Allow()
+	start = time.Now()
+	if err := fast.Wait(ctx); err != nil {
+		t.Fatalf("Wait() = %v", err)
+	}
+	if d := time.Since(start); d < 5*time.Millisecond {
+		t.Errorf("Wait() returned after %v, want about 10ms", d)
+	}
+}
@@ -0,0 +1,129 @@
+package strict
+
+import (
+	"errors"
+	"regexp"
+)
+
+// Redactor masks sensitive content in a body before the debug log writes
+// it. It must not modify body in place and must be safe for concurrent
+// use. Hooks see bodies unredacted; stricttest.VCR takes a Redactor of
+// its own for cassettes.
+type Redactor interface {
+	Redact(body []byte) []byte
+}
+
+// RedactorFunc adapts a function to a Redactor.
+type RedactorFunc func(body []byte) []byte
+
+// Redact calls f.
+func (f RedactorFunc) Redact(body []byte) []byte { return f(body) }
+
+// WithRedactor passes every body the debug log writes through r, on top
+// of the field masking WithDebug does. Use DefaultRedactor to make debug
+// output safe to enable in production. It doesn't apply to the bodies
+// Hooks receive.
+func WithRedactor(r Redactor) Option {
+	return func(c *Client) {
+		if r == nil {
+			c.setConfigErr(errors.New("strict: WithRedactor: nil redactor"))
+			return
+		}
+		c.redactor = r
+	}
+}
+
+// ChainRedactors applies rs in order.
+func ChainRedactors(rs ...Redactor) Redactor {
+	return RedactorFunc(func(body []byte) []byte {
+		for _, r := range rs {
+			body = r.Redact(body)
+		}
+		return body
+	})
+}
+
+// PatternRedactor replaces every match of any of patterns with
+// "[REDACTED]".
+func PatternRedactor(patterns ...*regexp.Regexp) Redactor {
+	return RedactorFunc(func(body []byte) []byte {
+		for _, re := range patterns {
+			body = re.ReplaceAllLiteral(body, []byte(redacted))
+		}
+		return body
+	})
+}
+
+// DefaultRedactor masks email addresses, credentials and payment card
+// numbers.
+func DefaultRedactor() Redactor {
+	return ChainRedactors(EmailRedactor(), TokenRedactor(), CreditCardRedactor())
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+	tokenPatterns = []*regexp.Regexp{
+		// JWTs.
+		regexp.MustCompile(`eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`),
+		// Bearer credentials.
+		regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9\-._~+/]+=*`),
+		// Prefixed API keys such as sk_live_... and ghp_....
+		regexp.MustCompile(`\b(?:sk|pk|rk)_(?:live|test)_[A-Za-z0-9]{8,}\b`),
+		regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{20,}\b`),
+		regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`),
+	}
+	// secretFieldPattern matches the value of JSON fields with secret
+	// names, capturing the parts to keep.
+	secretFieldPattern = regexp.MustCompile(`(?i)("(?:api_?key|access_token|refresh_token|id_token|token|secret|client_secret|password)"\s*:\s*")(?:[^"\\]|\\.)*(")`)
+
+	cardPattern = regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`)
+)
+
+// EmailRedactor masks email addresses.
+func EmailRedactor() Redactor { return PatternRedactor(emailPattern) }
+
+// TokenRedactor masks JWTs, bearer credentials, well-known API key
+// formats, and the values of JSON fields named like secrets, such as
+// "password" or "api_key".
+func TokenRedactor() Redactor {
+	patterns := PatternRedactor(tokenPatterns...)
+	return RedactorFunc(func(body []byte) []byte {
+		body = secretFieldPattern.ReplaceAll(body, []byte("${1}"+redacted+"${2}"))
+		return patterns.Redact(body)
+	})
+}
+
+// CreditCardRedactor masks runs of 13 to 19 digits, optionally grouped
+// with spaces or dashes, that start like a card number (2 to 6, the
+// major networks' prefixes) and pass the Luhn check, so numbers such as
+// millisecond timestamps are left alone.
+func CreditCardRedactor() Redactor {
+	return RedactorFunc(func(body []byte) []byte {
+		return cardPattern.ReplaceAllFunc(body, func(m []byte) []byte {
+			if m[0] < '2' || m[0] > '6' || !luhn(m) {
+				return m
+			}
+			return []byte(redacted)
+		})
+	})
+}
+
+// luhn reports whether the digits in s pass the Luhn checksum.
+func luhn(s []byte) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] < '0' || s[i] > '9' {
+			continue
+		}
+		d := int(s[i] - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
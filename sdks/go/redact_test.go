@@ -0,0 +1,26 @@
+package strict
+
+import "testing"
+
+func TestCreditCardRedactor(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"visa", `{"card":"4111 1111 1111 1111"}`, `{"card":"[REDACTED]"}`},
+		{"mastercard dashes", `{"card":"5555-5555-5555-4444"}`, `{"card":"[REDACTED]"}`},
+		{"amex", `{"card":"378282246310005"}`, `{"card":"[REDACTED]"}`},
+		{"fails luhn", `{"card":"4111111111111112"}`, `{"card":"4111111111111112"}`},
+		// Passes the Luhn check, but no card number starts with 1.
+		{"epoch milliseconds", `{"created_ms":1760000000008}`, `{"created_ms":1760000000008}`},
+		{"short number", `{"id":411111111111}`, `{"id":411111111111}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(CreditCardRedactor().Redact([]byte(tt.body))); got != tt.want {
+				t.Errorf("Redact() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
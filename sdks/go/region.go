@@ -0,0 +1,121 @@
+package strict
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Region is a deployment of the hosted API.
+type Region struct {
+	// Name identifies the region, such as "eu-west-1".
+	Name string
+	// BaseURL is the region's API base URL.
+	BaseURL string
+	// Processors lists the processor types the region hosts.
+	Processors []ProcessorType
+}
+
+// Hosts reports whether the region hosts processor type p. Requests that
+// leave the processor type to the server are hosted everywhere.
+func (r Region) Hosts(p ProcessorType) bool {
+	if p == "" {
+		return true
+	}
+	for _, hosted := range r.Processors {
+		if hosted == p {
+			return true
+		}
+	}
+	return false
+}
+
+// RegionError is returned, before anything is sent, for a request whose
+// processor type the client's region doesn't host.
+type RegionError struct {
+	Region    string
+	Processor ProcessorType
+}
+
+func (e *RegionError) Error() string {
+	return fmt.Sprintf("strict: region %s does not host %s processors", e.Region, e.Processor)
+}
+
+var regions = struct {
+	sync.RWMutex
+	m map[string]Region
+}{m: map[string]Region{
+	"us-east-1":      {"us-east-1", "https://us-east.api.strict.io", []ProcessorType{Cloud, Local, HybridProc}},
+	"us-west-2":      {"us-west-2", "https://us-west.api.strict.io", []ProcessorType{Cloud, Local, HybridProc}},
+	"eu-central-1":   {"eu-central-1", "https://eu-central.api.strict.io", []ProcessorType{Cloud, Local, HybridProc}},
+	"eu-west-1":      {"eu-west-1", "https://eu-west.api.strict.io", []ProcessorType{Cloud, HybridProc}},
+	"ap-southeast-1": {"ap-southeast-1", "https://ap-southeast.api.strict.io", []ProcessorType{Cloud}},
+}}
+
+// RegisterRegion adds r to the region registry, replacing any region of
+// the same name. It is for private deployments and regions newer than
+// the SDK.
+func RegisterRegion(r Region) error {
+	if r.Name == "" || r.BaseURL == "" {
+		return errors.New("strict: RegisterRegion: name and base URL are required")
+	}
+	r.Processors = append([]ProcessorType(nil), r.Processors...)
+	regions.Lock()
+	regions.m[r.Name] = r
+	regions.Unlock()
+	return nil
+}
+
+// LookupRegion returns the registered region called name.
+func LookupRegion(name string) (Region, bool) {
+	regions.RLock()
+	r, ok := regions.m[name]
+	regions.RUnlock()
+	return r, ok
+}
+
+// Regions returns the registered regions, sorted by name.
+func Regions() []Region {
+	regions.RLock()
+	out := make([]Region, 0, len(regions.m))
+	for _, r := range regions.m {
+		out = append(out, r)
+	}
+	regions.RUnlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// WithRegion sends requests to the named region's base URL, replacing the
+// base URL given to NewClient and any endpoints set before it. Requests
+// for a processor type the region doesn't host fail with a *RegionError
+// without being sent. Unknown regions are a configuration error.
+func WithRegion(name string) Option {
+	return func(c *Client) {
+		r, ok := LookupRegion(name)
+		if !ok {
+			c.setConfigErr(fmt.Errorf("strict: WithRegion: unknown region %q", name))
+			return
+		}
+		c.region = &r
+		withBaseURLs([]string{r.BaseURL})(c)
+	}
+}
+
+// Region returns the client's region, if it was built with WithRegion.
+func (c *Client) Region() (Region, bool) {
+	if c.region == nil {
+		return Region{}, false
+	}
+	return *c.region, true
+}
+
+// checkRegion fails requests for processors the client's region doesn't
+// host.
+func (c *Client) checkRegion(p ProcessorType) error {
+	if c.region == nil || c.region.Hosts(p) {
+		return nil
+	}
+	return &RegionError{Region: c.region.Name, Processor: p}
+}
@@ -0,0 +1,18 @@
+package strict
+
+import "context"
+
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a context carrying a correlation ID. Calls made
+// with the context send it as X-Correlation-ID, so one business operation
+// can be followed across several API calls and services.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID stored in ctx, if any.
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
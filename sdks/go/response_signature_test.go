@@ -0,0 +1,151 @@
+package strict
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// signingServer serves body with a signature made by sign, as tamper
+// says: "" signs it, "body" changes the body after signing, "missing"
+// omits the signature and "garbled" sends one that isn't base64.
+func signingServer(t *testing.T, contentType, body, tamper string, sign func(msg []byte) []byte) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sum := sha256.Sum256([]byte(body))
+		msg := []byte(strconv.Itoa(http.StatusOK) + "\n" + r.Header.Get("X-Request-ID") + "\n" + hex.EncodeToString(sum[:]))
+		sig := base64.StdEncoding.EncodeToString(sign(msg))
+		sent := body
+		switch tamper {
+		case "body":
+			sent = body[:len(body)-1] + " }"
+		case "missing":
+			sig = ""
+		case "garbled":
+			sig = "not base64!"
+		}
+		if sig != "" {
+			w.Header().Set("X-Response-Signature", sig)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Write([]byte(sent))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestResponseVerification(t *testing.T) {
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	keys := []struct {
+		name string
+		pub  crypto.PublicKey
+		sign func(msg []byte) []byte
+	}{
+		{"ed25519", edKey.Public(), func(msg []byte) []byte { return ed25519.Sign(edKey, msg) }},
+		{"ecdsa", &ecKey.PublicKey, func(msg []byte) []byte {
+			d := sha256.Sum256(msg)
+			sig, _ := ecdsa.SignASN1(rand.Reader, ecKey, d[:])
+			return sig
+		}},
+		{"rsa", &rsaKey.PublicKey, func(msg []byte) []byte {
+			d := sha256.Sum256(msg)
+			sig, _ := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, d[:])
+			return sig
+		}},
+	}
+	tampers := []struct {
+		tamper      string
+		wantErr     bool
+		wantMissing bool
+	}{
+		{"", false, false},
+		{"body", true, false},
+		{"missing", true, true},
+		{"garbled", true, false},
+	}
+	for _, k := range keys {
+		for _, tt := range tampers {
+			t.Run(k.name+"/"+tt.tamper, func(t *testing.T) {
+				srv := signingServer(t, "application/json", `{"version":"1"}`, tt.tamper, k.sign)
+				c := NewClient(srv.URL, "k", WithResponseVerification(k.pub))
+				info, err := c.ServerInfo(context.Background())
+				if !tt.wantErr {
+					if err != nil || info.Version != "1" {
+						t.Fatalf("ServerInfo() = %v, %v", info, err)
+					}
+					return
+				}
+				var se *ResponseSignatureError
+				if !errors.As(err, &se) || !errors.Is(err, ErrInvalidSignature) {
+					t.Fatalf("ServerInfo() error = %v, want *ResponseSignatureError", err)
+				}
+				if se.Missing != tt.wantMissing {
+					t.Errorf("Missing = %v, want %v", se.Missing, tt.wantMissing)
+				}
+			})
+		}
+	}
+}
+
+func TestResponseVerificationStreamed(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	sign := func(msg []byte) []byte { return ed25519.Sign(key, msg) }
+	req := ProcessingRequest{InputData: "x", ProcessorType: Local, InputTokens: 1}
+	const events = "event: progress\ndata: {\"percent\":50}\n\n" +
+		"event: result\ndata: {\"result\":1,\"processor_used\":\"local\"}\n\n"
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		tamper      string
+		wantErr     bool
+	}{
+		{"events", "text/event-stream", events, "", false},
+		{"events tampered", "text/event-stream", events, "body", true},
+		{"events unsigned", "text/event-stream", events, "missing", true},
+		{"json fallback", "application/json", `{"result":1,"processor_used":"local"}`, "", false},
+		{"json fallback tampered", "application/json", `{"result":1,"processor_used":"local"}`, "body", true},
+	}
+	for _, tt := range tests {
+		t.Run("progress/"+tt.name, func(t *testing.T) {
+			srv := signingServer(t, tt.contentType, tt.body, tt.tamper, sign)
+			c := NewClient(srv.URL, "k", WithResponseVerification(key.Public()))
+			progress := make(chan Progress, 10)
+			_, err := c.ProcessRequestWithProgress(context.Background(), req, progress)
+			if gotErr := errors.Is(err, ErrInvalidSignature); gotErr != tt.wantErr || (!tt.wantErr && err != nil) {
+				t.Fatalf("ProcessRequestWithProgress() = %v, want signature error %v", err, tt.wantErr)
+			}
+		})
+	}
+	for _, tamper := range []string{"", "body"} {
+		t.Run("stream result/"+tamper, func(t *testing.T) {
+			srv := signingServer(t, "application/json", `{"result":[1,2,3],"processor_used":"local"}`, tamper, sign)
+			c := NewClient(srv.URL, "k", WithResponseVerification(key.Public()))
+			rs, err := c.ProcessRequestStreamResult(context.Background(), req)
+			if tamper != "" {
+				if !errors.Is(err, ErrInvalidSignature) {
+					t.Fatalf("ProcessRequestStreamResult() = %v, want ErrInvalidSignature", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			rs.Close()
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package strict
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// UnmarshalJSON decodes o, keeping a non-null result as a
+// json.RawMessage in Result rather than decoding it. Large results are
+// then decoded only once, straight into the caller's type, and
+// re-encoding them costs nothing.
+func (o *OutputSchema) UnmarshalJSON(data []byte) error {
+	type plain OutputSchema
+	aux := struct {
+		*plain
+		Result json.RawMessage `json:"result"`
+	}{plain: (*plain)(o)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	o.Result = nil
+	if len(aux.Result) > 0 && string(aux.Result) != "null" {
+		o.Result = aux.Result
+	}
+	return nil
+}
+
+// RawResult returns Result as JSON. It is free for results decoded from
+// JSON responses, which keep their raw form; results from other codecs
+// are encoded on demand.
+func (o *OutputSchema) RawResult() (json.RawMessage, error) {
+	switch r := o.Result.(type) {
+	case json.RawMessage:
+		return r, nil
+	case nil:
+		return json.RawMessage("null"), nil
+	}
+	data, err := json.Marshal(o.Result)
+	if err != nil {
+		return nil, fmt.Errorf("strict: encode result: %w", err)
+	}
+	return data, nil
+}
+
+// ResultValue returns Result decoded into generic Go values, the way
+// Result itself was populated before results were kept raw: objects
+// become map[string]interface{}, arrays []interface{}, and numbers
+// float64.
+func (o *OutputSchema) ResultValue() (interface{}, error) {
+	raw, ok := o.Result.(json.RawMessage)
+	if !ok {
+		return o.Result, nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, fmt.Errorf("strict: decode result: %w", err)
+	}
+	return v, nil
+}
+
+// DecodeResult decodes Result into target, which must be a pointer.
+// Decoding is strict: an object with a field target doesn't have is an
+// error, so a result that drifts from the expected shape fails here
+// rather than further on.
+func (o *OutputSchema) DecodeResult(target interface{}) error {
+	raw, err := o.RawResult()
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(target); err != nil {
+		return fmt.Errorf("strict: decode result: %w", err)
+	}
+	return nil
+}
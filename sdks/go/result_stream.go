@@ -0,0 +1,214 @@
+package strict
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ResultStream reads a processing response incrementally, so a large
+// Result can be consumed piece by piece instead of held in memory. Read
+// the result with Decoder or ForEach, then call Finish for the remaining
+// fields, or Close to abandon the response.
+type ResultStream struct {
+	// RequestID is the server's ID for the request.
+	RequestID string
+
+	body io.ReadCloser
+	dec  *json.Decoder
+	// fields holds the response's other fields, raw, as they are passed.
+	fields map[string]json.RawMessage
+	// noResult is set when the response has no result field, in which
+	// case the object has already been read to its end.
+	noResult bool
+	used     bool
+}
+
+// ProcessRequestStreamResult sends req like ProcessRequest, but returns
+// as soon as the server starts sending the result. The response is not
+// cached, deduplicated or hedged, and is always requested as JSON. The
+// caller must call Finish or Close.
+func (c *Client) ProcessRequestStreamResult(ctx context.Context, req ProcessingRequest, opts ...CallOption) (*ResultStream, error) {
+	if c.envelope != nil {
+		return nil, ErrEncryptionUnsupported
+	}
+	co := newCallOptions(opts)
+	if err := c.prepareRequest(ctx, &req, co); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("strict: encode request: %w", err)
+	}
+	cancel := context.CancelFunc(func() {})
+	if timeout := callTimeout(req, co); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+
+	cl := &call{
+		op:       "ProcessRequestStreamResult",
+		method:   http.MethodPost,
+		path:     "/process/request",
+		body:     data,
+		opts:     co,
+		request:  &req,
+		keepBody: true,
+	}
+	if err := c.do(ctx, cl); err != nil {
+		cancel()
+		return nil, err
+	}
+	s := &ResultStream{
+		RequestID: cl.serverRequestID,
+		body:      &cancelOnClose{ReadCloser: cl.respBody, cancel: cancel},
+		fields:    make(map[string]json.RawMessage),
+	}
+	s.dec = json.NewDecoder(s.body)
+	if err := s.seekResult(); err != nil {
+		s.Close()
+		return nil, fmt.Errorf("strict: decode response: %w", err)
+	}
+	return s, nil
+}
+
+// seekResult reads up to the start of the result value, keeping any
+// fields that come before it.
+func (s *ResultStream) seekResult() error {
+	if err := s.expect(json.Delim('{')); err != nil {
+		return err
+	}
+	for s.dec.More() {
+		key, err := s.key()
+		if err != nil {
+			return err
+		}
+		if key == "result" {
+			return nil
+		}
+		var raw json.RawMessage
+		if err := s.dec.Decode(&raw); err != nil {
+			return err
+		}
+		s.fields[key] = raw
+	}
+	s.noResult = true
+	return s.expect(json.Delim('}'))
+}
+
+func (s *ResultStream) key() (string, error) {
+	tok, err := s.dec.Token()
+	if err != nil {
+		return "", err
+	}
+	key, ok := tok.(string)
+	if !ok {
+		return "", fmt.Errorf("unexpected %v, want object key", tok)
+	}
+	return key, nil
+}
+
+func (s *ResultStream) expect(want json.Delim) error {
+	tok, err := s.dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != want {
+		return fmt.Errorf("unexpected %v, want %v", tok, want)
+	}
+	return nil
+}
+
+// Decoder returns a decoder positioned at the result value. Read exactly
+// one value from it, using Token and More to walk large values, before
+// calling Finish.
+func (s *ResultStream) Decoder() *json.Decoder {
+	s.used = true
+	if s.noResult {
+		return json.NewDecoder(strings.NewReader("null"))
+	}
+	return s.dec
+}
+
+// ForEach calls fn for each element of an array result. fn must read
+// exactly one value from dec, typically with dec.Decode.
+func (s *ResultStream) ForEach(fn func(dec *json.Decoder) error) error {
+	dec := s.Decoder()
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("strict: decode response: %w", err)
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("strict: result is %v, not an array", tok)
+	}
+	for dec.More() {
+		if err := fn(dec); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("strict: decode response: %w", err)
+	}
+	return nil
+}
+
+// Finish reads the fields that follow the result and closes the stream.
+// The returned OutputSchema has a nil Result. A result that was never
+// read is skipped.
+func (s *ResultStream) Finish() (*OutputSchema, error) {
+	defer s.Close()
+	if !s.noResult {
+		if !s.used {
+			var skip json.RawMessage
+			if err := s.dec.Decode(&skip); err != nil {
+				return nil, fmt.Errorf("strict: decode response: %w", err)
+			}
+		}
+		for s.dec.More() {
+			key, err := s.key()
+			if err != nil {
+				return nil, fmt.Errorf("strict: decode response: %w", err)
+			}
+			var raw json.RawMessage
+			if err := s.dec.Decode(&raw); err != nil {
+				return nil, fmt.Errorf("strict: decode response: %w", err)
+			}
+			s.fields[key] = raw
+		}
+		if err := s.expect(json.Delim('}')); err != nil {
+			return nil, fmt.Errorf("strict: decode response: %w", err)
+		}
+	}
+
+	delete(s.fields, "result")
+	data, err := json.Marshal(s.fields)
+	if err != nil {
+		return nil, err
+	}
+	var out OutputSchema
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("strict: decode response: %w", err)
+	}
+	out.RequestID = s.RequestID
+	return &out, nil
+}
+
+// Close releases the response without reading the rest of it.
+func (s *ResultStream) Close() error {
+	return s.body.Close()
+}
+
+// cancelOnClose releases a context when the body it was read under is
+// closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
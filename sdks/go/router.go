@@ -0,0 +1,301 @@
+package strict
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Router picks the processor type for requests that don't name one,
+// replacing the client's default processor. See WithRouter. Route must be
+// safe for concurrent use.
+type Router interface {
+	// Route returns the processor type for in.Request, or "" to use the
+	// client's default processor.
+	Route(in RouteInput) ProcessorType
+}
+
+// RouterFunc adapts a function to the Router interface.
+type RouterFunc func(in RouteInput) ProcessorType
+
+func (f RouterFunc) Route(in RouteInput) ProcessorType { return f(in) }
+
+// RouteInput is what a Router decides on.
+type RouteInput struct {
+	Request ProcessingRequest
+	// Tokens is the request's InputTokens, or the default Tokenizer's
+	// count if the caller left it zero.
+	Tokens int
+	// Budget is the time left for the call: the least of the call timeout,
+	// the request's TimeoutSeconds and the context's deadline. Zero means
+	// no limit.
+	Budget time.Duration
+	// Candidates are the processors the server lists, less any the
+	// client's region doesn't host.
+	Candidates []ProcessorStatus
+}
+
+// ProcessorStatus is a processor's capabilities, from ListProcessors, and
+// the client's own record of calls to it.
+type ProcessorStatus struct {
+	ProcessorInfo
+	Requests int64
+	Failures int64
+	// ConsecutiveFailures counts the calls that have failed since the
+	// last success.
+	ConsecutiveFailures int64
+	Latency             LatencySummary
+}
+
+// routerFailureThreshold is the number of consecutive failures that
+// makes a processor unhealthy to the built-in routers.
+const routerFailureThreshold = 3
+
+// Healthy reports whether the processor is available and its recent
+// calls haven't all been failing.
+func (s ProcessorStatus) Healthy() bool {
+	return s.Available && s.ConsecutiveFailures < routerFailureThreshold
+}
+
+// ExpectedLatency is the median latency the client has measured, or the
+// server's estimate before the client has called the processor.
+func (s ProcessorStatus) ExpectedLatency() time.Duration {
+	if s.Requests > 0 {
+		return s.Latency.P50
+	}
+	return time.Duration(s.ExpectedLatencyMs * float64(time.Millisecond))
+}
+
+// eligible returns the healthy candidates that accept tokens.
+func (in RouteInput) eligible() []ProcessorStatus {
+	var out []ProcessorStatus
+	for _, c := range in.Candidates {
+		if c.Healthy() && c.Accepts(in.Tokens) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// FastestRouter picks the healthy processor with the lowest expected
+// latency that accepts the request's tokens. Processors with no latency
+// figure yet are tried first so every processor gets measured.
+func FastestRouter() Router {
+	return RouterFunc(func(in RouteInput) ProcessorType {
+		return fastest(in.eligible())
+	})
+}
+
+func fastest(cands []ProcessorStatus) ProcessorType {
+	if len(cands) == 0 {
+		return ""
+	}
+	best := cands[0]
+	for _, c := range cands[1:] {
+		if c.ExpectedLatency() < best.ExpectedLatency() {
+			best = c
+		}
+	}
+	return best.Type
+}
+
+// costRank orders cost tiers, cheapest first. Unknown tiers rank last.
+var costRank = map[string]int{"free": 0, "standard": 1, "premium": 2}
+
+func rankOf(tier string) int {
+	if r, ok := costRank[tier]; ok {
+		return r
+	}
+	return len(costRank)
+}
+
+// CheapestRouter picks the healthy processor in the lowest cost tier that
+// accepts the request's tokens and is expected to answer within its
+// budget, breaking ties on latency. If no processor fits the budget it
+// picks the fastest.
+func CheapestRouter() Router {
+	return RouterFunc(func(in RouteInput) ProcessorType {
+		cands := in.eligible()
+		var best *ProcessorStatus
+		for i, c := range cands {
+			if in.Budget > 0 && c.ExpectedLatency() > in.Budget {
+				continue
+			}
+			if best == nil || rankOf(c.CostTier) < rankOf(best.CostTier) ||
+				rankOf(c.CostTier) == rankOf(best.CostTier) && c.ExpectedLatency() < best.ExpectedLatency() {
+				best = &cands[i]
+			}
+		}
+		if best == nil {
+			return fastest(cands)
+		}
+		return best.Type
+	})
+}
+
+// stickyMaxKeys bounds StickyRouter's memory; it forgets every key when
+// it has seen this many.
+const stickyMaxKeys = 10000
+
+// StickyRouter sends requests with the same key to the processor next
+// first picked for that key, for as long as that processor stays healthy
+// and accepts the requests, so related requests see consistent results.
+// A nil key makes every request share one key.
+func StickyRouter(next Router, key func(RouteInput) string) Router {
+	var (
+		mu    sync.Mutex
+		stuck = make(map[string]ProcessorType)
+	)
+	return RouterFunc(func(in RouteInput) ProcessorType {
+		k := ""
+		if key != nil {
+			k = key(in)
+		}
+		mu.Lock()
+		p, ok := stuck[k]
+		mu.Unlock()
+		if ok {
+			for _, c := range in.eligible() {
+				if c.Type == p {
+					return p
+				}
+			}
+		}
+		p = next.Route(in)
+		mu.Lock()
+		defer mu.Unlock()
+		if p == "" {
+			delete(stuck, k)
+			return p
+		}
+		if len(stuck) >= stickyMaxKeys {
+			stuck = make(map[string]ProcessorType)
+		}
+		stuck[k] = p
+		return p
+	})
+}
+
+// WithRouter routes requests that don't name a processor type, by
+// ProcessingRequest.ProcessorType or WithProcessorOverride, with r. The
+// client fetches its candidates with ListProcessors, at most once a
+// minute and in the background, routing with the previous list until
+// the new one arrives; if the server doesn't list its processors, the
+// candidates are the known processor types, all assumed available.
+func WithRouter(r Router) Option {
+	return func(c *Client) {
+		c.router = r
+		c.catalog = new(processorCatalog)
+	}
+}
+
+const (
+	routerCatalogTTL     = time.Minute
+	routerCatalogRetry   = 10 * time.Second
+	routerCatalogTimeout = 10 * time.Second
+)
+
+// processorCatalog caches the server's processor list for routing. Once
+// it has a list, an expired one is still served while a single
+// background refresh, bounded by routerCatalogTimeout rather than any
+// caller's context, fetches the next.
+type processorCatalog struct {
+	mu      sync.Mutex
+	list    []ProcessorInfo
+	expires time.Time
+	// refreshing is closed when the running refresh ends, and nil when
+	// none is running.
+	refreshing chan struct{}
+}
+
+func (pc *processorCatalog) get(ctx context.Context, c *Client) []ProcessorInfo {
+	pc.mu.Lock()
+	if time.Now().Before(pc.expires) {
+		list := pc.list
+		pc.mu.Unlock()
+		return list
+	}
+	done := pc.refreshing
+	if done == nil {
+		done = make(chan struct{})
+		pc.refreshing = done
+		go pc.refresh(c, done)
+	}
+	list := pc.list
+	pc.mu.Unlock()
+	if list == nil {
+		// Nothing to serve yet: wait for the first list as long as the
+		// caller does.
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
+		pc.mu.Lock()
+		list = pc.list
+		pc.mu.Unlock()
+	}
+	if list == nil {
+		return knownProcessors()
+	}
+	return list
+}
+
+// refresh fetches the processor list and closes done.
+func (pc *processorCatalog) refresh(c *Client, done chan struct{}) {
+	ctx, cancel := context.WithTimeout(context.Background(), routerCatalogTimeout)
+	defer cancel()
+	list, err := c.ListProcessors(ctx)
+	pc.mu.Lock()
+	if err == nil {
+		pc.list, pc.expires = list, time.Now().Add(routerCatalogTTL)
+	} else {
+		if pc.list == nil {
+			pc.list = knownProcessors()
+		}
+		pc.expires = time.Now().Add(routerCatalogRetry)
+	}
+	pc.refreshing = nil
+	pc.mu.Unlock()
+	close(done)
+}
+
+func knownProcessors() []ProcessorInfo {
+	var list []ProcessorInfo
+	for _, p := range ProcessorTypes() {
+		list = append(list, ProcessorInfo{Type: p, Available: true})
+	}
+	return list
+}
+
+// route picks req's processor type with the client's router.
+func (c *Client) route(ctx context.Context, req *ProcessingRequest, co callOptions) {
+	in := RouteInput{Request: *req, Tokens: req.InputTokens}
+	if in.Tokens == 0 && c.tokenizer != nil {
+		in.Tokens = c.tokenizer.CountTokens(req.InputData)
+	}
+	budget := func(d time.Duration) {
+		if d > 0 && (in.Budget == 0 || d < in.Budget) {
+			in.Budget = d
+		}
+	}
+	budget(co.timeout)
+	budget(time.Duration(req.TimeoutSeconds * float64(time.Second)))
+	if dl, ok := ctx.Deadline(); ok {
+		budget(max(time.Until(dl), time.Nanosecond))
+	}
+	for _, p := range c.catalog.get(ctx, c) {
+		if c.checkRegion(p.Type) != nil {
+			continue
+		}
+		st := ProcessorStatus{ProcessorInfo: p}
+		if v, ok := c.stats.byProcessor.Load(p.Type); ok {
+			ps := v.(*processorStats)
+			st.Requests = ps.requests.Load()
+			st.Failures = ps.failures.Load()
+			st.ConsecutiveFailures = ps.streak.Load()
+			st.Latency = ps.latency.summary()
+		}
+		in.Candidates = append(in.Candidates, st)
+	}
+	req.ProcessorType = c.router.Route(in)
+}
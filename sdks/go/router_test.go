@@ -0,0 +1,65 @@
+package strict
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestProcessorCatalogRefresh(t *testing.T) {
+	var lists atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lists.Add(1)
+		<-release
+		w.Write([]byte(`{"processors":[{"type":"cloud","available":true}]}`))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, "k")
+	stale := []ProcessorInfo{{Type: Local, Available: true}}
+	pc := &processorCatalog{list: stale, expires: time.Now().Add(-time.Second)}
+
+	// While the refresh is blocked, every caller gets the stale list at
+	// once and no caller starts another.
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := pc.get(context.Background(), c); len(got) != 1 || got[0].Type != Local {
+				t.Errorf("get() = %v, want the stale list", got)
+			}
+		}()
+	}
+	wg.Wait()
+	for deadline := time.Now().Add(time.Second); lists.Load() == 0 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if n := lists.Load(); n != 1 {
+		t.Fatalf("server saw %d list requests, want 1", n)
+	}
+
+	pc.mu.Lock()
+	done := pc.refreshing
+	pc.mu.Unlock()
+	close(release)
+	<-done
+	if got := pc.get(context.Background(), c); len(got) != 1 || got[0].Type != Cloud {
+		t.Errorf("get() = %v after the refresh, want the new list", got)
+	}
+}
+
+func TestProcessorCatalogFirstFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"processors":[{"type":"cloud","available":true}]}`))
+	}))
+	defer srv.Close()
+	pc := new(processorCatalog)
+	if got := pc.get(context.Background(), NewClient(srv.URL, "k")); len(got) != 1 || got[0].Type != Cloud {
+		t.Errorf("get() = %v, want the server's list", got)
+	}
+}
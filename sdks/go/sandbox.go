@@ -0,0 +1,78 @@
+package strict
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// SandboxURL is the base URL of the sandbox environment, where
+	// requests are validated and processed against test processors and
+	// never billed.
+	SandboxURL = "https://sandbox.api.strict.io"
+	// SandboxHeader is set to "true" on every request a sandbox client
+	// sends, so a misrouted request is still recognisable server-side.
+	SandboxHeader = "X-Sandbox"
+
+	// productionDomain is the domain of the hosted API. Its hosts are
+	// production unless their first label starts with "sandbox".
+	productionDomain = "strict.io"
+)
+
+// ErrProductionEndpoint is returned, wrapped in a *SandboxError, when a
+// sandbox client is about to send a request to a production endpoint.
+var ErrProductionEndpoint = errors.New("strict: sandbox client refused a production endpoint")
+
+// SandboxError reports the production URL a sandbox client refused.
+type SandboxError struct {
+	URL string
+}
+
+func (e *SandboxError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrProductionEndpoint, e.URL)
+}
+
+func (e *SandboxError) Unwrap() error { return ErrProductionEndpoint }
+
+// WithSandbox points the client at SandboxURL, replacing the base URL
+// given to NewClient and any endpoints set before it, and sets
+// SandboxHeader on every request. Options after it may set another base
+// URL, such as a local mock server, but a sandbox client refuses to send
+// anything to a production host of the hosted API, failing the call with
+// a *SandboxError before it leaves the process.
+func WithSandbox() Option {
+	return func(c *Client) {
+		c.sandbox = true
+		c.BaseURL = SandboxURL
+		c.endpoints = nil
+	}
+}
+
+// Sandbox reports whether the client was built with WithSandbox.
+func (c *Client) Sandbox() bool { return c.sandbox }
+
+// CheckEndpoint returns a *SandboxError if the client is in sandbox mode
+// and rawURL is a production endpoint. It is for packages that open
+// their own connections to the server.
+func (c *Client) CheckEndpoint(rawURL string) error {
+	if !c.sandbox || !isProductionURL(rawURL) {
+		return nil
+	}
+	return &SandboxError{URL: rawURL}
+}
+
+// isProductionURL reports whether rawURL is on a production host.
+// Unparseable URLs count as production, so they are refused too.
+func isProductionURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return true
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host != productionDomain && !strings.HasSuffix(host, "."+productionDomain) {
+		return false
+	}
+	return !strings.HasPrefix(host, "sandbox")
+}
@@ -0,0 +1,182 @@
+package strict
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// expectedSignature computes the X-Signature value a server would expect.
+func expectedSignature(secret []byte, method, uri, ts, nonce, bodyHash string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strings.Join([]string{method, uri, ts, nonce, bodyHash}, "\n")))
+	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestRequestSignerSign(t *testing.T) {
+	secret := []byte("s3cret")
+	now := time.Unix(1_700_000_000, 0)
+	emptyHash := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	tests := []struct {
+		name     string
+		keyID    string
+		method   string
+		url      string
+		body     string
+		streamed bool
+		wantHash string
+	}{
+		{"get", "", http.MethodGet, "https://api.example/usage?period=day", "", false, emptyHash},
+		{"post with key id", "kid-1", http.MethodPost, "https://api.example/v1/process/request", `{"input_data":"x"}`, false, ""},
+		{"streamed", "", http.MethodPost, "https://api.example/process/stream", "ignored", true, unsignedPayload},
+		{"escaped path", "", http.MethodGet, "https://api.example/jobs/a%2Fb", "", false, emptyHash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, tt.url, nil)
+			s := &requestSigner{keyID: tt.keyID, secret: secret}
+			if err := s.sign(req, []byte(tt.body), tt.streamed, now); err != nil {
+				t.Fatal(err)
+			}
+			wantHash := tt.wantHash
+			if wantHash == "" {
+				sum := sha256.Sum256([]byte(tt.body))
+				wantHash = hex.EncodeToString(sum[:])
+			}
+			h := req.Header
+			if got := h.Get("X-Content-SHA256"); got != wantHash {
+				t.Errorf("X-Content-SHA256 = %q, want %q", got, wantHash)
+			}
+			if got := h.Get("X-Signature-Timestamp"); got != "1700000000" {
+				t.Errorf("X-Signature-Timestamp = %q", got)
+			}
+			if got := h.Get("X-Signature-Key-ID"); got != tt.keyID {
+				t.Errorf("X-Signature-Key-ID = %q, want %q", got, tt.keyID)
+			}
+			want := expectedSignature(secret, tt.method, req.URL.RequestURI(), h.Get("X-Signature-Timestamp"), h.Get("X-Signature-Nonce"), wantHash)
+			if got := h.Get("X-Signature"); got != want {
+				t.Errorf("X-Signature = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestRequestSigningClient(t *testing.T) {
+	secret := []byte("s3cret")
+	var bad []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sum := sha256.Sum256(body)
+		h := r.Header
+		if h.Get("X-Content-SHA256") != hex.EncodeToString(sum[:]) ||
+			h.Get("X-Signature") != expectedSignature(secret, r.Method, r.URL.RequestURI(), h.Get("X-Signature-Timestamp"), h.Get("X-Signature-Nonce"), hex.EncodeToString(sum[:])) {
+			bad = append(bad, r.Method+" "+r.URL.Path)
+		}
+		w.Write([]byte(`{"result":1}`))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL+"/api", "k", WithRequestSigning("kid", secret))
+	ctx := context.Background()
+	if _, err := c.ProcessRequest(ctx, ProcessingRequest{InputData: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetUsage(ctx, UsageDay); err != nil {
+		t.Fatal(err)
+	}
+	if len(bad) > 0 {
+		t.Errorf("requests with bad signatures: %v", bad)
+	}
+	if NewClient(srv.URL, "k", WithRequestSigning("kid", nil)).configErr == nil {
+		t.Error("empty secret accepted")
+	}
+}
+
+func TestRequestSignerNonces(t *testing.T) {
+	s := &requestSigner{secret: []byte("x")}
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		req, _ := http.NewRequest(http.MethodGet, "https://api.example/health", nil)
+		if err := s.sign(req, nil, false, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+		n := req.Header.Get("X-Signature-Nonce")
+		if len(n) != 32 {
+			t.Fatalf("nonce %q is not 32 hex digits", n)
+		}
+		if seen[n] {
+			t.Fatalf("nonce %q reused", n)
+		}
+		seen[n] = true
+	}
+}
+
+func TestObserveClock(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		signing   bool
+		tolerance time.Duration
+		date      string
+		want      time.Duration
+	}{
+		{"in step", true, defaultClockSkew, now.Format(http.TimeFormat), 0},
+		{"within tolerance", true, defaultClockSkew, now.Add(time.Second).Format(http.TimeFormat), 0},
+		{"server ahead", true, defaultClockSkew, now.Add(time.Hour).Format(http.TimeFormat), time.Hour + 500*time.Millisecond},
+		{"server behind", true, defaultClockSkew, now.Add(-10 * time.Minute).Format(http.TimeFormat), -10*time.Minute + 500*time.Millisecond},
+		{"wider tolerance", true, time.Hour, now.Add(30 * time.Minute).Format(http.TimeFormat), 0},
+		{"disabled", true, -1, now.Add(time.Hour).Format(http.TimeFormat), 0},
+		{"not signing", false, defaultClockSkew, now.Add(time.Hour).Format(http.TimeFormat), 0},
+		{"bad date", true, defaultClockSkew, "yesterday", 0},
+		{"no date", true, defaultClockSkew, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{clockSkew: tt.tolerance}
+			if tt.signing {
+				c.signer = &requestSigner{secret: []byte("x")}
+			}
+			h := make(http.Header)
+			if tt.date != "" {
+				h.Set("Date", tt.date)
+			}
+			c.observeClock(h, now)
+			if got := time.Duration(c.clockOffset.Load()); got != tt.want {
+				t.Errorf("offset = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClockSkewCorrection(t *testing.T) {
+	var stamps []int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ts, _ := strconv.ParseInt(r.Header.Get("X-Signature-Timestamp"), 10, 64)
+		stamps = append(stamps, ts)
+		w.Header().Set("Date", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+	c := NewClient(srv.URL, "k", WithRequestSigning("", []byte("x")))
+	c.ServerInfo(ctx)
+	c.ServerInfo(ctx)
+	if d := stamps[1] - stamps[0]; d < 3590 || d > 3610 {
+		t.Errorf("corrected timestamp moved %ds, want about 3600s", d)
+	}
+
+	stamps = nil
+	c = NewClient(srv.URL, "k", WithRequestSigning("", []byte("x")), WithClockSkewTolerance(-1))
+	c.ServerInfo(ctx)
+	c.ServerInfo(ctx)
+	if d := stamps[1] - stamps[0]; d > 1 {
+		t.Errorf("uncorrected timestamp moved %ds", d)
+	}
+}
@@ -0,0 +1,65 @@
+package strict
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// sseEvent is one Server-Sent Events message.
+type sseEvent struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+// sseReader parses a text/event-stream body as described in the HTML
+// Living Standard. Lines are read without a length limit, since a final
+// result event can be large.
+type sseReader struct {
+	r *bufio.Reader
+}
+
+func newSSEReader(r io.Reader) *sseReader {
+	return &sseReader{r: bufio.NewReader(r)}
+}
+
+// next returns the next event with data, or io.EOF at the end of the
+// stream.
+func (s *sseReader) next() (sseEvent, error) {
+	var ev sseEvent
+	var data []string
+	hasData := false
+	for {
+		line, err := s.r.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return sseEvent{}, err
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		if line == "" {
+			if hasData {
+				ev.Data = strings.Join(data, "\n")
+				if ev.Event == "" {
+					ev.Event = "message"
+				}
+				return ev, nil
+			}
+			ev = sseEvent{}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			ev.Event = value
+		case "data":
+			data = append(data, value)
+			hasData = true
+		case "id":
+			ev.ID = value
+		}
+	}
+}
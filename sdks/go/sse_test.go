@@ -0,0 +1,58 @@
+package strict
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSSEReader(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		want   []sseEvent
+	}{
+		{"empty", "", nil},
+		{"default event", "data: hello\n\n", []sseEvent{{Event: "message", Data: "hello"}}},
+		{"named with id", "id: 7\nevent: progress\ndata: {\"p\":1}\n\n", []sseEvent{{ID: "7", Event: "progress", Data: `{"p":1}`}}},
+		{"multi-line data", "data: a\ndata: b\n\n", []sseEvent{{Event: "message", Data: "a\nb"}}},
+		{"crlf", "event: x\r\ndata: y\r\n\r\n", []sseEvent{{Event: "x", Data: "y"}}},
+		{"no space after colon", "data:raw\n\n", []sseEvent{{Event: "message", Data: "raw"}}},
+		{"keeps second space", "data:  two\n\n", []sseEvent{{Event: "message", Data: " two"}}},
+		{"comments skipped", ": keep-alive\n\ndata: x\n\n", []sseEvent{{Event: "message", Data: "x"}}},
+		{"event without data dropped", "event: ping\n\ndata: x\n\n", []sseEvent{{Event: "message", Data: "x"}}},
+		{"unknown fields ignored", "retry: 10\nfoo: bar\ndata: x\n\n", []sseEvent{{Event: "message", Data: "x"}}},
+		{"empty data field", "data\n\n", []sseEvent{{Event: "message"}}},
+		{"unterminated last event", "data: x\n\ndata: partial", []sseEvent{{Event: "message", Data: "x"}}},
+		{"several", "event: a\ndata: 1\n\nevent: b\ndata: 2\n\n", []sseEvent{{Event: "a", Data: "1"}, {Event: "b", Data: "2"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newSSEReader(strings.NewReader(tt.stream))
+			var got []sseEvent
+			for {
+				ev, err := r.next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					t.Fatalf("next() = %v", err)
+				}
+				got = append(got, ev)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSSEReaderLongLine(t *testing.T) {
+	data := strings.Repeat("x", 1<<20)
+	ev, err := newSSEReader(strings.NewReader("data: " + data + "\n\n")).next()
+	if err != nil || ev.Data != data {
+		t.Fatalf("next() = %d bytes, %v", len(ev.Data), err)
+	}
+}
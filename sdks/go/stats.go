@@ -0,0 +1,219 @@
+package strict
+
+import (
+	"io"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of a client's cumulative activity since it was
+// created. Request counts are per logical call; retries are counted
+// separately.
+type Stats struct {
+	Requests      int64
+	Successes     int64
+	Failures      int64
+	Retries       int64
+	InFlight      int64
+	BytesSent     int64
+	BytesReceived int64
+	Latency       LatencySummary
+	// ByProcessor breaks down processing calls by requested processor
+	// type. Calls that don't name a type are counted under HybridProc,
+	// the server's default.
+	ByProcessor map[ProcessorType]ProcessorStats
+	// ByTenant breaks down calls made for a tenant by tenant ID. See
+	// WithTenant.
+	ByTenant map[string]TenantStats
+}
+
+// ProcessorStats is the per-processor-type part of Stats.
+type ProcessorStats struct {
+	Requests int64
+	Failures int64
+	Latency  LatencySummary
+}
+
+// TenantStats is the per-tenant part of Stats.
+type TenantStats struct {
+	Requests int64
+	Failures int64
+	Latency  LatencySummary
+}
+
+// LatencySummary holds approximate call latency percentiles. Values are
+// the upper bound of the histogram bucket the percentile falls in, so they
+// overestimate by at most 30%.
+type LatencySummary struct {
+	P50 time.Duration
+	P95 time.Duration
+	P99 time.Duration
+}
+
+// Stats returns a snapshot of the client's counters. It takes no locks and
+// is cheap enough to call from health endpoints.
+func (c *Client) Stats() Stats {
+	s := c.stats
+	out := Stats{
+		Requests:      s.requests.Load(),
+		Successes:     s.successes.Load(),
+		Failures:      s.failures.Load(),
+		Retries:       s.retries.Load(),
+		InFlight:      s.inFlight.Load(),
+		BytesSent:     s.bytesSent.Load(),
+		BytesReceived: s.bytesReceived.Load(),
+		Latency:       s.latency.summary(),
+		ByProcessor:   make(map[ProcessorType]ProcessorStats),
+		ByTenant:      make(map[string]TenantStats),
+	}
+	s.byProcessor.Range(func(k, v any) bool {
+		ps := v.(*processorStats)
+		out.ByProcessor[k.(ProcessorType)] = ProcessorStats{
+			Requests: ps.requests.Load(),
+			Failures: ps.failures.Load(),
+			Latency:  ps.latency.summary(),
+		}
+		return true
+	})
+	s.byTenant.Range(func(k, v any) bool {
+		ts := v.(*processorStats)
+		out.ByTenant[k.(string)] = TenantStats{
+			Requests: ts.requests.Load(),
+			Failures: ts.failures.Load(),
+			Latency:  ts.latency.summary(),
+		}
+		return true
+	})
+	return out
+}
+
+type clientStats struct {
+	requests      atomic.Int64
+	successes     atomic.Int64
+	failures      atomic.Int64
+	retries       atomic.Int64
+	inFlight      atomic.Int64
+	bytesSent     atomic.Int64
+	bytesReceived atomic.Int64
+	latency       latencyHistogram
+	byProcessor   sync.Map // ProcessorType -> *processorStats
+	byTenant      sync.Map // string -> *processorStats
+}
+
+// processorStats counts the calls in one breakdown of Stats.
+type processorStats struct {
+	requests atomic.Int64
+	failures atomic.Int64
+	streak   atomic.Int64 // consecutive failures
+	latency  latencyHistogram
+}
+
+func (s *clientStats) begin() {
+	s.inFlight.Add(1)
+}
+
+func (s *clientStats) end(cl *call, elapsed time.Duration, err error) {
+	s.inFlight.Add(-1)
+	s.requests.Add(1)
+	if err != nil {
+		s.failures.Add(1)
+	} else {
+		s.successes.Add(1)
+	}
+	if cl.attempt > 1 {
+		s.retries.Add(int64(cl.attempt - 1))
+	}
+	s.latency.observe(elapsed)
+
+	if cl.tenant != "" {
+		statsFor(&s.byTenant, cl.tenant).observe(elapsed, err)
+	}
+	if cl.request == nil {
+		return
+	}
+	pt := cl.request.ProcessorType
+	if pt == "" {
+		pt = HybridProc
+	}
+	statsFor(&s.byProcessor, pt).observe(elapsed, err)
+}
+
+func statsFor(m *sync.Map, key any) *processorStats {
+	v, ok := m.Load(key)
+	if !ok {
+		v, _ = m.LoadOrStore(key, new(processorStats))
+	}
+	return v.(*processorStats)
+}
+
+func (ps *processorStats) observe(elapsed time.Duration, err error) {
+	ps.requests.Add(1)
+	if err != nil {
+		ps.failures.Add(1)
+		ps.streak.Add(1)
+	} else {
+		ps.streak.Store(0)
+	}
+	ps.latency.observe(elapsed)
+}
+
+const (
+	latencyBuckets = 64
+	latencyBase    = 50 * time.Microsecond
+	latencyGrowth  = 1.3
+)
+
+// latencyHistogram is a fixed log-scale histogram of atomic counters.
+// Bucket i counts durations up to latencyBase * latencyGrowth^i.
+type latencyHistogram struct {
+	counts [latencyBuckets]atomic.Int64
+}
+
+func (h *latencyHistogram) observe(d time.Duration) {
+	i := 0
+	if d > latencyBase {
+		i = int(math.Ceil(math.Log(float64(d)/float64(latencyBase)) / math.Log(latencyGrowth)))
+		if i >= latencyBuckets {
+			i = latencyBuckets - 1
+		}
+	}
+	h.counts[i].Add(1)
+}
+
+func (h *latencyHistogram) summary() LatencySummary {
+	var counts [latencyBuckets]int64
+	var total int64
+	for i := range h.counts {
+		counts[i] = h.counts[i].Load()
+		total += counts[i]
+	}
+	if total == 0 {
+		return LatencySummary{}
+	}
+	quantile := func(q float64) time.Duration {
+		target := int64(math.Ceil(q * float64(total)))
+		var seen int64
+		for i, n := range counts {
+			seen += n
+			if seen >= target {
+				return time.Duration(float64(latencyBase) * math.Pow(latencyGrowth, float64(i)))
+			}
+		}
+		return time.Duration(float64(latencyBase) * math.Pow(latencyGrowth, latencyBuckets-1))
+	}
+	return LatencySummary{P50: quantile(0.50), P95: quantile(0.95), P99: quantile(0.99)}
+}
+
+// countingReader adds the number of bytes read to n.
+type countingReader struct {
+	io.ReadCloser
+	n *atomic.Int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n.Add(int64(n))
+	return n, err
+}
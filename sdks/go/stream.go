@@ -0,0 +1,337 @@
+package strict
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// streamChunkSize is how much input is read, hashed and encoded at a time.
+const streamChunkSize = 32 << 10
+
+// StreamOutput is the result of ProcessStream.
+type StreamOutput struct {
+	OutputSchema
+
+	// InputHash is the hex-encoded SHA-256 of the input, as the server
+	// computes it.
+	InputHash string
+	// InputTokens is the token count sent with the request.
+	InputTokens int
+}
+
+// ProcessStream processes input read from r without holding it in
+// memory. The request body is sent with chunked transfer encoding, and
+// the input hash and token count are computed as r is read. Invalid
+// UTF-8 in the input is replaced with U+FFFD.
+//
+// The request is prepared as ProcessRequest prepares one, except that
+// the router sees no input, and that with WithRequestValidation the
+// input's length and token count are checked once r is exhausted; a
+// failure aborts the request before it completes. A Tokenizer other
+// than WordTokenizer counts each chunk of input on its own, so a token
+// split between chunks may be counted twice.
+//
+// A stream can only be read once, so the call is never retried, hedged
+// or failed over to another endpoint. Use WithProcessorOverride to pick
+// a processor and WithCallTimeout to bound the call.
+func (c *Client) ProcessStream(ctx context.Context, r io.Reader, opts ...CallOption) (*StreamOutput, error) {
+	if c.envelope != nil {
+		return nil, ErrEncryptionUnsupported
+	}
+	co := newCallOptions(opts)
+	if co.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, co.timeout)
+		defer cancel()
+	}
+
+	var req ProcessingRequest
+	if err := c.prepareProcessor(ctx, &req, co); err != nil {
+		return nil, err
+	}
+	// The input isn't known yet: check the other fields now, and the
+	// input once it has been read.
+	if err := c.checkStreamed(req, 1); err != nil {
+		return nil, err
+	}
+	body := newStreamBody(r, req, c.tokenizerFor(req.ProcessorType))
+	body.check = func(tokens, n int) error {
+		checked := req
+		checked.InputTokens = tokens
+		return c.checkStreamed(checked, n)
+	}
+	var output StreamOutput
+	cl := &call{
+		op:      "ProcessStream",
+		method:  http.MethodPost,
+		path:    "/process/request",
+		stream:  body,
+		out:     &output.OutputSchema,
+		opts:    co,
+		request: &req,
+	}
+	if err := c.do(ctx, cl); err != nil {
+		if body.err != nil {
+			return nil, body.err
+		}
+		return nil, err
+	}
+	output.RequestID = cl.serverRequestID
+	output.InputHash = body.digest.sum()
+	output.InputTokens = body.tokens.count()
+	if err := c.verifyInputHash(output.InputHash, &output.OutputSchema); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+// streamBody renders a ProcessingRequest as JSON while reading its input
+// from src. input_tokens follows input_data so it can be counted first.
+type streamBody struct {
+	src    io.Reader
+	req    ProcessingRequest
+	digest *inputDigest
+	tokens tokenCounter
+	// runes counts the input's characters. check, if set, validates the
+	// input's token count and length once it has all been read; err is
+	// its failure, which aborted the request.
+	runes int
+	check func(tokens, n int) error
+	err   error
+
+	chunk []byte
+	// partial holds a UTF-8 sequence split across reads.
+	partial []byte
+	out     []byte
+	eof     bool
+	done    bool
+}
+
+// newStreamBody renders req with its input read from src, counting its
+// tokens with t. Only the processor type and dry-run flag are taken from
+// req.
+func newStreamBody(src io.Reader, req ProcessingRequest, t Tokenizer) *streamBody {
+	return &streamBody{
+		src:    src,
+		req:    req,
+		digest: newInputDigest(),
+		tokens: tokenCounter{t: t},
+		chunk:  make([]byte, streamChunkSize),
+		out:    []byte(`{"input_data":"`),
+	}
+}
+
+func (b *streamBody) Read(p []byte) (int, error) {
+	for len(b.out) == 0 {
+		if b.done {
+			return 0, io.EOF
+		}
+		if err := b.fill(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, b.out)
+	b.out = b.out[n:]
+	return n, nil
+}
+
+// fill reads the next chunk of input into out, or the closing fields
+// once the input is exhausted.
+func (b *streamBody) fill() error {
+	if b.eof {
+		b.tokens.flush()
+		if b.check != nil {
+			if err := b.check(b.tokens.count(), b.runes); err != nil {
+				b.err = err
+				return err
+			}
+		}
+		b.out = append(b.out, `","input_tokens":`...)
+		b.out = strconv.AppendInt(b.out, int64(b.tokens.count()), 10)
+		if b.req.ProcessorType != "" {
+			b.out = append(b.out, `,"processor_type":`...)
+			b.out = strconv.AppendQuote(b.out, string(b.req.ProcessorType))
+		}
+		if b.req.DryRun {
+			b.out = append(b.out, `,"dry_run":true`...)
+		}
+		b.out = append(b.out, '}')
+		b.done = true
+		return nil
+	}
+
+	n, err := b.src.Read(b.chunk)
+	if err == io.EOF {
+		b.eof = true
+	} else if err != nil {
+		return err
+	}
+	b.digest.write(b.chunk[:n])
+	data := b.chunk[:n]
+	if len(b.partial) > 0 {
+		data = append(b.partial, data...)
+		b.partial = nil
+	}
+	if !b.eof {
+		cut := incompleteTail(data)
+		b.partial = append([]byte(nil), data[cut:]...)
+		data = data[:cut]
+	}
+	b.tokens.write(data)
+	b.runes += utf8.RuneCount(data)
+	quoted, _ := json.Marshal(string(data))
+	b.out = append(b.out, quoted[1:len(quoted)-1]...)
+	return nil
+}
+
+// incompleteTail returns the index where a UTF-8 sequence cut off at the
+// end of p begins, or len(p) if p ends on a rune boundary.
+func incompleteTail(p []byte) int {
+	for i := len(p) - 1; i >= 0 && i >= len(p)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(p[i]) {
+			if !utf8.FullRune(p[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(p)
+}
+
+// inputDigest hashes input written to it in pieces as ComputeInputHash
+// hashes it whole: runes split between writes are joined, and each
+// invalid UTF-8 byte is hashed as U+FFFD. It counts the input's
+// characters in runes, the partial rune at the end only once sum is
+// called.
+type inputDigest struct {
+	h       hash.Hash
+	runes   int
+	partial []byte
+}
+
+func newInputDigest() *inputDigest {
+	return &inputDigest{h: sha256.New()}
+}
+
+func (d *inputDigest) write(p []byte) {
+	if len(d.partial) > 0 {
+		p = append(d.partial, p...)
+		d.partial = nil
+	}
+	cut := incompleteTail(p)
+	d.partial = append(d.partial, p[cut:]...)
+	writeValidUTF8(d.h, p[:cut])
+	d.runes += utf8.RuneCount(p[:cut])
+}
+
+// sum hashes any trailing partial rune and returns the hex-encoded
+// digest.
+func (d *inputDigest) sum() string {
+	writeValidUTF8(d.h, d.partial)
+	d.runes += utf8.RuneCount(d.partial)
+	d.partial = nil
+	return hex.EncodeToString(d.h.Sum(nil))
+}
+
+// writeValidUTF8 writes p to w with each invalid UTF-8 byte replaced by
+// U+FFFD.
+func writeValidUTF8(w io.Writer, p []byte) {
+	start := 0
+	for i := 0; i < len(p); {
+		r, size := utf8.DecodeRune(p[i:])
+		if r == utf8.RuneError && size == 1 {
+			w.Write(p[start:i])
+			w.Write([]byte("\uFFFD"))
+			start = i + 1
+		}
+		i += size
+	}
+	w.Write(p[start:])
+}
+
+// tokenCounter counts the tokens in input written to it in pieces. With
+// WordTokenizer it counts words across writes exactly; another Tokenizer
+// counts each piece on its own, with runes split between writes joined.
+// A nil Tokenizer counts nothing.
+type tokenCounter struct {
+	t       Tokenizer
+	words   wordCounter
+	n       int
+	partial []byte
+}
+
+func (tc *tokenCounter) write(p []byte) {
+	switch tc.t.(type) {
+	case nil:
+	case wordTokenizer:
+		tc.words.write(p)
+	default:
+		if len(tc.partial) > 0 {
+			p = append(tc.partial, p...)
+			tc.partial = nil
+		}
+		cut := incompleteTail(p)
+		tc.partial = append(tc.partial, p[cut:]...)
+		if cut > 0 {
+			tc.n += tc.t.CountTokens(string(p[:cut]))
+		}
+	}
+}
+
+// flush counts any trailing partial rune.
+func (tc *tokenCounter) flush() {
+	tc.words.flush()
+	if len(tc.partial) > 0 {
+		tc.n += tc.t.CountTokens(string(tc.partial))
+		tc.partial = nil
+	}
+}
+
+func (tc *tokenCounter) count() int {
+	return tc.n + tc.words.n
+}
+
+// wordCounter counts whitespace-separated words written to it, across
+// write boundaries, including runes split between writes.
+type wordCounter struct {
+	n       int
+	inWord  bool
+	partial []byte
+}
+
+func (w *wordCounter) write(p []byte) {
+	if len(w.partial) > 0 {
+		p = append(w.partial, p...)
+		w.partial = nil
+	}
+	cut := incompleteTail(p)
+	w.partial = append(w.partial, p[cut:]...)
+	w.count(p[:cut])
+}
+
+// flush counts any trailing partial rune.
+func (w *wordCounter) flush() {
+	w.count(w.partial)
+	w.partial = nil
+}
+
+func (w *wordCounter) count(p []byte) {
+	for len(p) > 0 {
+		r, size := utf8.DecodeRune(p)
+		p = p[size:]
+		if unicode.IsSpace(r) {
+			w.inWord = false
+		} else if !w.inWord {
+			w.inWord = true
+			w.n++
+		}
+	}
+}
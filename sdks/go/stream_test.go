@@ -0,0 +1,192 @@
+package strict
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var inputHashCases = []struct {
+	name  string
+	input string
+}{
+	{"empty", ""},
+	{"ascii", "hello world"},
+	{"multibyte", "ab 日本 語 😀 "},
+	{"literal replacement char", "a�b"},
+	{"invalid byte", "a\xffb"},
+	{"invalid run", "a\xff\xfe\xfdb"},
+	{"truncated rune", "日本\xe6\x97"},
+	{"truncated rune mid-input", "a\xe6\x97 b"},
+}
+
+func TestInputDigest(t *testing.T) {
+	for _, tt := range inputHashCases {
+		want := ComputeInputHash(ProcessingRequest{InputData: tt.input})
+		for _, size := range []int{1, 2, 3, 5, len(tt.input) + 1} {
+			t.Run(tt.name+"/"+strconv.Itoa(size), func(t *testing.T) {
+				d := newInputDigest()
+				for p := []byte(tt.input); len(p) > 0; {
+					n := min(size, len(p))
+					d.write(p[:n])
+					p = p[n:]
+				}
+				if got := d.sum(); got != want {
+					t.Errorf("digest of %q in %d-byte writes = %s, want %s", tt.input, size, got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestProcessStreamInputHash(t *testing.T) {
+	for _, tt := range inputHashCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent ProcessingRequest
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+					t.Errorf("decode request: %v", err)
+				}
+				w.Write([]byte(`{"result":"ok","processor_used":"local"}`))
+			}))
+			defer srv.Close()
+			out, err := NewClient(srv.URL, "k").ProcessStream(context.Background(), strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := ComputeInputHash(sent); out.InputHash != want {
+				t.Errorf("InputHash = %s, want %s for the input the server received", out.InputHash, want)
+			}
+		})
+	}
+}
+
+func TestProcessUploadInputHash(t *testing.T) {
+	for _, tt := range inputHashCases {
+		if len(tt.input) <= 1 {
+			continue // sent whole, not uploaded
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				got  []byte
+				done uploadComplete
+			)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPost && r.URL.Path == "/uploads":
+					w.Write([]byte(`{"upload_id":"u1","offset":0}`))
+				case r.Method == http.MethodPut && r.URL.Path == "/uploads/u1":
+					b, _ := io.ReadAll(r.Body)
+					got = append(got, b...)
+					w.Write([]byte(`{"offset":` + strconv.Itoa(len(got)) + `}`))
+				case r.URL.Path == "/uploads/u1/complete":
+					json.NewDecoder(r.Body).Decode(&done)
+					w.Write([]byte(`{"result":"ok","processor_used":"local"}`))
+				default:
+					t.Errorf("unexpected %s %s", r.Method, r.URL)
+				}
+			}))
+			defer srv.Close()
+			c := NewClient(srv.URL, "k", WithChunkedUpload(UploadConfig{Threshold: 1, ChunkSize: 3}))
+			if _, err := c.ProcessUpload(context.Background(), strings.NewReader(tt.input), int64(len(tt.input))); err != nil {
+				t.Fatal(err)
+			}
+			if want := ComputeInputHash(ProcessingRequest{InputData: string(got)}); done.InputHash != want {
+				t.Errorf("InputHash = %s, want %s", done.InputHash, want)
+			}
+		})
+	}
+}
+
+func TestStreamedRequestPreparation(t *testing.T) {
+	var errRegion *RegionError
+	tests := []struct {
+		name          string
+		opts          []Option
+		callOpts      []CallOption
+		input         string
+		wantProcessor ProcessorType
+		wantTokens    int
+		wantErr       any
+	}{
+		{"defaults", nil, nil, "one two three", "", 3, nil},
+		{"default processor", []Option{WithDefaultProcessor(Cloud)}, nil, "one two", Cloud, 2, nil},
+		{"override beats router", []Option{WithRouter(RouterFunc(func(RouteInput) ProcessorType { return Cloud }))}, []CallOption{WithProcessorOverride(Local)}, "a b", Local, 2, nil},
+		{"router", []Option{WithRouter(RouterFunc(func(RouteInput) ProcessorType { return HybridProc }))}, nil, "a b", HybridProc, 2, nil},
+		{"processor tokenizer", []Option{WithDefaultProcessor(Cloud), WithProcessorTokenizer(Cloud, TokenizerFunc(func(s string) int { return len(s) }))}, nil, "abcdefgh", Cloud, 8, nil},
+		{"tokenizer off", []Option{WithTokenizer(nil)}, nil, "a b c", "", 0, nil},
+		{"region", []Option{WithRegion("ap-southeast-1"), WithDefaultProcessor(Local)}, nil, "a b", "", 0, &errRegion},
+		{"too long", []Option{WithRequestValidation(RequestLimits{MaxInputLength: 4})}, nil, "日本語 日本語", "", 0, ErrValidationFailed},
+		{"too many tokens", []Option{WithRequestValidation(RequestLimits{MaxInputTokens: 2})}, nil, "a b c d", "", 0, ErrValidationFailed},
+		{"within limits", []Option{WithRequestValidation(RequestLimits{MaxInputLength: 7, MaxInputTokens: 2})}, nil, "日本語 日本語", "", 2, nil},
+	}
+	for _, tt := range tests {
+		for _, upload := range []bool{false, true} {
+			name := tt.name + "/stream"
+			if upload {
+				name = tt.name + "/upload"
+			}
+			t.Run(name, func(t *testing.T) {
+				var sent struct {
+					ProcessorType ProcessorType `json:"processor_type"`
+					InputTokens   int           `json:"input_tokens"`
+				}
+				completed := false
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					switch r.URL.Path {
+					case "/uploads":
+						w.Write([]byte(`{"upload_id":"u1","offset":0}`))
+						return
+					case "/uploads/u1":
+						b, _ := io.ReadAll(r.Body)
+						off, _ := strconv.Atoi(r.Header.Get("Upload-Offset"))
+						w.Write([]byte(`{"offset":` + strconv.Itoa(off+len(b)) + `}`))
+						return
+					}
+					if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+						return
+					}
+					completed = true
+					w.Write([]byte(`{"result":"ok","processor_used":"local"}`))
+				}))
+				defer srv.Close()
+				c := NewClient(srv.URL, "k", append([]Option{WithChunkedUpload(UploadConfig{Threshold: 1, ChunkSize: 3})}, tt.opts...)...)
+				var err error
+				if upload {
+					_, err = c.ProcessUpload(context.Background(), strings.NewReader(tt.input), int64(len(tt.input)), tt.callOpts...)
+				} else {
+					_, err = c.ProcessStream(context.Background(), strings.NewReader(tt.input), tt.callOpts...)
+				}
+				switch target := tt.wantErr.(type) {
+				case nil:
+					if err != nil {
+						t.Fatal(err)
+					}
+				case error:
+					if !errors.Is(err, target) {
+						t.Fatalf("err = %v, want %v", err, target)
+					}
+				default:
+					if !errors.As(err, target) {
+						t.Fatalf("err = %v, want %T", err, target)
+					}
+				}
+				if tt.wantErr != nil {
+					if completed {
+						t.Error("request completed despite the error")
+					}
+					return
+				}
+				if sent.ProcessorType != tt.wantProcessor || sent.InputTokens != tt.wantTokens {
+					t.Errorf("sent processor %q, %d tokens; want %q, %d", sent.ProcessorType, sent.InputTokens, tt.wantProcessor, tt.wantTokens)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// Package strictcbor adds CBOR (RFC 8949) encoding to the strict Go
+// client, for devices that talk to the binary API endpoint.
+//
+//	c := strict.NewClient(url, key, strict.WithCodec(strictcbor.Codec()))
+//
+// Struct fields are mapped using their json tags, so the same types work
+// with either encoding.
+package strictcbor
+
+import (
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+// ContentType is the media type the codec sends and accepts.
+const ContentType = "application/cbor"
+
+// Codec returns a strict.Codec for CBOR. Untyped maps, such as those in
+// OutputSchema.Result, decode to map[string]interface{}, matching
+// OutputSchema.ResultValue.
+func Codec() strict.Codec {
+	dm, err := cbor.DecOptions{
+		DefaultMapType: reflect.TypeOf(map[string]interface{}(nil)),
+	}.DecMode()
+	if err != nil {
+		// DecMode only fails on invalid options.
+		panic(err)
+	}
+	return codec{dec: dm}
+}
+
+type codec struct {
+	dec cbor.DecMode
+}
+
+func (codec) ContentType() string { return ContentType }
+
+func (codec) Marshal(v interface{}) ([]byte, error) { return cbor.Marshal(v) }
+
+func (c codec) Unmarshal(data []byte, v interface{}) error { return c.dec.Unmarshal(data, v) }
@@ -0,0 +1,61 @@
+// Package strictcreds provides credentials providers for the strict Go
+// client: environment variables, credential files with profiles, and
+// refreshing keys fetched from a secrets manager.
+//
+//	c := strict.NewClient(url, "", strict.WithCredentials(strictcreds.Default()))
+//
+// Every provider is safe for concurrent use.
+package strictcreds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+// Environment variables read by the providers.
+const (
+	EnvAPIKey          = strict.EnvAPIKey
+	EnvProfile         = strict.EnvProfile
+	EnvCredentialsFile = "STRICT_CREDENTIALS_FILE"
+)
+
+// ErrNoCredentials is returned by a provider that found no key. Chain
+// moves on to the next provider when it sees it.
+var ErrNoCredentials = errors.New("strictcreds: no credentials found")
+
+// Default returns the usual provider chain: the STRICT_API_KEY
+// environment variable, then the default credentials file.
+func Default() strict.CredentialsProvider {
+	return Chain(Env(EnvAPIKey), File("", ""))
+}
+
+// Env returns a provider that reads the key from the named environment
+// variable on every call, so a changed value is picked up without
+// restarting.
+func Env(name string) strict.CredentialsProvider {
+	return strict.CredentialsFunc(func(context.Context) (string, error) {
+		if key := os.Getenv(name); key != "" {
+			return key, nil
+		}
+		return "", fmt.Errorf("%w: %s is not set", ErrNoCredentials, name)
+	})
+}
+
+// Chain returns a provider that tries each of providers in order and uses
+// the first key found. Errors other than ErrNoCredentials stop the chain.
+func Chain(providers ...strict.CredentialsProvider) strict.CredentialsProvider {
+	return strict.CredentialsFunc(func(ctx context.Context) (string, error) {
+		for _, p := range providers {
+			key, err := p.APIKey(ctx)
+			if errors.Is(err, ErrNoCredentials) {
+				continue
+			}
+			return key, err
+		}
+		return "", ErrNoCredentials
+	})
+}
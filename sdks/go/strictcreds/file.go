@@ -0,0 +1,142 @@
+package strictcreds
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+// DefaultProfile is the profile used when none is named.
+const DefaultProfile = strict.DefaultProfile
+
+// File returns a provider that reads the api_key of a profile from a
+// credentials file:
+//
+//	[default]
+//	api_key = sk_live_...
+//
+//	[staging]
+//	api_key = sk_test_...
+//
+// An empty path means $STRICT_CREDENTIALS_FILE, or ~/.strict/credentials
+// if that is unset; an empty profile means $STRICT_PROFILE, or "default".
+// The file is read again whenever it changes, so keys can be rotated in
+// place.
+func File(path, profile string) strict.CredentialsProvider {
+	return &fileProvider{path: path, profile: profile}
+}
+
+type fileProvider struct {
+	path    string
+	profile string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	key     string
+}
+
+func (p *fileProvider) APIKey(context.Context) (string, error) {
+	path, profile, err := p.resolve()
+	if err != nil {
+		return "", err
+	}
+	fi, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("%w: %s does not exist", ErrNoCredentials, path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("strictcreds: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.key != "" && fi.ModTime().Equal(p.modTime) && fi.Size() == p.size {
+		return p.key, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("strictcreds: %w", err)
+	}
+	profiles, err := parseCredentials(data)
+	if err != nil {
+		return "", fmt.Errorf("strictcreds: %s: %w", path, err)
+	}
+	key := profiles[profile]["api_key"]
+	if key == "" {
+		return "", fmt.Errorf("%w: no api_key for profile %q in %s", ErrNoCredentials, profile, path)
+	}
+	p.key, p.modTime, p.size = key, fi.ModTime(), fi.Size()
+	return key, nil
+}
+
+// resolve fills in the default path and profile from the environment.
+func (p *fileProvider) resolve() (path, profile string, err error) {
+	path, profile = p.path, p.profile
+	if path == "" {
+		path = os.Getenv(EnvCredentialsFile)
+	}
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", "", fmt.Errorf("%w: %v", ErrNoCredentials, err)
+		}
+		path = filepath.Join(home, ".strict", "credentials")
+	}
+	if profile == "" {
+		profile = os.Getenv(EnvProfile)
+	}
+	if profile == "" {
+		profile = DefaultProfile
+	}
+	return path, profile, nil
+}
+
+// parseCredentials parses an INI-style file into key-value pairs per
+// section. Lines starting with '#' or ';' are comments, and values may
+// be quoted.
+func parseCredentials(data []byte) (map[string]map[string]string, error) {
+	profiles := make(map[string]map[string]string)
+	var section map[string]string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+			continue
+		case line[0] == '[':
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: malformed section header", n)
+			}
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if profiles[name] == nil {
+				profiles[name] = make(map[string]string)
+			}
+			section = profiles[name]
+		default:
+			k, v, ok := strings.Cut(line, "=")
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected key = value", n)
+			}
+			if section == nil {
+				return nil, fmt.Errorf("line %d: key outside a profile section", n)
+			}
+			v = strings.TrimSpace(v)
+			if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+				v = v[1 : len(v)-1]
+			}
+			section[strings.TrimSpace(k)] = v
+		}
+	}
+	return profiles, sc.Err()
+}
@@ -0,0 +1,114 @@
+package strictcreds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+// Secret is an API key fetched from a Source.
+type Secret struct {
+	Key string
+	// ExpiresAt is when the key stops working, or zero if the source
+	// doesn't say.
+	ExpiresAt time.Time
+}
+
+// Source fetches an API key from a secrets store such as Vault or AWS
+// Secrets Manager. Adapt a store's client to it with SourceFunc.
+type Source interface {
+	Fetch(ctx context.Context) (Secret, error)
+}
+
+// SourceFunc adapts a function to a Source.
+type SourceFunc func(ctx context.Context) (Secret, error)
+
+// Fetch calls f.
+func (f SourceFunc) Fetch(ctx context.Context) (Secret, error) { return f(ctx) }
+
+// RefreshOptions tunes Refreshing. The zero value uses the defaults.
+type RefreshOptions struct {
+	// Early is how long before ExpiresAt a key is refetched. Default 1m.
+	Early time.Duration
+	// MaxAge is how long a key without an expiry is used before it is
+	// refetched. Default 5m; negative keeps it until the process exits.
+	MaxAge time.Duration
+	// RetryAfter is how long the cached key is used after a failed
+	// refresh before the next try, capped at its expiry. Default 10s.
+	RetryAfter time.Duration
+}
+
+const (
+	defaultRefreshEarly      = time.Minute
+	defaultRefreshMaxAge     = 5 * time.Minute
+	defaultRefreshRetryAfter = 10 * time.Second
+)
+
+// Refreshing returns a provider that caches the key fetched from src and
+// fetches a new one shortly before it expires. If a refresh fails while
+// the cached key is still valid, the cached key is used and the refresh
+// is tried again after RetryAfter, or when the key expires if that is
+// sooner.
+func Refreshing(src Source, opts RefreshOptions) strict.CredentialsProvider {
+	if opts.Early <= 0 {
+		opts.Early = defaultRefreshEarly
+	}
+	if opts.MaxAge == 0 {
+		opts.MaxAge = defaultRefreshMaxAge
+	}
+	if opts.RetryAfter <= 0 {
+		opts.RetryAfter = defaultRefreshRetryAfter
+	}
+	return &refreshing{src: src, opts: opts}
+}
+
+type refreshing struct {
+	src  Source
+	opts RefreshOptions
+
+	mu        sync.Mutex
+	secret    Secret
+	refreshAt time.Time
+}
+
+func (r *refreshing) APIKey(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	if r.secret.Key != "" && (r.refreshAt.IsZero() || now.Before(r.refreshAt)) {
+		return r.secret.Key, nil
+	}
+	s, err := r.src.Fetch(ctx)
+	if err == nil && s.Key == "" {
+		err = errors.New("source returned an empty key")
+	}
+	if err != nil {
+		if r.secret.Key != "" && (r.secret.ExpiresAt.IsZero() || now.Before(r.secret.ExpiresAt)) {
+			retry := r.opts.RetryAfter
+			if !r.secret.ExpiresAt.IsZero() {
+				retry = min(retry, r.secret.ExpiresAt.Sub(now))
+			}
+			r.refreshAt = now.Add(retry)
+			return r.secret.Key, nil
+		}
+		return "", fmt.Errorf("strictcreds: refresh: %w", err)
+	}
+	r.secret = s
+	switch {
+	case !s.ExpiresAt.IsZero():
+		r.refreshAt = s.ExpiresAt.Add(-r.opts.Early)
+		if !r.refreshAt.After(now) {
+			// A key shorter-lived than Early is refreshed halfway through.
+			r.refreshAt = now.Add(s.ExpiresAt.Sub(now) / 2)
+		}
+	case r.opts.MaxAge > 0:
+		r.refreshAt = now.Add(r.opts.MaxAge)
+	default:
+		r.refreshAt = time.Time{}
+	}
+	return s.Key, nil
+}
@@ -0,0 +1,160 @@
+package strict
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// WithStrictDecoding makes the client reject JSON responses that carry
+// fields the SDK's types don't have, failing the call with
+// *UnknownFieldsError. Responses are decoded leniently by default, so a
+// newer server doesn't break older clients; turn this on in tests and CI
+// to catch drift between the server and the SDK early.
+func WithStrictDecoding() Option {
+	return func(c *Client) {
+		c.strictDecoding = true
+	}
+}
+
+// UnknownFieldsError reports response fields the SDK doesn't know.
+type UnknownFieldsError struct {
+	// Type is the Go type the response was decoded into.
+	Type string
+	// Fields are the unknown fields' dotted paths, sorted, such as
+	// "validation.score" or "results[0].extra".
+	Fields []string
+}
+
+func (e *UnknownFieldsError) Error() string {
+	return fmt.Sprintf("strict: response has fields unknown to %s: %s", e.Type, strings.Join(e.Fields, ", "))
+}
+
+// decodeJSON decodes data into v, checking for unknown fields if the
+// client decodes strictly. It checks the JSON against v's type itself,
+// rather than using json.Decoder.DisallowUnknownFields, because that
+// setting doesn't reach types with their own UnmarshalJSON.
+func (c *Client) decodeJSON(data []byte, v interface{}) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	if !c.strictDecoding {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return err
+	}
+	var unknown []string
+	findUnknown(doc, reflect.TypeOf(v), "", &unknown)
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return &UnknownFieldsError{Type: reflect.TypeOf(v).Elem().String(), Fields: unknown}
+}
+
+var rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
+// findUnknown appends the paths of object keys in doc that t has no
+// field for.
+func findUnknown(doc interface{}, t reflect.Type, path string, unknown *[]string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == rawMessageType {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return
+		}
+		fields := jsonFields(t)
+		for key, val := range obj {
+			f, ok := fields[key]
+			if !ok {
+				f, ok = foldField(fields, key)
+			}
+			if !ok {
+				*unknown = append(*unknown, path+key)
+				continue
+			}
+			findUnknown(val, f.Type, path+key+".", unknown)
+		}
+	case reflect.Slice, reflect.Array:
+		arr, ok := doc.([]interface{})
+		if !ok {
+			return
+		}
+		base := strings.TrimSuffix(path, ".")
+		for i, val := range arr {
+			findUnknown(val, t.Elem(), fmt.Sprintf("%s[%d].", base, i), unknown)
+		}
+	case reflect.Map:
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for key, val := range obj {
+			findUnknown(val, t.Elem(), path+key+".", unknown)
+		}
+	}
+}
+
+// jsonFields maps the JSON names of t's fields to the fields, including
+// those promoted from embedded structs.
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	var embedded []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			embedded = append(embedded, f)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f
+	}
+	for _, e := range embedded {
+		et := e.Type
+		if et.Kind() == reflect.Pointer {
+			et = et.Elem()
+		}
+		if et.Kind() != reflect.Struct {
+			continue
+		}
+		for name, f := range jsonFields(et) {
+			if _, ok := fields[name]; !ok {
+				fields[name] = f
+			}
+		}
+	}
+	return fields
+}
+
+// foldField matches key case-insensitively, as encoding/json does.
+func foldField(fields map[string]reflect.StructField, key string) (reflect.StructField, bool) {
+	for name, f := range fields {
+		if strings.EqualFold(name, key) {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
@@ -0,0 +1,187 @@
+// Package strictgrpc lets the strict Go client talk to the strict gRPC
+// gateway instead of the REST API. The transport translates each REST
+// call into the equivalent RPC, so retries, breakers, hooks and the rest
+// of the client behave exactly as they do over HTTP.
+//
+//	conn, err := grpc.NewClient("gateway:9090",
+//		grpc.WithTransportCredentials(credentials.NewTLS(nil)))
+//	...
+//	c := strict.NewClient("grpc://gateway:9090", key,
+//		strict.WithTransport(strictgrpc.Transport(conn)))
+//
+// The base URL is only used to build request URLs; the connection
+// decides where RPCs go. Request compression and non-JSON codecs are not
+// supported over gRPC; use the connection's own compression instead.
+package strictgrpc
+
+//go:generate protoc -I proto --go_out=. --go_opt=module=github.com/mohitmishra786/strict/sdks/go/strictgrpc --go-grpc_out=. --go-grpc_opt=module=github.com/mohitmishra786/strict/sdks/go/strictgrpc strict/v1/strict.proto
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/mohitmishra786/strict/sdks/go/strictgrpc/strictv1"
+)
+
+// Transport returns an http.RoundTripper that sends the client's calls
+// as RPCs over conn. Calls with no gRPC equivalent fail with 501 Not
+// Implemented.
+func Transport(conn grpc.ClientConnInterface) http.RoundTripper {
+	return &transport{client: strictv1.NewStrictClient(conn)}
+}
+
+type transport struct {
+	client strictv1.StrictClient
+}
+
+// route invokes one RPC. body is the call's JSON request body.
+type route func(ctx context.Context, c strictv1.StrictClient, body []byte, opts ...grpc.CallOption) (proto.Message, error)
+
+var routes = map[string]route{
+	"POST /process/request": func(ctx context.Context, c strictv1.StrictClient, body []byte, opts ...grpc.CallOption) (proto.Message, error) {
+		var req strictv1.ProcessingRequest
+		if err := unmarshalOpts.Unmarshal(body, &req); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return c.ProcessRequest(ctx, &req, opts...)
+	},
+}
+
+var (
+	unmarshalOpts = protojson.UnmarshalOptions{DiscardUnknown: true}
+	// Proto field names match the REST API's JSON names.
+	marshalOpts = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+)
+
+// skipHeaders are HTTP headers that describe the HTTP exchange itself
+// and are not forwarded as metadata.
+var skipHeaders = map[string]bool{
+	"Accept":          true,
+	"Accept-Encoding": true,
+	"Connection":      true,
+	"Content-Length":  true,
+	"Content-Type":    true,
+	"Host":            true,
+	"Te":              true,
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	r, ok := routes[req.Method+" "+req.URL.Path]
+	if !ok {
+		return response(req, http.StatusNotImplemented, nil, detail("no gRPC method for "+req.Method+" "+req.URL.Path)), nil
+	}
+	if mt, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); len(body) > 0 && mt != "application/json" {
+		return response(req, http.StatusUnsupportedMediaType, nil, detail("gRPC transport requires JSON bodies")), nil
+	}
+	if enc := req.Header.Get("Content-Encoding"); enc != "" && enc != "identity" {
+		return response(req, http.StatusUnsupportedMediaType, nil, detail("gRPC transport does not support Content-Encoding "+enc)), nil
+	}
+
+	md := metadata.MD{}
+	for key, values := range req.Header {
+		if !skipHeaders[key] {
+			md.Append(strings.ToLower(key), values...)
+		}
+	}
+	ctx := metadata.NewOutgoingContext(req.Context(), md)
+
+	var header, trailer metadata.MD
+	out, err := r(ctx, t.client, body, grpc.Header(&header), grpc.Trailer(&trailer))
+	h := make(http.Header)
+	for _, m := range []metadata.MD{header, trailer} {
+		for key, values := range m {
+			for _, v := range values {
+				h.Add(key, v)
+			}
+		}
+	}
+	if err != nil {
+		st := status.Convert(err)
+		if st.Code() == codes.Canceled || st.Code() == codes.DeadlineExceeded {
+			if ctxErr := req.Context().Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+		}
+		return response(req, httpStatus(st.Code()), h, detail(st.Message())), nil
+	}
+	data, err := marshalOpts.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+	return response(req, http.StatusOK, h, data), nil
+}
+
+func response(req *http.Request, code int, h http.Header, body []byte) *http.Response {
+	if h == nil {
+		h = make(http.Header)
+	}
+	h.Set("Content-Type", "application/json")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		StatusCode:    code,
+		Proto:         "HTTP/2.0",
+		ProtoMajor:    2,
+		Header:        h,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// detail renders msg the way the REST API reports errors.
+func detail(msg string) []byte {
+	data, _ := json.Marshal(map[string]string{"detail": msg})
+	return data
+}
+
+// httpStatus maps a gRPC code to the status the REST API would use, so
+// the client classifies and retries errors the same way.
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusUnprocessableEntity
+	case codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
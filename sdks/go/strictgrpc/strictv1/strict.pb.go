@@ -0,0 +1,336 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: strict/v1/strict.proto
+
+package strictv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ProcessingRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	InputData      string                 `protobuf:"bytes,1,opt,name=input_data,json=inputData,proto3" json:"input_data,omitempty"`
+	InputTokens    int32                  `protobuf:"varint,2,opt,name=input_tokens,json=inputTokens,proto3" json:"input_tokens,omitempty"`
+	ProcessorType  string                 `protobuf:"bytes,3,opt,name=processor_type,json=processorType,proto3" json:"processor_type,omitempty"`
+	TimeoutSeconds float64                `protobuf:"fixed64,4,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`
+	DryRun         bool                   `protobuf:"varint,5,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ProcessingRequest) Reset() {
+	*x = ProcessingRequest{}
+	mi := &file_strict_v1_strict_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessingRequest) ProtoMessage() {}
+
+func (x *ProcessingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_strict_v1_strict_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessingRequest.ProtoReflect.Descriptor instead.
+func (*ProcessingRequest) Descriptor() ([]byte, []int) {
+	return file_strict_v1_strict_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ProcessingRequest) GetInputData() string {
+	if x != nil {
+		return x.InputData
+	}
+	return ""
+}
+
+func (x *ProcessingRequest) GetInputTokens() int32 {
+	if x != nil {
+		return x.InputTokens
+	}
+	return 0
+}
+
+func (x *ProcessingRequest) GetProcessorType() string {
+	if x != nil {
+		return x.ProcessorType
+	}
+	return ""
+}
+
+func (x *ProcessingRequest) GetTimeoutSeconds() float64 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+func (x *ProcessingRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type ValidationResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	IsValid       bool                   `protobuf:"varint,2,opt,name=is_valid,json=isValid,proto3" json:"is_valid,omitempty"`
+	InputHash     string                 `protobuf:"bytes,3,opt,name=input_hash,json=inputHash,proto3" json:"input_hash,omitempty"`
+	Errors        []string               `protobuf:"bytes,4,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidationResult) Reset() {
+	*x = ValidationResult{}
+	mi := &file_strict_v1_strict_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidationResult) ProtoMessage() {}
+
+func (x *ValidationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_strict_v1_strict_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidationResult.ProtoReflect.Descriptor instead.
+func (*ValidationResult) Descriptor() ([]byte, []int) {
+	return file_strict_v1_strict_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ValidationResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ValidationResult) GetIsValid() bool {
+	if x != nil {
+		return x.IsValid
+	}
+	return false
+}
+
+func (x *ValidationResult) GetInputHash() string {
+	if x != nil {
+		return x.InputHash
+	}
+	return ""
+}
+
+func (x *ValidationResult) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type OutputSchema struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Result           *structpb.Value        `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Validation       *ValidationResult      `protobuf:"bytes,2,opt,name=validation,proto3" json:"validation,omitempty"`
+	ProcessorUsed    string                 `protobuf:"bytes,3,opt,name=processor_used,json=processorUsed,proto3" json:"processor_used,omitempty"`
+	ProcessingTimeMs float64                `protobuf:"fixed64,4,opt,name=processing_time_ms,json=processingTimeMs,proto3" json:"processing_time_ms,omitempty"`
+	RetriesAttempted int32                  `protobuf:"varint,5,opt,name=retries_attempted,json=retriesAttempted,proto3" json:"retries_attempted,omitempty"`
+	DryRun           bool                   `protobuf:"varint,6,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *OutputSchema) Reset() {
+	*x = OutputSchema{}
+	mi := &file_strict_v1_strict_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OutputSchema) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutputSchema) ProtoMessage() {}
+
+func (x *OutputSchema) ProtoReflect() protoreflect.Message {
+	mi := &file_strict_v1_strict_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutputSchema.ProtoReflect.Descriptor instead.
+func (*OutputSchema) Descriptor() ([]byte, []int) {
+	return file_strict_v1_strict_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *OutputSchema) GetResult() *structpb.Value {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *OutputSchema) GetValidation() *ValidationResult {
+	if x != nil {
+		return x.Validation
+	}
+	return nil
+}
+
+func (x *OutputSchema) GetProcessorUsed() string {
+	if x != nil {
+		return x.ProcessorUsed
+	}
+	return ""
+}
+
+func (x *OutputSchema) GetProcessingTimeMs() float64 {
+	if x != nil {
+		return x.ProcessingTimeMs
+	}
+	return 0
+}
+
+func (x *OutputSchema) GetRetriesAttempted() int32 {
+	if x != nil {
+		return x.RetriesAttempted
+	}
+	return 0
+}
+
+func (x *OutputSchema) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+var File_strict_v1_strict_proto protoreflect.FileDescriptor
+
+const file_strict_v1_strict_proto_rawDesc = "" +
+	"\n" +
+	"\x16strict/v1/strict.proto\x12\tstrict.v1\x1a\x1cgoogle/protobuf/struct.proto\"\xbe\x01\n" +
+	"\x11ProcessingRequest\x12\x1d\n" +
+	"\n" +
+	"input_data\x18\x01 \x01(\tR\tinputData\x12!\n" +
+	"\finput_tokens\x18\x02 \x01(\x05R\vinputTokens\x12%\n" +
+	"\x0eprocessor_type\x18\x03 \x01(\tR\rprocessorType\x12'\n" +
+	"\x0ftimeout_seconds\x18\x04 \x01(\x01R\x0etimeoutSeconds\x12\x17\n" +
+	"\adry_run\x18\x05 \x01(\bR\x06dryRun\"|\n" +
+	"\x10ValidationResult\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x19\n" +
+	"\bis_valid\x18\x02 \x01(\bR\aisValid\x12\x1d\n" +
+	"\n" +
+	"input_hash\x18\x03 \x01(\tR\tinputHash\x12\x16\n" +
+	"\x06errors\x18\x04 \x03(\tR\x06errors\"\x96\x02\n" +
+	"\fOutputSchema\x12.\n" +
+	"\x06result\x18\x01 \x01(\v2\x16.google.protobuf.ValueR\x06result\x12;\n" +
+	"\n" +
+	"validation\x18\x02 \x01(\v2\x1b.strict.v1.ValidationResultR\n" +
+	"validation\x12%\n" +
+	"\x0eprocessor_used\x18\x03 \x01(\tR\rprocessorUsed\x12,\n" +
+	"\x12processing_time_ms\x18\x04 \x01(\x01R\x10processingTimeMs\x12+\n" +
+	"\x11retries_attempted\x18\x05 \x01(\x05R\x10retriesAttempted\x12\x17\n" +
+	"\adry_run\x18\x06 \x01(\bR\x06dryRun2Q\n" +
+	"\x06Strict\x12G\n" +
+	"\x0eProcessRequest\x12\x1c.strict.v1.ProcessingRequest\x1a\x17.strict.v1.OutputSchemaBGZEgithub.com/mohitmishra786/strict/sdks/go/strictgrpc/strictv1;strictv1b\x06proto3"
+
+var (
+	file_strict_v1_strict_proto_rawDescOnce sync.Once
+	file_strict_v1_strict_proto_rawDescData []byte
+)
+
+func file_strict_v1_strict_proto_rawDescGZIP() []byte {
+	file_strict_v1_strict_proto_rawDescOnce.Do(func() {
+		file_strict_v1_strict_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_strict_v1_strict_proto_rawDesc), len(file_strict_v1_strict_proto_rawDesc)))
+	})
+	return file_strict_v1_strict_proto_rawDescData
+}
+
+var file_strict_v1_strict_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_strict_v1_strict_proto_goTypes = []any{
+	(*ProcessingRequest)(nil), // 0: strict.v1.ProcessingRequest
+	(*ValidationResult)(nil),  // 1: strict.v1.ValidationResult
+	(*OutputSchema)(nil),      // 2: strict.v1.OutputSchema
+	(*structpb.Value)(nil),    // 3: google.protobuf.Value
+}
+var file_strict_v1_strict_proto_depIdxs = []int32{
+	3, // 0: strict.v1.OutputSchema.result:type_name -> google.protobuf.Value
+	1, // 1: strict.v1.OutputSchema.validation:type_name -> strict.v1.ValidationResult
+	0, // 2: strict.v1.Strict.ProcessRequest:input_type -> strict.v1.ProcessingRequest
+	2, // 3: strict.v1.Strict.ProcessRequest:output_type -> strict.v1.OutputSchema
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_strict_v1_strict_proto_init() }
+func file_strict_v1_strict_proto_init() {
+	if File_strict_v1_strict_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_strict_v1_strict_proto_rawDesc), len(file_strict_v1_strict_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_strict_v1_strict_proto_goTypes,
+		DependencyIndexes: file_strict_v1_strict_proto_depIdxs,
+		MessageInfos:      file_strict_v1_strict_proto_msgTypes,
+	}.Build()
+	File_strict_v1_strict_proto = out.File
+	file_strict_v1_strict_proto_goTypes = nil
+	file_strict_v1_strict_proto_depIdxs = nil
+}
@@ -0,0 +1,127 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.1
+// - protoc             (unknown)
+// source: strict/v1/strict.proto
+
+package strictv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Strict_ProcessRequest_FullMethodName = "/strict.v1.Strict/ProcessRequest"
+)
+
+// StrictClient is the client API for Strict service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Strict is the service exposed by the strict gRPC gateway. Messages
+// mirror the REST API's JSON bodies field for field.
+type StrictClient interface {
+	ProcessRequest(ctx context.Context, in *ProcessingRequest, opts ...grpc.CallOption) (*OutputSchema, error)
+}
+
+type strictClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStrictClient(cc grpc.ClientConnInterface) StrictClient {
+	return &strictClient{cc}
+}
+
+func (c *strictClient) ProcessRequest(ctx context.Context, in *ProcessingRequest, opts ...grpc.CallOption) (*OutputSchema, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OutputSchema)
+	err := c.cc.Invoke(ctx, Strict_ProcessRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StrictServer is the server API for Strict service.
+// All implementations must embed UnimplementedStrictServer
+// for forward compatibility.
+//
+// Strict is the service exposed by the strict gRPC gateway. Messages
+// mirror the REST API's JSON bodies field for field.
+type StrictServer interface {
+	ProcessRequest(context.Context, *ProcessingRequest) (*OutputSchema, error)
+	mustEmbedUnimplementedStrictServer()
+}
+
+// UnimplementedStrictServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedStrictServer struct{}
+
+func (UnimplementedStrictServer) ProcessRequest(context.Context, *ProcessingRequest) (*OutputSchema, error) {
+	return nil, status.Error(codes.Unimplemented, "method ProcessRequest not implemented")
+}
+func (UnimplementedStrictServer) mustEmbedUnimplementedStrictServer() {}
+func (UnimplementedStrictServer) testEmbeddedByValue()                {}
+
+// UnsafeStrictServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StrictServer will
+// result in compilation errors.
+type UnsafeStrictServer interface {
+	mustEmbedUnimplementedStrictServer()
+}
+
+func RegisterStrictServer(s grpc.ServiceRegistrar, srv StrictServer) {
+	// If the following call panics, it indicates UnimplementedStrictServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Strict_ServiceDesc, srv)
+}
+
+func _Strict_ProcessRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProcessingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StrictServer).ProcessRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Strict_ProcessRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StrictServer).ProcessRequest(ctx, req.(*ProcessingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Strict_ServiceDesc is the grpc.ServiceDesc for Strict service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Strict_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "strict.v1.Strict",
+	HandlerType: (*StrictServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ProcessRequest",
+			Handler:    _Strict_ProcessRequest_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "strict/v1/strict.proto",
+}
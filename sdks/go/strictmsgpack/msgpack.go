@@ -0,0 +1,45 @@
+// Package strictmsgpack adds MessagePack encoding to the strict Go
+// client, for pipelines where JSON serialization is a bottleneck.
+//
+//	c := strict.NewClient(url, key, strict.WithCodec(strictmsgpack.Codec()))
+//
+// Struct fields are mapped using their json tags, so the same types work
+// with either encoding.
+package strictmsgpack
+
+import (
+	"bytes"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+// ContentType is the media type the codec sends and accepts.
+const ContentType = "application/msgpack"
+
+// Codec returns a strict.Codec for MessagePack.
+func Codec() strict.Codec {
+	return codec{}
+}
+
+type codec struct{}
+
+func (codec) ContentType() string { return ContentType }
+
+func (codec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	enc.UseCompactInts(true)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (codec) Unmarshal(data []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
@@ -0,0 +1,131 @@
+// Package strictotel instruments the strict Go client with OpenTelemetry.
+//
+//	c := strict.NewClient(url, key, strictotel.Options()...)
+//
+// Each API call gets a client span carrying the processor type, input
+// tokens, retry count and response status, and the W3C trace context is
+// propagated to the server on every attempt.
+package strictotel
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+const instrumentationName = "github.com/mohitmishra786/strict/sdks/go/strictotel"
+
+type config struct {
+	provider   trace.TracerProvider
+	propagator propagation.TextMapPropagator
+}
+
+// Option configures the instrumentation.
+type Option func(*config)
+
+// WithTracerProvider uses tp instead of the global TracerProvider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = tp
+	}
+}
+
+// WithPropagator uses p instead of the global TextMapPropagator.
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = p
+	}
+}
+
+func newConfig(opts []Option) config {
+	c := config{
+		provider:   otel.GetTracerProvider(),
+		propagator: otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// Options returns the client options that enable tracing and trace context
+// propagation.
+func Options(opts ...Option) []strict.Option {
+	cfg := newConfig(opts)
+	return []strict.Option{
+		strict.WithTracer(newTracer(cfg)),
+		strict.WithMiddleware(propagate(cfg.propagator)),
+	}
+}
+
+// NewTracer returns a strict.Tracer that records a span per API call.
+func NewTracer(opts ...Option) strict.Tracer {
+	return newTracer(newConfig(opts))
+}
+
+// Propagator returns middleware that injects trace context headers, such
+// as traceparent, into every outgoing request.
+func Propagator(opts ...Option) strict.Middleware {
+	return propagate(newConfig(opts).propagator)
+}
+
+type tracer struct {
+	tracer trace.Tracer
+}
+
+func newTracer(cfg config) *tracer {
+	return &tracer{tracer: cfg.provider.Tracer(instrumentationName)}
+}
+
+func (t *tracer) StartCall(ctx context.Context, info *strict.CallInfo) (context.Context, func(*strict.CallResult)) {
+	attrs := []attribute.KeyValue{
+		attribute.String("http.request.method", info.Method),
+		attribute.String("url.path", info.Path),
+		attribute.String("strict.request_id", info.RequestID),
+	}
+	if info.CorrelationID != "" {
+		attrs = append(attrs, attribute.String("strict.correlation_id", info.CorrelationID))
+	}
+	if req := info.Request; req != nil {
+		attrs = append(attrs,
+			attribute.String("strict.processor_type", string(req.ProcessorType)),
+			attribute.Int("strict.input_tokens", req.InputTokens),
+		)
+	}
+	ctx, span := t.tracer.Start(ctx, "strict."+info.Operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx, func(res *strict.CallResult) {
+		retries := res.Attempts - 1
+		if retries < 0 {
+			retries = 0
+		}
+		span.SetAttributes(attribute.Int("strict.retries", retries))
+		if res.StatusCode != 0 {
+			span.SetAttributes(attribute.Int("http.response.status_code", res.StatusCode))
+		}
+		if res.Err != nil {
+			span.RecordError(res.Err)
+			span.SetAttributes(attribute.String("strict.error_class", strict.Classify(res.Err).String()))
+			span.SetStatus(codes.Error, res.Err.Error())
+		}
+		span.End()
+	}
+}
+
+func propagate(p propagation.TextMapPropagator) strict.Middleware {
+	return func(next strict.Handler) strict.Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			p.Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+			return next(req)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Package strictprom exports metrics about the strict Go client to
+// Prometheus.
+//
+//	col := strictprom.NewCollector()
+//	prometheus.MustRegister(col)
+//	c := strict.NewClient(url, key, col.Option())
+package strictprom
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+// Collector is a prometheus.Collector fed by client hooks. One Collector
+// may be shared by several clients.
+type Collector struct {
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+	retries  prometheus.Counter
+	inFlight prometheus.Gauge
+	tenants  bool
+}
+
+// Opts configures metric naming.
+type Opts struct {
+	// Namespace prefixes every metric name. Defaults to "strict_client".
+	Namespace string
+	// Buckets are the latency histogram buckets in seconds. Defaults to
+	// prometheus.DefBuckets.
+	Buckets []float64
+	// ConstLabels are attached to every metric.
+	ConstLabels prometheus.Labels
+	// TenantLabel adds a "tenant" label, from strict.WithTenant, to the
+	// request count and latency metrics. Leave it off when tenants are
+	// numerous, as each one adds a series per operation.
+	TenantLabel bool
+}
+
+// NewCollector returns a Collector using default Opts.
+func NewCollector() *Collector {
+	return NewCollectorWithOpts(Opts{})
+}
+
+// NewCollectorWithOpts returns a Collector configured by opts.
+func NewCollectorWithOpts(opts Opts) *Collector {
+	if opts.Namespace == "" {
+		opts.Namespace = "strict_client"
+	}
+	if opts.Buckets == nil {
+		opts.Buckets = prometheus.DefBuckets
+	}
+	requestLabels, latencyLabels := []string{"operation", "code"}, []string{"operation"}
+	if opts.TenantLabel {
+		requestLabels = append(requestLabels, "tenant")
+		latencyLabels = append(latencyLabels, "tenant")
+	}
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Name:        "requests_total",
+			Help:        "HTTP attempts made by the strict client, by operation and status code.",
+			ConstLabels: opts.ConstLabels,
+		}, requestLabels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Name:        "errors_total",
+			Help:        "Failed HTTP attempts, by error class.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"class"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   opts.Namespace,
+			Name:        "request_duration_seconds",
+			Help:        "Latency of HTTP attempts made by the strict client, by operation.",
+			Buckets:     opts.Buckets,
+			ConstLabels: opts.ConstLabels,
+		}, latencyLabels),
+		retries: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Name:        "retries_total",
+			Help:        "HTTP attempts that were retries of an earlier failure.",
+			ConstLabels: opts.ConstLabels,
+		}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   opts.Namespace,
+			Name:        "in_flight_requests",
+			Help:        "HTTP attempts currently in flight.",
+			ConstLabels: opts.ConstLabels,
+		}),
+		tenants: opts.TenantLabel,
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.errors.Describe(ch)
+	c.latency.Describe(ch)
+	c.retries.Describe(ch)
+	c.inFlight.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.errors.Collect(ch)
+	c.latency.Collect(ch)
+	c.retries.Collect(ch)
+	c.inFlight.Collect(ch)
+}
+
+// Hooks returns the client hooks that feed the collector.
+func (c *Collector) Hooks() strict.Hooks {
+	return strict.Hooks{
+		OnRequest:  c.onRequest,
+		OnResponse: c.onResponse,
+	}
+}
+
+// Option returns a client option that installs the collector's hooks.
+func (c *Collector) Option() strict.Option {
+	return strict.WithHooks(c.Hooks())
+}
+
+func (c *Collector) onRequest(_ context.Context, req *strict.RequestInfo) {
+	c.inFlight.Inc()
+	if req.Attempt > 1 {
+		c.retries.Inc()
+	}
+}
+
+func (c *Collector) onResponse(_ context.Context, resp *strict.ResponseInfo) {
+	c.inFlight.Dec()
+	// Label by operation rather than URL path, which carries job, upload
+	// and webhook IDs and so would add a series per resource.
+	op := resp.Request.Operation
+	code := "error"
+	if resp.StatusCode != 0 {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	requestLabels := []string{op, code}
+	latencyLabels := []string{op}
+	if c.tenants {
+		requestLabels = append(requestLabels, resp.Request.Tenant)
+		latencyLabels = append(latencyLabels, resp.Request.Tenant)
+	}
+	c.requests.WithLabelValues(requestLabels...).Inc()
+	c.latency.WithLabelValues(latencyLabels...).Observe(resp.Latency.Seconds())
+	if resp.Err != nil {
+		c.errors.WithLabelValues(strict.Classify(resp.Err).String()).Inc()
+	}
+}
@@ -0,0 +1,59 @@
+package strictprom
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+	"github.com/mohitmishra786/strict/sdks/go/stricttest"
+)
+
+func TestCollectorLabelsByOperation(t *testing.T) {
+	srv := stricttest.NewServer()
+	defer srv.Close()
+	col := NewCollector()
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(col)
+	c := srv.Client(col.Option())
+
+	ctx := context.Background()
+	for _, id := range []strict.JobID{"job-1", "job-2", "job-3"} {
+		c.GetJobStatus(ctx, id)
+	}
+	if _, err := c.ServerInfo(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]float64{}
+	for _, mf := range families {
+		if mf.GetName() != "strict_client_requests_total" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			var labels []string
+			for _, l := range m.GetLabel() {
+				labels = append(labels, l.GetName()+"="+l.GetValue())
+			}
+			got[strings.Join(labels, ",")] += m.GetCounter().GetValue()
+		}
+	}
+	want := map[string]float64{
+		"code=404,operation=GetJobStatus": 3,
+		"code=200,operation=ServerInfo":   1,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("requests_total series = %v, want %v", got, want)
+	}
+	for labels, n := range want {
+		if got[labels] != n {
+			t.Errorf("requests_total{%s} = %v, want %v", labels, got[labels], n)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package stricttest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+// EnvOpenAPISpec names the environment variable VerifyContract reads the
+// spec location from when it isn't given one.
+const EnvOpenAPISpec = "STRICT_OPENAPI_SPEC"
+
+// VerifyContract checks the SDK's wire types against the server's OpenAPI
+// spec with strict.CheckContract, failing t once for each mismatch.
+// source is a file path or an http(s) URL such as
+// "http://localhost:8000/openapi.json"; if empty, $STRICT_OPENAPI_SPEC is
+// used, and the test is skipped when that isn't set either:
+//
+//	func TestContract(t *testing.T) { stricttest.VerifyContract(t, "") }
+//
+// Run it in CI against the server's current spec to catch drift before a
+// release.
+func VerifyContract(t testing.TB, source string) {
+	t.Helper()
+	if source == "" {
+		source = os.Getenv(EnvOpenAPISpec)
+	}
+	if source == "" {
+		t.Skipf("stricttest: no OpenAPI spec; set %s", EnvOpenAPISpec)
+	}
+	spec, err := readSpec(source)
+	if err != nil {
+		t.Fatalf("stricttest: %v", err)
+	}
+	problems, err := strict.CheckContract(spec)
+	if err != nil {
+		t.Fatalf("stricttest: %s: %v", source, err)
+	}
+	for _, p := range problems {
+		t.Error(p)
+	}
+}
+
+// readSpec reads an OpenAPI document from a file or URL.
+func readSpec(source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("read spec: %w", err)
+		}
+		return data, nil
+	}
+	hc := &http.Client{Timeout: 30 * time.Second}
+	resp, err := hc.Get(source)
+	if err != nil {
+		return nil, fmt.Errorf("fetch spec: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch spec: %s: %s", source, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("fetch spec: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,72 @@
+package stricttest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestContract checks the SDK's wire types against the spec the mock
+// server publishes.
+func TestContract(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.RequireAPIKey("k1")
+	VerifyContract(t, srv.URL+"/openapi.json")
+}
+
+// TestContractLive checks them against the real server's spec when
+// STRICT_OPENAPI_SPEC names one.
+func TestContractLive(t *testing.T) {
+	VerifyContract(t, "")
+}
+
+func TestVerifyContractSkips(t *testing.T) {
+	t.Setenv(EnvOpenAPISpec, "")
+	VerifyContract(t, "")
+	t.Fatal("VerifyContract did not skip without a spec")
+}
+
+func TestReadSpec(t *testing.T) {
+	const spec = `{"openapi":"3.1.0"}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openapi.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(spec))
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "spec.json")
+	if err := os.WriteFile(file, []byte(spec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		wantErr bool
+	}{
+		{"url", srv.URL + "/openapi.json", false},
+		{"url not found", srv.URL + "/missing.json", true},
+		{"file", file, false},
+		{"missing file", filepath.Join(dir, "none.json"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := readSpec(tt.source)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("readSpec(%q) = %q, want error", tt.source, data)
+				}
+				return
+			}
+			if err != nil || string(data) != spec {
+				t.Errorf("readSpec(%q) = %q, %v, want %q", tt.source, data, err, spec)
+			}
+		})
+	}
+}
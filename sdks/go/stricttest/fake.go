@@ -0,0 +1,559 @@
+// Package stricttest helps test code that uses the strict Go client.
+// Server is a mock API server for tests that should exercise the real
+// HTTP path, and VCR records exchanges with a real server to replay them
+// offline, and VerifyContract checks the SDK's types against the
+// server's OpenAPI spec. FakeClient is an in-memory strict.API that records calls and
+// answers them with scripted responses, so code written against
+// strict.API can be unit-tested without a server at all:
+//
+//	fake := stricttest.NewFakeClient()
+//	fake.Script("bad input", stricttest.Response{Err: &strict.APIError{StatusCode: 422}})
+//	err := myCode(ctx, fake)
+//	if got := fake.Calls(); len(got) != 1 { ... }
+package stricttest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+// Response is a scripted answer to a request.
+type Response struct {
+	// Output is returned on success. If nil and Err is nil, the fake's
+	// default output is returned.
+	Output *strict.OutputSchema
+	Err    error
+	// Latency delays the answer, on top of FakeClient.Latency.
+	Latency time.Duration
+}
+
+// Call records one method call made on a FakeClient.
+type Call struct {
+	// Method is the strict.API method called, such as "ProcessRequest".
+	Method string
+	// Request is the processing request, for methods that take one.
+	// Streamed input is read into InputData.
+	Request strict.ProcessingRequest
+	// InputHash is strict.ComputeInputHash of Request, for methods that
+	// take one.
+	InputHash string
+	// JobID is the job the call was about, for job methods.
+	JobID strict.JobID
+	Time  time.Time
+}
+
+// FakeClient is an in-memory strict.API. Requests are answered with the
+// responses scripted for their input hash, in order, with the last one
+// repeated; requests with no script get a successful default output that
+// echoes the input. Jobs complete as soon as they are submitted. Call
+// options are accepted but ignored.
+//
+// A FakeClient is safe for concurrent use. Its exported fields must be
+// set before it is used.
+type FakeClient struct {
+	// Latency delays every call, as if the server were that far away. A
+	// call whose context ends first fails with the context's error.
+	Latency time.Duration
+	// Default answers requests with no scripted response. If nil, the
+	// default output is used.
+	Default func(req strict.ProcessingRequest) (*strict.OutputSchema, error)
+	// Now returns the current time; it defaults to time.Now.
+	Now func() time.Time
+
+	mu      sync.Mutex
+	scripts map[string][]Response
+	calls   []Call
+	jobs    map[strict.JobID]*fakeJob
+	hooks   webhookStore
+	nextID  int
+}
+
+type fakeJob struct {
+	status strict.JobStatus
+	output *strict.OutputSchema
+	err    error
+}
+
+var _ strict.API = (*FakeClient)(nil)
+
+// NewFakeClient returns a FakeClient with nothing scripted.
+func NewFakeClient() *FakeClient {
+	return &FakeClient{}
+}
+
+// Script sets the responses to requests whose InputData is input.
+func (f *FakeClient) Script(input string, responses ...Response) {
+	f.ScriptHash(strict.ComputeInputHash(strict.ProcessingRequest{InputData: input}), responses...)
+}
+
+// ScriptHash sets the responses to requests whose input hash, as
+// strict.ComputeInputHash computes it, is hash. Later responses are used
+// by later calls, and the last one is repeated. No responses clears the
+// script.
+func (f *FakeClient) ScriptHash(hash string, responses ...Response) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.scripts == nil {
+		f.scripts = make(map[string][]Response)
+	}
+	if len(responses) == 0 {
+		delete(f.scripts, hash)
+		return
+	}
+	f.scripts[hash] = append([]Response(nil), responses...)
+}
+
+// Calls returns the calls made so far, in order.
+func (f *FakeClient) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// CallsTo returns the calls made so far to method.
+func (f *FakeClient) CallsTo(method string) []Call {
+	var out []Call
+	for _, c := range f.Calls() {
+		if c.Method == method {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Reset forgets recorded calls, scripts, jobs and webhooks.
+func (f *FakeClient) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.scripts = nil
+	f.calls = nil
+	f.jobs = nil
+	f.hooks = webhookStore{}
+}
+
+func (f *FakeClient) now() time.Time {
+	if f.Now != nil {
+		return f.Now()
+	}
+	return time.Now()
+}
+
+// note records a call that carries no request.
+func (f *FakeClient) note(method string, id strict.JobID) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, Call{Method: method, JobID: id, Time: f.now()})
+}
+
+// record notes a call and returns the scripted response for req.
+func (f *FakeClient) record(method string, req strict.ProcessingRequest, id strict.JobID) (Response, bool) {
+	hash := strict.ComputeInputHash(req)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, Call{Method: method, Request: req, InputHash: hash, JobID: id, Time: f.now()})
+	script, ok := f.scripts[hash]
+	if !ok {
+		return Response{}, false
+	}
+	if len(script) > 1 {
+		f.scripts[hash] = script[1:]
+	}
+	return script[0], true
+}
+
+// wait sleeps for the fake's latency plus extra, or until ctx is done.
+func (f *FakeClient) wait(ctx context.Context, extra time.Duration) error {
+	d := f.Latency + extra
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// answer records a call for req and returns its response.
+func (f *FakeClient) answer(ctx context.Context, method string, req strict.ProcessingRequest) (*strict.OutputSchema, error) {
+	resp, ok := f.record(method, req, "")
+	if err := f.wait(ctx, resp.Latency); err != nil {
+		return nil, err
+	}
+	return f.resolve(req, resp, ok)
+}
+
+// resolve turns a scripted response, or the lack of one, into a result.
+func (f *FakeClient) resolve(req strict.ProcessingRequest, resp Response, scripted bool) (*strict.OutputSchema, error) {
+	if !scripted || (resp.Output == nil && resp.Err == nil) {
+		if f.Default != nil {
+			return f.Default(req)
+		}
+		return DefaultOutput(req), nil
+	}
+	if resp.Err != nil {
+		return nil, resp.Err
+	}
+	out := *resp.Output
+	return &out, nil
+}
+
+// DefaultOutput returns the output FakeClient gives req when nothing is
+// scripted: a valid result holding the input as a JSON string.
+func DefaultOutput(req strict.ProcessingRequest) *strict.OutputSchema {
+	result, _ := json.Marshal(req.InputData)
+	processor := req.ProcessorType
+	if processor == "" {
+		processor = strict.Cloud
+	}
+	return &strict.OutputSchema{
+		Result: json.RawMessage(result),
+		Validation: strict.ValidationResult{
+			Status:    "valid",
+			IsValid:   true,
+			InputHash: strict.ComputeInputHash(req)[:16],
+		},
+		ProcessorUsed: processor,
+		DryRun:        req.DryRun,
+		RequestID:     "fake-" + strict.ComputeInputHash(req)[:8],
+	}
+}
+
+// ProcessRequest answers req with its scripted response.
+func (f *FakeClient) ProcessRequest(ctx context.Context, req strict.ProcessingRequest, _ ...strict.CallOption) (*strict.OutputSchema, error) {
+	return f.answer(ctx, "ProcessRequest", req)
+}
+
+// ProcessRequestWithProgress answers like ProcessRequest, sending a
+// single 100% progress update first on success.
+func (f *FakeClient) ProcessRequestWithProgress(ctx context.Context, req strict.ProcessingRequest, progress chan<- strict.Progress, _ ...strict.CallOption) (*strict.OutputSchema, error) {
+	defer close(progress)
+	out, err := f.answer(ctx, "ProcessRequestWithProgress", req)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case progress <- strict.Progress{Percent: 100, Stage: "done"}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return out, nil
+}
+
+// ProcessBatch answers each request as ProcessRequest would, recording a
+// "ProcessBatch" call per request. Failures are reported per item.
+func (f *FakeClient) ProcessBatch(ctx context.Context, reqs []strict.ProcessingRequest, _ ...strict.CallOption) (*strict.BatchResult, error) {
+	res := &strict.BatchResult{Items: make([]strict.BatchItem, len(reqs))}
+	var latency time.Duration
+	for i, req := range reqs {
+		resp, ok := f.record("ProcessBatch", req, "")
+		if resp.Latency > latency {
+			latency = resp.Latency
+		}
+		it := strict.BatchItem{Index: i, Request: req}
+		it.Output, it.Err = f.resolve(req, resp, ok)
+		res.Items[i] = it
+	}
+	if err := f.wait(ctx, latency); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ValidateBatch returns the Validation of each request's answer,
+// recording a "ValidateBatch" call per request. A scripted error fails
+// the whole call.
+func (f *FakeClient) ValidateBatch(ctx context.Context, reqs []strict.ProcessingRequest, _ ...strict.CallOption) ([]strict.ValidationResult, error) {
+	results := make([]strict.ValidationResult, len(reqs))
+	var latency time.Duration
+	var firstErr error
+	for i, req := range reqs {
+		resp, ok := f.record("ValidateBatch", req, "")
+		if resp.Latency > latency {
+			latency = resp.Latency
+		}
+		out, err := f.resolve(req, resp, ok)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		results[i] = out.Validation
+	}
+	if err := f.wait(ctx, latency); err != nil {
+		return nil, err
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return results, nil
+}
+
+// ProcessStream reads r into a request and answers it.
+func (f *FakeClient) ProcessStream(ctx context.Context, r io.Reader, _ ...strict.CallOption) (*strict.StreamOutput, error) {
+	return f.stream(ctx, "ProcessStream", r)
+}
+
+// ProcessUpload reads the size bytes of r into a request and answers it.
+func (f *FakeClient) ProcessUpload(ctx context.Context, r io.ReaderAt, size int64, _ ...strict.CallOption) (*strict.StreamOutput, error) {
+	return f.stream(ctx, "ProcessUpload", io.NewSectionReader(r, 0, size))
+}
+
+func (f *FakeClient) stream(ctx context.Context, method string, r io.Reader) (*strict.StreamOutput, error) {
+	var b strings.Builder
+	if _, err := io.Copy(&b, r); err != nil {
+		return nil, fmt.Errorf("stricttest: read input: %w", err)
+	}
+	req := strict.ProcessingRequest{InputData: b.String()}
+	req.InputTokens = len(strings.Fields(req.InputData))
+	out, err := f.answer(ctx, method, req)
+	if err != nil {
+		return nil, err
+	}
+	return &strict.StreamOutput{
+		OutputSchema: *out,
+		InputHash:    strict.ComputeInputHash(req),
+		InputTokens:  req.InputTokens,
+	}, nil
+}
+
+// SubmitJob answers req at once and stores the answer as a finished job:
+// succeeded with the output, or failed with the error.
+func (f *FakeClient) SubmitJob(ctx context.Context, req strict.ProcessingRequest, _ ...strict.CallOption) (strict.JobID, error) {
+	f.mu.Lock()
+	f.nextID++
+	id := strict.JobID(fmt.Sprintf("fake-job-%d", f.nextID))
+	f.mu.Unlock()
+
+	resp, ok := f.record("SubmitJob", req, id)
+	if err := f.wait(ctx, resp.Latency); err != nil {
+		return "", err
+	}
+	out, err := f.resolve(req, resp, ok)
+	now := f.now()
+	job := &fakeJob{
+		status: strict.JobStatus{ID: id, State: strict.JobSucceeded, ProcessorType: req.ProcessorType, CreatedAt: now, UpdatedAt: now},
+		output: out,
+		err:    err,
+	}
+	if err != nil {
+		job.status.State = strict.JobFailed
+		job.status.Error = err.Error()
+	}
+	f.mu.Lock()
+	if f.jobs == nil {
+		f.jobs = make(map[strict.JobID]*fakeJob)
+	}
+	f.jobs[id] = job
+	f.mu.Unlock()
+	return id, nil
+}
+
+// job records a call about id and returns the job.
+func (f *FakeClient) job(ctx context.Context, method string, id strict.JobID) (*fakeJob, error) {
+	f.note(method, id)
+	if err := f.wait(ctx, 0); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	job, ok := f.jobs[id]
+	if !ok {
+		return nil, &strict.APIError{StatusCode: 404, Message: fmt.Sprintf("job %s not found", id)}
+	}
+	return job, nil
+}
+
+// GetJobStatus returns the status of a job submitted to f.
+func (f *FakeClient) GetJobStatus(ctx context.Context, id strict.JobID, _ ...strict.CallOption) (*strict.JobStatus, error) {
+	job, err := f.job(ctx, "GetJobStatus", id)
+	if err != nil {
+		return nil, err
+	}
+	st := job.status
+	return &st, nil
+}
+
+// GetJobResult returns the output of a job submitted to f, or the error
+// it failed with.
+func (f *FakeClient) GetJobResult(ctx context.Context, id strict.JobID, _ ...strict.CallOption) (*strict.OutputSchema, error) {
+	job, err := f.job(ctx, "GetJobResult", id)
+	if err != nil {
+		return nil, err
+	}
+	return jobResult(job)
+}
+
+// CancelJob returns the job's status. Jobs finish when submitted, so
+// there is never anything to cancel.
+func (f *FakeClient) CancelJob(ctx context.Context, id strict.JobID, _ ...strict.CallOption) (*strict.JobStatus, error) {
+	job, err := f.job(ctx, "CancelJob", id)
+	if err != nil {
+		return nil, err
+	}
+	st := job.status
+	return &st, nil
+}
+
+// AwaitJob returns the job's result, reporting its status to
+// opts.OnStatus first.
+func (f *FakeClient) AwaitJob(ctx context.Context, id strict.JobID, opts strict.AwaitOptions) (*strict.OutputSchema, error) {
+	job, err := f.job(ctx, "AwaitJob", id)
+	if err != nil {
+		return nil, err
+	}
+	if opts.OnStatus != nil {
+		st := job.status
+		opts.OnStatus(&st)
+	}
+	return jobResult(job)
+}
+
+func jobResult(job *fakeJob) (*strict.OutputSchema, error) {
+	if job.err != nil {
+		return nil, job.err
+	}
+	out := *job.output
+	return &out, nil
+}
+
+// Health reports the fake as healthy.
+func (f *FakeClient) Health(ctx context.Context) (*strict.HealthStatus, error) {
+	return f.health(ctx, "Health")
+}
+
+// Ready reports the fake as ready.
+func (f *FakeClient) Ready(ctx context.Context) (*strict.HealthStatus, error) {
+	return f.health(ctx, "Ready")
+}
+
+func (f *FakeClient) health(ctx context.Context, method string) (*strict.HealthStatus, error) {
+	f.note(method, "")
+	if err := f.wait(ctx, 0); err != nil {
+		return nil, err
+	}
+	return &strict.HealthStatus{Status: "ok", Version: "stricttest"}, nil
+}
+
+// ServerInfo describes the fake as a server supporting every processor
+// type.
+func (f *FakeClient) ServerInfo(ctx context.Context, _ ...strict.CallOption) (*strict.ServerInfo, error) {
+	f.note("ServerInfo", "")
+	if err := f.wait(ctx, 0); err != nil {
+		return nil, err
+	}
+	return &strict.ServerInfo{
+		Version:        "stricttest",
+		APIVersions:    []string{strict.DefaultAPIVersion},
+		ProcessorTypes: []strict.ProcessorType{strict.Cloud, strict.Local, strict.HybridProc},
+	}, nil
+}
+
+// ListProcessors lists one available processor of each type.
+func (f *FakeClient) ListProcessors(ctx context.Context, _ ...strict.CallOption) ([]strict.ProcessorInfo, error) {
+	f.note("ListProcessors", "")
+	if err := f.wait(ctx, 0); err != nil {
+		return nil, err
+	}
+	var out []strict.ProcessorInfo
+	for _, p := range []strict.ProcessorType{strict.Cloud, strict.Local, strict.HybridProc} {
+		out = append(out, strict.ProcessorInfo{Type: p, Available: true})
+	}
+	return out, nil
+}
+
+// GetUsage counts the processing requests recorded so far, whatever the
+// period.
+func (f *FakeClient) GetUsage(ctx context.Context, period strict.UsagePeriod, _ ...strict.CallOption) (*strict.Usage, error) {
+	f.note("GetUsage", "")
+	if err := f.wait(ctx, 0); err != nil {
+		return nil, err
+	}
+	u := &strict.Usage{Period: period, End: f.now(), ByProcessor: make(map[strict.ProcessorType]strict.ProcessorUsage)}
+	for _, c := range f.Calls() {
+		switch c.Method {
+		case "ProcessRequest", "ProcessRequestWithProgress", "ProcessBatch", "ProcessStream", "ProcessUpload", "SubmitJob":
+		default:
+			continue
+		}
+		u.Requests++
+		u.InputTokens += int64(c.Request.InputTokens)
+		pu := u.ByProcessor[c.Request.ProcessorType]
+		pu.Requests++
+		pu.InputTokens += int64(c.Request.InputTokens)
+		u.ByProcessor[c.Request.ProcessorType] = pu
+	}
+	return u, nil
+}
+
+// webhook records a call and runs fn on the fake's webhooks.
+func (f *FakeClient) webhook(ctx context.Context, method string, fn func(s *webhookStore) (*strict.Webhook, error)) (*strict.Webhook, error) {
+	f.note(method, "")
+	if err := f.wait(ctx, 0); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return fn(&f.hooks)
+}
+
+// CreateWebhook stores a subscription. Nothing is ever delivered to it.
+func (f *FakeClient) CreateWebhook(ctx context.Context, req strict.CreateWebhookRequest, _ ...strict.CallOption) (*strict.Webhook, error) {
+	return f.webhook(ctx, "CreateWebhook", func(s *webhookStore) (*strict.Webhook, error) {
+		return s.create(req, f.now())
+	})
+}
+
+// ListWebhooks lists the stored subscriptions in creation order.
+func (f *FakeClient) ListWebhooks(ctx context.Context, _ ...strict.CallOption) ([]strict.Webhook, error) {
+	var list []strict.Webhook
+	_, err := f.webhook(ctx, "ListWebhooks", func(s *webhookStore) (*strict.Webhook, error) {
+		list = s.list()
+		return nil, nil
+	})
+	return list, err
+}
+
+// GetWebhook returns a stored subscription.
+func (f *FakeClient) GetWebhook(ctx context.Context, id string, _ ...strict.CallOption) (*strict.Webhook, error) {
+	return f.webhook(ctx, "GetWebhook", func(s *webhookStore) (*strict.Webhook, error) {
+		return s.get(id)
+	})
+}
+
+// UpdateWebhook changes a stored subscription.
+func (f *FakeClient) UpdateWebhook(ctx context.Context, id string, req strict.UpdateWebhookRequest, _ ...strict.CallOption) (*strict.Webhook, error) {
+	return f.webhook(ctx, "UpdateWebhook", func(s *webhookStore) (*strict.Webhook, error) {
+		return s.update(id, req, f.now())
+	})
+}
+
+// DeleteWebhook removes a stored subscription.
+func (f *FakeClient) DeleteWebhook(ctx context.Context, id string, _ ...strict.CallOption) error {
+	_, err := f.webhook(ctx, "DeleteWebhook", func(s *webhookStore) (*strict.Webhook, error) {
+		return nil, s.delete(id)
+	})
+	return err
+}
+
+// ReplayEvents schedules a replay of no events: the fake never sends any.
+func (f *FakeClient) ReplayEvents(ctx context.Context, _, _ time.Time, filter strict.ReplayFilter, _ ...strict.CallOption) (*strict.Replay, error) {
+	var r *strict.Replay
+	_, err := f.webhook(ctx, "ReplayEvents", func(s *webhookStore) (*strict.Webhook, error) {
+		var err error
+		r, err = s.replay(filter)
+		return nil, err
+	})
+	return r, err
+}
@@ -0,0 +1,631 @@
+package stricttest
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+// Server is a mock strict API server for integration tests that go
+// through the client's real HTTP path: retries, rate-limit handling,
+// error decoding and job polling all see what a real server would send.
+//
+//	srv := stricttest.NewServer()
+//	defer srv.Close()
+//	srv.InjectFaults(stricttest.Fault{StatusCode: 503})
+//	out, err := srv.Client(strict.WithRetryPolicy(strict.DefaultRetryPolicy())).ProcessRequest(ctx, req)
+//
+// It serves /process/request, /process/batch, /validate/batch, the /jobs
+// endpoints, /health, /ready, /info, /processors, /usage, the /webhooks
+// endpoints and /events/replay, speaking JSON only, and its OpenAPI spec
+// at /openapi.json without requiring the API key. Requests are answered
+// by the process function, which defaults to DefaultOutput. The setters
+// may be called at any time.
+type Server struct {
+	// URL is the server's base URL, to pass to strict.NewClient.
+	URL string
+
+	srv *httptest.Server
+
+	mu        sync.Mutex
+	process   func(strict.ProcessingRequest) (*strict.OutputSchema, error)
+	apiKey    string
+	latency   time.Duration
+	faults    []Fault
+	limit     int
+	window    time.Duration
+	windowEnd time.Time
+	used      int
+	jobTime   time.Duration
+	jobs      map[strict.JobID]*serverJob
+	jobOrder  []strict.JobID
+	hooks     webhookStore
+	requests  []Request
+	processed []strict.ProcessingRequest
+	nextID    int
+}
+
+// Fault is a failure the server sends in place of a normal answer.
+type Fault struct {
+	// StatusCode is the response status; it defaults to 500.
+	StatusCode int
+	// Code and Message fill the error body. Message defaults to the
+	// status text.
+	Code    string
+	Message string
+	// RetryAfter, if set, is sent in the Retry-After header.
+	RetryAfter time.Duration
+}
+
+// Request is a request the server received.
+type Request struct {
+	Method string
+	// Path includes the query string, if any.
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+// openAPISpec describes the endpoints the server speaks, as the SDK's wire
+// types expect them.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+type serverJob struct {
+	status strict.JobStatus
+	output *strict.OutputSchema
+	err    error
+	done   time.Time
+}
+
+// NewServer starts a Server. Close it when done.
+func NewServer() *Server {
+	s := &Server{jobs: make(map[strict.JobID]*serverJob)}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() { s.srv.Close() }
+
+// Client returns a client for the server, with the API key it requires,
+// if any, and opts.
+func (s *Server) Client(opts ...strict.Option) *strict.Client {
+	s.mu.Lock()
+	key := s.apiKey
+	s.mu.Unlock()
+	if key == "" {
+		key = "test-key"
+	}
+	return strict.NewClient(s.URL, key, opts...)
+}
+
+// SetProcess sets the function that answers processing requests. An
+// error that is a *strict.APIError is sent with its status, code,
+// message and details; other errors are sent as 500s. nil restores
+// DefaultOutput.
+func (s *Server) SetProcess(fn func(strict.ProcessingRequest) (*strict.OutputSchema, error)) {
+	s.mu.Lock()
+	s.process = fn
+	s.mu.Unlock()
+}
+
+// RequireAPIKey makes the server answer 401 to requests that don't carry
+// key in X-API-Key or as a bearer token. An empty key accepts anything.
+func (s *Server) RequireAPIKey(key string) {
+	s.mu.Lock()
+	s.apiKey = key
+	s.mu.Unlock()
+}
+
+// SetLatency delays every response by d.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	s.latency = d
+	s.mu.Unlock()
+}
+
+// InjectFaults queues failures: each of the next requests, other than
+// health checks, gets the next fault instead of its answer.
+func (s *Server) InjectFaults(faults ...Fault) {
+	s.mu.Lock()
+	s.faults = append(s.faults, faults...)
+	s.mu.Unlock()
+}
+
+// SetRateLimit allows limit requests, other than health checks, per
+// window, reporting the budget in X-RateLimit-* headers and answering
+// 429 with Retry-After once it is spent. A zero limit turns it off.
+func (s *Server) SetRateLimit(limit int, window time.Duration) {
+	s.mu.Lock()
+	s.limit, s.window = limit, window
+	s.windowEnd, s.used = time.Time{}, 0
+	s.mu.Unlock()
+}
+
+// SetJobDuration makes submitted jobs run for d before finishing. Jobs
+// are processed when submitted, so d only delays when their outcome is
+// reported. It defaults to zero: jobs finish at once.
+func (s *Server) SetJobDuration(d time.Duration) {
+	s.mu.Lock()
+	s.jobTime = d
+	s.mu.Unlock()
+}
+
+// Requests returns the requests received so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Processed returns the processing requests answered so far, including
+// batch items and jobs, in order.
+func (s *Server) Processed() []strict.ProcessingRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]strict.ProcessingRequest(nil), s.processed...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	requestID := r.Header.Get("X-Request-ID")
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.RequestURI(), Header: r.Header.Clone(), Body: body})
+	if requestID == "" {
+		s.nextID++
+		requestID = "mock-" + strconv.Itoa(s.nextID)
+	}
+	latency := s.latency
+	apiKey := s.apiKey
+	s.mu.Unlock()
+	w.Header().Set("X-Request-ID", requestID)
+
+	if latency > 0 {
+		t := time.NewTimer(latency)
+		select {
+		case <-t.C:
+		case <-r.Context().Done():
+			t.Stop()
+			return
+		}
+	}
+	if r.Method == http.MethodGet && r.URL.Path == "/openapi.json" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openAPISpec)
+		return
+	}
+	if apiKey != "" && r.Header.Get("X-API-Key") != apiKey && r.Header.Get("Authorization") != "Bearer "+apiKey {
+		writeError(w, requestID, &strict.APIError{StatusCode: http.StatusUnauthorized, Code: "unauthorized", Message: "invalid API key"})
+		return
+	}
+	if r.URL.Path == "/health" || r.URL.Path == "/ready" {
+		writeJSON(w, http.StatusOK, strict.HealthStatus{Status: "ok", Version: "stricttest"})
+		return
+	}
+	if s.throttle(w, requestID) || s.fault(w, requestID) {
+		return
+	}
+	s.route(w, r, body, requestID)
+}
+
+// throttle applies the rate limit, reporting whether it answered.
+func (s *Server) throttle(w http.ResponseWriter, requestID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.limit <= 0 {
+		return false
+	}
+	now := time.Now()
+	if !now.Before(s.windowEnd) {
+		s.windowEnd, s.used = now.Add(s.window), 0
+	}
+	reset := int(math.Ceil(s.windowEnd.Sub(now).Seconds()))
+	h := w.Header()
+	h.Set("X-RateLimit-Limit", strconv.Itoa(s.limit))
+	h.Set("X-RateLimit-Reset", strconv.Itoa(reset))
+	if s.used >= s.limit {
+		h.Set("X-RateLimit-Remaining", "0")
+		h.Set("Retry-After", strconv.Itoa(reset))
+		writeError(w, requestID, &strict.APIError{StatusCode: http.StatusTooManyRequests, Code: "rate_limited", Message: "rate limit exceeded"})
+		return true
+	}
+	s.used++
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(s.limit-s.used))
+	return false
+}
+
+// fault sends the next injected fault, reporting whether there was one.
+func (s *Server) fault(w http.ResponseWriter, requestID string) bool {
+	s.mu.Lock()
+	if len(s.faults) == 0 {
+		s.mu.Unlock()
+		return false
+	}
+	f := s.faults[0]
+	s.faults = s.faults[1:]
+	s.mu.Unlock()
+	if f.StatusCode == 0 {
+		f.StatusCode = http.StatusInternalServerError
+	}
+	if f.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(f.RetryAfter.Seconds()))))
+	}
+	writeError(w, requestID, &strict.APIError{StatusCode: f.StatusCode, Code: f.Code, Message: f.Message})
+	return true
+}
+
+func (s *Server) route(w http.ResponseWriter, r *http.Request, body []byte, requestID string) {
+	path := r.URL.Path
+	switch {
+	case r.Method == http.MethodPost && path == "/process/request":
+		var req strict.ProcessingRequest
+		if !decode(w, body, &req, requestID) {
+			return
+		}
+		out, err := s.run(req)
+		if err != nil {
+			writeError(w, requestID, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, out)
+	case r.Method == http.MethodPost && path == "/process/batch":
+		s.batch(w, body, requestID)
+	case r.Method == http.MethodPost && path == "/validate/batch":
+		s.validateBatch(w, body, requestID)
+	case r.Method == http.MethodPost && path == "/jobs":
+		var req strict.ProcessingRequest
+		if !decode(w, body, &req, requestID) {
+			return
+		}
+		writeJSON(w, http.StatusAccepted, s.submit(req))
+	case r.Method == http.MethodGet && path == "/jobs":
+		s.listJobs(w, r)
+	case strings.HasPrefix(path, "/jobs/"):
+		s.job(w, r, strings.TrimPrefix(path, "/jobs/"), requestID)
+	case r.Method == http.MethodGet && path == "/info":
+		writeJSON(w, http.StatusOK, strict.ServerInfo{
+			Version:        "stricttest",
+			APIVersions:    []string{strict.DefaultAPIVersion},
+			ProcessorTypes: []strict.ProcessorType{strict.Cloud, strict.Local, strict.HybridProc},
+		})
+	case r.Method == http.MethodGet && path == "/processors":
+		var list struct {
+			Processors []strict.ProcessorInfo `json:"processors"`
+		}
+		for _, p := range []strict.ProcessorType{strict.Cloud, strict.Local, strict.HybridProc} {
+			list.Processors = append(list.Processors, strict.ProcessorInfo{Type: p, Available: true})
+		}
+		writeJSON(w, http.StatusOK, list)
+	case r.Method == http.MethodGet && path == "/usage":
+		writeJSON(w, http.StatusOK, s.usage(strict.UsagePeriod(r.URL.Query().Get("period"))))
+	case r.Method == http.MethodPost && path == "/events/replay":
+		var req struct {
+			WebhookID string                    `json:"webhook_id"`
+			Events    []strict.WebhookEventType `json:"events"`
+		}
+		if !decode(w, body, &req, requestID) {
+			return
+		}
+		s.mu.Lock()
+		replay, err := s.hooks.replay(strict.ReplayFilter{WebhookID: req.WebhookID, Events: req.Events})
+		s.mu.Unlock()
+		if err != nil {
+			writeError(w, requestID, err)
+			return
+		}
+		writeJSON(w, http.StatusAccepted, replay)
+	case path == "/webhooks" || strings.HasPrefix(path, "/webhooks/"):
+		s.webhooks(w, r, strings.TrimPrefix(strings.TrimPrefix(path, "/webhooks"), "/"), body, requestID)
+	default:
+		writeError(w, requestID, &strict.APIError{StatusCode: http.StatusNotFound, Code: "not_found", Message: "no such endpoint: " + r.Method + " " + path})
+	}
+}
+
+// webhooks manages webhook subscriptions. Nothing is ever delivered to
+// them.
+func (s *Server) webhooks(w http.ResponseWriter, r *http.Request, id string, body []byte, requestID string) {
+	var (
+		wh     *strict.Webhook
+		err    error
+		status = http.StatusOK
+	)
+	switch {
+	case r.Method == http.MethodPost && id == "":
+		var req strict.CreateWebhookRequest
+		if !decode(w, body, &req, requestID) {
+			return
+		}
+		s.mu.Lock()
+		wh, err = s.hooks.create(req, time.Now())
+		s.mu.Unlock()
+		status = http.StatusCreated
+	case r.Method == http.MethodGet && id == "":
+		s.mu.Lock()
+		list := s.hooks.list()
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string][]strict.Webhook{"webhooks": list})
+		return
+	case r.Method == http.MethodGet:
+		s.mu.Lock()
+		wh, err = s.hooks.get(id)
+		s.mu.Unlock()
+	case r.Method == http.MethodPatch:
+		var req strict.UpdateWebhookRequest
+		if !decode(w, body, &req, requestID) {
+			return
+		}
+		s.mu.Lock()
+		wh, err = s.hooks.update(id, req, time.Now())
+		s.mu.Unlock()
+	case r.Method == http.MethodDelete:
+		s.mu.Lock()
+		err = s.hooks.delete(id)
+		s.mu.Unlock()
+		status = http.StatusNoContent
+	default:
+		err = &strict.APIError{StatusCode: http.StatusNotFound, Code: "not_found", Message: "no such endpoint: " + r.Method + " " + r.URL.Path}
+	}
+	switch {
+	case err != nil:
+		writeError(w, requestID, err)
+	case wh == nil:
+		w.WriteHeader(status)
+	default:
+		writeJSON(w, status, wh)
+	}
+}
+
+// run answers one processing request.
+func (s *Server) run(req strict.ProcessingRequest) (*strict.OutputSchema, error) {
+	s.mu.Lock()
+	s.processed = append(s.processed, req)
+	fn := s.process
+	s.mu.Unlock()
+	if fn == nil {
+		return DefaultOutput(req), nil
+	}
+	return fn(req)
+}
+
+func (s *Server) batch(w http.ResponseWriter, body []byte, requestID string) {
+	var in struct {
+		Requests []strict.ProcessingRequest `json:"requests"`
+	}
+	if !decode(w, body, &in, requestID) {
+		return
+	}
+	type item struct {
+		StatusCode int                  `json:"status_code"`
+		Output     *strict.OutputSchema `json:"output,omitempty"`
+		Error      interface{}          `json:"error,omitempty"`
+	}
+	var out struct {
+		Results []item `json:"results"`
+	}
+	for _, req := range in.Requests {
+		res, err := s.run(req)
+		if err != nil {
+			apiErr := asAPIError(err)
+			out.Results = append(out.Results, item{StatusCode: apiErr.StatusCode, Error: errorBody(apiErr, requestID)["error"]})
+			continue
+		}
+		out.Results = append(out.Results, item{StatusCode: http.StatusOK, Output: res})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) validateBatch(w http.ResponseWriter, body []byte, requestID string) {
+	var in struct {
+		Requests []strict.ProcessingRequest `json:"requests"`
+	}
+	if !decode(w, body, &in, requestID) {
+		return
+	}
+	var out struct {
+		Results []strict.ValidationResult `json:"results"`
+	}
+	for _, req := range in.Requests {
+		res := DefaultOutput(req).Validation
+		if strings.TrimSpace(req.InputData) == "" {
+			res = strict.ValidationResult{Status: "invalid", Errors: []string{"input_data is empty"}}
+		}
+		out.Results = append(out.Results, res)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// submit runs req as a job, which reports its outcome once the job
+// duration has passed.
+func (s *Server) submit(req strict.ProcessingRequest) strict.JobStatus {
+	out, err := s.run(req)
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	id := strict.JobID("job-" + strconv.Itoa(s.nextID))
+	job := &serverJob{
+		status: strict.JobStatus{ID: id, State: strict.JobQueued, ProcessorType: req.ProcessorType, CreatedAt: now, UpdatedAt: now},
+		output: out,
+		err:    err,
+		done:   now.Add(s.jobTime),
+	}
+	s.jobs[id] = job
+	s.jobOrder = append(s.jobOrder, id)
+	return s.statusLocked(job, now)
+}
+
+// statusLocked brings job's state up to date as of now.
+func (s *Server) statusLocked(job *serverJob, now time.Time) strict.JobStatus {
+	if job.status.State.Done() {
+		return job.status
+	}
+	switch {
+	case !now.Before(job.done) && job.err != nil:
+		job.status.State = strict.JobFailed
+		job.status.Error = job.err.Error()
+		job.status.UpdatedAt = job.done
+	case !now.Before(job.done):
+		job.status.State = strict.JobSucceeded
+		job.status.UpdatedAt = job.done
+	case now.After(job.status.CreatedAt):
+		job.status.State = strict.JobRunning
+	}
+	return job.status
+}
+
+func (s *Server) job(w http.ResponseWriter, r *http.Request, rest, requestID string) {
+	id, action, _ := strings.Cut(rest, "/")
+	s.mu.Lock()
+	job, ok := s.jobs[strict.JobID(id)]
+	if !ok {
+		s.mu.Unlock()
+		writeError(w, requestID, &strict.APIError{StatusCode: http.StatusNotFound, Code: "job_not_found", Message: "job " + id + " not found"})
+		return
+	}
+	now := time.Now()
+	st := s.statusLocked(job, now)
+	switch {
+	case r.Method == http.MethodGet && action == "":
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, st)
+	case r.Method == http.MethodGet && action == "result":
+		s.mu.Unlock()
+		switch st.State {
+		case strict.JobSucceeded:
+			writeJSON(w, http.StatusOK, job.output)
+		case strict.JobFailed:
+			writeError(w, requestID, job.err)
+		default:
+			writeError(w, requestID, &strict.APIError{StatusCode: http.StatusConflict, Code: "job_not_finished", Message: "job is " + string(st.State)})
+		}
+	case r.Method == http.MethodPost && action == "cancel":
+		if st.State.Done() {
+			s.mu.Unlock()
+			writeError(w, requestID, &strict.APIError{StatusCode: http.StatusConflict, Code: "job_finished", Message: "job is already " + string(st.State)})
+			return
+		}
+		job.status.State = strict.JobCancelled
+		job.status.UpdatedAt = now
+		st = job.status
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, st)
+	default:
+		s.mu.Unlock()
+		writeError(w, requestID, &strict.APIError{StatusCode: http.StatusNotFound, Code: "not_found", Message: "no such endpoint: " + r.Method + " " + r.URL.Path})
+	}
+}
+
+func (s *Server) listJobs(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	states := q["status"]
+	start, _ := strconv.Atoi(q.Get("cursor"))
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 {
+		limit = 50
+	}
+	now := time.Now()
+	var page struct {
+		Jobs       []strict.JobStatus `json:"jobs"`
+		NextCursor string             `json:"next_cursor,omitempty"`
+	}
+	s.mu.Lock()
+	for i := start; i < len(s.jobOrder); i++ {
+		st := s.statusLocked(s.jobs[s.jobOrder[i]], now)
+		if len(states) > 0 && !contains(states, string(st.State)) {
+			continue
+		}
+		if p := q.Get("processor_type"); p != "" && string(st.ProcessorType) != p {
+			continue
+		}
+		if len(page.Jobs) == limit {
+			page.NextCursor = strconv.Itoa(i)
+			break
+		}
+		page.Jobs = append(page.Jobs, st)
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) usage(period strict.UsagePeriod) *strict.Usage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := &strict.Usage{Period: period, End: time.Now(), Quota: int64(s.limit), ByProcessor: make(map[strict.ProcessorType]strict.ProcessorUsage)}
+	for _, req := range s.processed {
+		u.Requests++
+		u.InputTokens += int64(req.InputTokens)
+		pu := u.ByProcessor[req.ProcessorType]
+		pu.Requests++
+		pu.InputTokens += int64(req.InputTokens)
+		u.ByProcessor[req.ProcessorType] = pu
+	}
+	return u
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// decode parses a JSON request body, answering 422 if it can't.
+func decode(w http.ResponseWriter, body []byte, v interface{}, requestID string) bool {
+	if err := json.Unmarshal(body, v); err != nil {
+		writeError(w, requestID, &strict.APIError{StatusCode: http.StatusUnprocessableEntity, Code: "invalid_json", Message: err.Error()})
+		return false
+	}
+	return true
+}
+
+func asAPIError(err error) *strict.APIError {
+	var apiErr *strict.APIError
+	if errors.As(err, &apiErr) {
+		e := *apiErr
+		if e.StatusCode == 0 {
+			e.StatusCode = http.StatusInternalServerError
+		}
+		return &e
+	}
+	return &strict.APIError{StatusCode: http.StatusInternalServerError, Code: "internal_error", Message: err.Error()}
+}
+
+func errorBody(e *strict.APIError, requestID string) map[string]interface{} {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	inner := map[string]interface{}{"code": e.Code, "message": msg, "request_id": requestID}
+	if len(e.Details) > 0 {
+		inner["details"] = e.Details
+	}
+	return map[string]interface{}{"error": inner}
+}
+
+// writeError sends err as the API's error body.
+func writeError(w http.ResponseWriter, requestID string, err error) {
+	e := asAPIError(err)
+	writeJSON(w, e.StatusCode, errorBody(e, requestID))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,276 @@
+package stricttest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"unicode/utf8"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+// VCRMode says whether a VCR records or replays.
+type VCRMode int
+
+const (
+	// VCRAuto replays the fixture if it exists and records it otherwise.
+	VCRAuto VCRMode = iota
+	// VCRReplay replays the fixture, failing if it doesn't exist. Use it
+	// in CI, where there is no server to record from.
+	VCRReplay
+	// VCRRecord sends requests to the server and records them, replacing
+	// the fixture on Save.
+	VCRRecord
+)
+
+// scrubbedHeaders carry credentials and are never written to fixtures.
+var scrubbedHeaders = []string{
+	"Authorization", "Proxy-Authorization", "X-API-Key", "Cookie", "Set-Cookie",
+	"X-Signature", "X-Signature-Nonce",
+}
+
+// scrubbed replaces credentials in fixtures.
+const scrubbed = "REDACTED"
+
+// VCR records the client's HTTP exchanges with a live server to a
+// fixture file and replays them later without network access:
+//
+//	vcr, err := stricttest.NewVCR("testdata/process.json", stricttest.VCRAuto)
+//	...
+//	defer vcr.Save()
+//	c := strict.NewClient(url, os.Getenv("STRICT_API_KEY"), strict.WithTransportWrapper(vcr.Wrap))
+//
+// Credentials in headers are replaced with "REDACTED" before anything is
+// written, and Redactor, if set, scrubs bodies too. A replayed request is
+// matched to the first unused recorded interaction with the same method,
+// path, query and body, so the same request may be replayed as often as
+// it was recorded. Requests that change on every run, such as those
+// using WithEnvelopeEncryption, can't be replayed.
+type VCR struct {
+	// Redactor scrubs request and response bodies before they are
+	// recorded, and live request bodies before they are matched.
+	Redactor strict.Redactor
+
+	path      string
+	recording bool
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// Interaction is one recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request as stored in a fixture. URL holds only the
+// path and query, so fixtures don't depend on the server's address.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   fixtureBody `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response as stored in a fixture.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       fixtureBody `json:"body,omitempty"`
+}
+
+type fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// NewVCR returns a VCR for the fixture at path in mode.
+func NewVCR(path string, mode VCRMode) (*VCR, error) {
+	v := &VCR{path: path}
+	if mode == VCRRecord {
+		v.recording = true
+		return v, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && mode == VCRAuto {
+		v.recording = true
+		return v, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("stricttest: read fixture: %w", err)
+	}
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("stricttest: parse fixture %s: %w", path, err)
+	}
+	v.interactions = f.Interactions
+	v.used = make([]bool, len(f.Interactions))
+	return v, nil
+}
+
+// Recording reports whether the VCR is recording rather than replaying.
+func (v *VCR) Recording() bool { return v.recording }
+
+// Wrap returns a transport that records exchanges sent through next or
+// replays them without calling next. It is a strict.TransportWrapper.
+func (v *VCR) Wrap(next http.RoundTripper) http.RoundTripper {
+	return vcrTransport{v: v, next: next}
+}
+
+// Save writes the recorded interactions to the fixture, creating its
+// directory if needed. It does nothing when replaying.
+func (v *VCR) Save() error {
+	if !v.recording {
+		return nil
+	}
+	v.mu.Lock()
+	data, err := json.MarshalIndent(fixture{Interactions: v.interactions}, "", "  ")
+	v.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("stricttest: encode fixture: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(v.path), 0o755); err != nil {
+		return fmt.Errorf("stricttest: save fixture: %w", err)
+	}
+	if err := os.WriteFile(v.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("stricttest: save fixture: %w", err)
+	}
+	return nil
+}
+
+type vcrTransport struct {
+	v    *VCR
+	next http.RoundTripper
+}
+
+func (t vcrTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	rec := RecordedRequest{
+		Method: req.Method,
+		URL:    req.URL.RequestURI(),
+		Header: scrub(req.Header),
+		Body:   fixtureBody(t.v.redact(body)),
+	}
+	if !t.v.recording {
+		return t.v.replay(req, rec)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	t.v.mu.Lock()
+	t.v.interactions = append(t.v.interactions, Interaction{
+		Request: rec,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     scrub(resp.Header),
+			Body:       fixtureBody(t.v.redact(respBody)),
+		},
+	})
+	t.v.mu.Unlock()
+	return resp, nil
+}
+
+// replay answers req with the first unused matching interaction.
+func (v *VCR) replay(req *http.Request, rec RecordedRequest) (*http.Response, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for i, in := range v.interactions {
+		if v.used[i] || in.Request.Method != rec.Method || in.Request.URL != rec.URL || !bytes.Equal(in.Request.Body, rec.Body) {
+			continue
+		}
+		v.used[i] = true
+		header := in.Response.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("stricttest: no unused interaction in %s for %s %s", v.path, rec.Method, rec.URL)
+}
+
+func (v *VCR) redact(body []byte) []byte {
+	if v.Redactor == nil || len(body) == 0 {
+		return body
+	}
+	return v.Redactor.Redact(body)
+}
+
+// scrub copies h with credentials replaced.
+func scrub(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	out := h.Clone()
+	for _, key := range scrubbedHeaders {
+		if _, ok := out[http.CanonicalHeaderKey(key)]; ok {
+			out.Set(key, scrubbed)
+		}
+	}
+	return out
+}
+
+// fixtureBody is stored as a string when it is valid UTF-8, and as
+// {"base64": "..."} otherwise, such as for compressed bodies.
+type fixtureBody []byte
+
+func (b fixtureBody) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(struct {
+		Base64 string `json:"base64"`
+	}{base64.StdEncoding.EncodeToString(b)})
+}
+
+func (b *fixtureBody) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = fixtureBody(s)
+		return nil
+	}
+	var enc struct {
+		Base64 string `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return err
+	}
+	raw, err := base64.StdEncoding.DecodeString(enc.Base64)
+	*b = raw
+	return err
+}
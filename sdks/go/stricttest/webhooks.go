@@ -0,0 +1,127 @@
+package stricttest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+// webhookStore holds webhook subscriptions for FakeClient and Server.
+// Callers hold their own lock.
+type webhookStore struct {
+	hooks   map[string]*strict.Webhook
+	order   []string
+	nextID  int
+	replays int
+}
+
+func webhookNotFound(id string) error {
+	return &strict.APIError{StatusCode: http.StatusNotFound, Code: "webhook_not_found", Message: "webhook " + id + " not found"}
+}
+
+func (s *webhookStore) create(req strict.CreateWebhookRequest, now time.Time) (*strict.Webhook, error) {
+	if req.URL == "" || len(req.Events) == 0 {
+		return nil, &strict.APIError{StatusCode: http.StatusUnprocessableEntity, Code: "invalid_webhook", Message: "url and events are required"}
+	}
+	if req.Secret == "" {
+		var b [16]byte
+		rand.Read(b[:])
+		req.Secret = "whsec_" + hex.EncodeToString(b[:])
+	}
+	if s.hooks == nil {
+		s.hooks = make(map[string]*strict.Webhook)
+	}
+	s.nextID++
+	wh := &strict.Webhook{
+		ID:          fmt.Sprintf("wh-%d", s.nextID),
+		URL:         req.URL,
+		Events:      append([]strict.WebhookEventType(nil), req.Events...),
+		Secret:      req.Secret,
+		Description: req.Description,
+		Active:      true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	s.hooks[wh.ID] = wh
+	s.order = append(s.order, wh.ID)
+	out := *wh
+	return &out, nil
+}
+
+// list returns the subscriptions in creation order, without secrets.
+func (s *webhookStore) list() []strict.Webhook {
+	out := make([]strict.Webhook, 0, len(s.hooks))
+	for _, id := range s.order {
+		w := *s.hooks[id]
+		w.Secret = ""
+		out = append(out, w)
+	}
+	return out
+}
+
+func (s *webhookStore) get(id string) (*strict.Webhook, error) {
+	wh, ok := s.hooks[id]
+	if !ok {
+		return nil, webhookNotFound(id)
+	}
+	out := *wh
+	out.Secret = ""
+	return &out, nil
+}
+
+func (s *webhookStore) update(id string, req strict.UpdateWebhookRequest, now time.Time) (*strict.Webhook, error) {
+	wh, ok := s.hooks[id]
+	if !ok {
+		return nil, webhookNotFound(id)
+	}
+	if req.URL != nil {
+		wh.URL = *req.URL
+	}
+	if req.Events != nil {
+		wh.Events = append([]strict.WebhookEventType(nil), req.Events...)
+	}
+	if req.Secret != nil {
+		wh.Secret = *req.Secret
+	}
+	if req.Description != nil {
+		wh.Description = *req.Description
+	}
+	if req.Active != nil {
+		wh.Active = *req.Active
+	}
+	wh.UpdatedAt = now
+	out := *wh
+	if req.Secret == nil {
+		out.Secret = ""
+	}
+	return &out, nil
+}
+
+func (s *webhookStore) delete(id string) error {
+	if _, ok := s.hooks[id]; !ok {
+		return webhookNotFound(id)
+	}
+	delete(s.hooks, id)
+	for i, o := range s.order {
+		if o == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// replay schedules a replay of no events, as no events are ever sent.
+func (s *webhookStore) replay(filter strict.ReplayFilter) (*strict.Replay, error) {
+	if filter.WebhookID != "" {
+		if _, ok := s.hooks[filter.WebhookID]; !ok {
+			return nil, webhookNotFound(filter.WebhookID)
+		}
+	}
+	s.replays++
+	return &strict.Replay{ID: fmt.Sprintf("replay-%d", s.replays)}, nil
+}
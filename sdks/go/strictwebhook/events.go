@@ -0,0 +1,163 @@
+package strictwebhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+// JobCompleted is the payload of a strict.EventJobCompleted event.
+type JobCompleted struct {
+	Job strict.JobStatus `json:"job"`
+	// Result is the job's output. It is nil when the output was too large
+	// to deliver; fetch it with Client.GetJobResult.
+	Result *strict.OutputSchema `json:"result,omitempty"`
+}
+
+// JobFailed is the payload of a strict.EventJobFailed event. Job.Error
+// explains the failure.
+type JobFailed struct {
+	Job strict.JobStatus `json:"job"`
+	// Code is the machine-readable error code, if there is one.
+	Code string `json:"code,omitempty"`
+}
+
+// ValidationRejected is the payload of a strict.EventValidationRejected
+// event.
+type ValidationRejected struct {
+	// RequestID identifies the rejected request in server logs.
+	RequestID string              `json:"request_id"`
+	JobID     strict.JobID        `json:"job_id,omitempty"`
+	Message   string              `json:"message"`
+	Details   []strict.FieldError `json:"details,omitempty"`
+}
+
+// QuotaWarning is the payload of a strict.EventQuotaWarning event.
+type QuotaWarning struct {
+	Period strict.UsagePeriod `json:"period"`
+	// Used and Quota count requests in the period.
+	Used    int64     `json:"used"`
+	Quota   int64     `json:"quota"`
+	ResetAt time.Time `json:"reset_at"`
+}
+
+// Fraction returns the part of the quota used, from 0 to 1 or more.
+func (q QuotaWarning) Fraction() float64 {
+	if q.Quota <= 0 {
+		return 0
+	}
+	return float64(q.Used) / float64(q.Quota)
+}
+
+// Decode decodes the event's Data into the payload for its Type: a
+// *JobCompleted, *JobFailed, *ValidationRejected or *QuotaWarning. For
+// other types it returns Data as a json.RawMessage.
+func (e *Event) Decode() (any, error) {
+	var v any
+	switch e.Type {
+	case strict.EventJobCompleted:
+		v = new(JobCompleted)
+	case strict.EventJobFailed:
+		v = new(JobFailed)
+	case strict.EventValidationRejected:
+		v = new(ValidationRejected)
+	case strict.EventQuotaWarning:
+		v = new(QuotaWarning)
+	default:
+		return e.Data, nil
+	}
+	if err := e.decodeInto(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (e *Event) decodeInto(v any) error {
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return fmt.Errorf("strictwebhook: decode %s event %s: %w", e.Type, e.ID, err)
+	}
+	return nil
+}
+
+// Dispatcher passes each event to the handler registered for its type,
+// with its payload decoded. Use its Handle method as a Handler's Handle:
+//
+//	d := strictwebhook.NewDispatcher()
+//	d.OnJobCompleted(func(ctx context.Context, e *strictwebhook.Event, p *strictwebhook.JobCompleted) error {
+//		...
+//	})
+//	h := strictwebhook.NewHandler(secret, d.Handle)
+//
+// Events of types without a handler go to Default, or are acknowledged
+// and dropped if it is nil. A Dispatcher is safe for concurrent use.
+type Dispatcher struct {
+	// Default, if set, handles events of types without a handler.
+	Default func(ctx context.Context, e *Event) error
+
+	mu       sync.RWMutex
+	handlers map[strict.WebhookEventType]func(ctx context.Context, e *Event) error
+}
+
+// NewDispatcher returns a Dispatcher with no handlers.
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{handlers: make(map[strict.WebhookEventType]func(ctx context.Context, e *Event) error)}
+}
+
+// On registers fn for events of type t, replacing any handler for it.
+// Use it for event types without a typed On method.
+func (d *Dispatcher) On(t strict.WebhookEventType, fn func(ctx context.Context, e *Event) error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers[t] = fn
+}
+
+// OnJobCompleted registers fn for strict.EventJobCompleted events.
+func (d *Dispatcher) OnJobCompleted(fn func(ctx context.Context, e *Event, p *JobCompleted) error) {
+	d.On(strict.EventJobCompleted, typed(fn))
+}
+
+// OnJobFailed registers fn for strict.EventJobFailed events.
+func (d *Dispatcher) OnJobFailed(fn func(ctx context.Context, e *Event, p *JobFailed) error) {
+	d.On(strict.EventJobFailed, typed(fn))
+}
+
+// OnValidationRejected registers fn for strict.EventValidationRejected
+// events.
+func (d *Dispatcher) OnValidationRejected(fn func(ctx context.Context, e *Event, p *ValidationRejected) error) {
+	d.On(strict.EventValidationRejected, typed(fn))
+}
+
+// OnQuotaWarning registers fn for strict.EventQuotaWarning events.
+func (d *Dispatcher) OnQuotaWarning(fn func(ctx context.Context, e *Event, p *QuotaWarning) error) {
+	d.On(strict.EventQuotaWarning, typed(fn))
+}
+
+// typed adapts a handler of payload T to an event handler.
+func typed[T any](fn func(ctx context.Context, e *Event, p *T) error) func(ctx context.Context, e *Event) error {
+	return func(ctx context.Context, e *Event) error {
+		p := new(T)
+		if err := e.decodeInto(p); err != nil {
+			return err
+		}
+		return fn(ctx, e, p)
+	}
+}
+
+// Handle passes e to the handler for its type. It returns the handler's
+// error, or the error decoding e's payload.
+func (d *Dispatcher) Handle(ctx context.Context, e *Event) error {
+	d.mu.RLock()
+	fn := d.handlers[e.Type]
+	d.mu.RUnlock()
+	if fn == nil {
+		fn = d.Default
+	}
+	if fn == nil {
+		return nil
+	}
+	return fn(ctx, e)
+}
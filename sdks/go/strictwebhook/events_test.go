@@ -0,0 +1,51 @@
+package strictwebhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestDispatcher(t *testing.T) {
+	d := NewDispatcher()
+	var completed *JobCompleted
+	var quota *QuotaWarning
+	d.OnJobCompleted(func(ctx context.Context, e *Event, p *JobCompleted) error {
+		completed = p
+		return nil
+	})
+	d.OnQuotaWarning(func(ctx context.Context, e *Event, p *QuotaWarning) error {
+		quota = p
+		return nil
+	})
+	ctx := context.Background()
+	err := d.Handle(ctx, &Event{ID: "1", Type: "job.completed", Data: []byte(`{"job":{"job_id":"j1","status":"succeeded"},"result":{"result":{"n":1},"processor_used":"local"}}`)})
+	if err != nil || completed == nil || completed.Job.ID != "j1" || completed.Result.ProcessorUsed != "local" {
+		t.Fatal(err, completed)
+	}
+	if err := d.Handle(ctx, &Event{ID: "2", Type: "quota.warning", Data: []byte(`{"period":"day","used":90,"quota":100}`)}); err != nil || quota.Fraction() != 0.9 {
+		t.Fatal(err, quota)
+	}
+	if err := d.Handle(ctx, &Event{ID: "3", Type: "quota.warning", Data: []byte(`[]`)}); err == nil {
+		t.Fatal("expected decode error")
+	}
+	// Unregistered types are dropped, or go to Default.
+	if err := d.Handle(ctx, &Event{ID: "4", Type: "job.failed", Data: []byte(`{}`)}); err != nil {
+		t.Fatal(err)
+	}
+	var other *Event
+	d.Default = func(ctx context.Context, e *Event) error { other = e; return nil }
+	d.Handle(ctx, &Event{ID: "5", Type: "something.new", Data: []byte(`{}`)})
+	if other == nil || other.ID != "5" {
+		t.Fatal(other)
+	}
+
+	e := &Event{ID: "6", Type: "validation.rejected", Data: []byte(`{"request_id":"r1","message":"bad","details":[{"loc":["body","input_data"],"msg":"required","type":"missing"}]}`)}
+	v, err := e.Decode()
+	if vr, ok := v.(*ValidationRejected); err != nil || !ok || vr.Details[0].Field() != "input_data" {
+		t.Fatal(v, err)
+	}
+	if v, err := (&Event{Type: "something.new", Data: []byte(`{"a":1}`)}).Decode(); err != nil || string(v.(json.RawMessage)) != `{"a":1}` {
+		t.Fatal(v, err)
+	}
+}
@@ -0,0 +1,354 @@
+// Package strictwebhook receives webhook deliveries from the strict API:
+// it checks their signatures, rejects stale and replayed deliveries, and
+// decodes them into Events.
+//
+//	h := strictwebhook.NewHandler(secret, func(ctx context.Context, e *strictwebhook.Event) error {
+//		...
+//	})
+//	http.Handle("/hooks/strict", h)
+//
+// Each delivery is a POST whose body is the JSON Event, with an
+// X-Strict-Signature header of the form
+//
+//	t=TIMESTAMP,v1=SIGNATURE
+//
+// where TIMESTAMP is Unix seconds and SIGNATURE is the hex HMAC-SHA256,
+// keyed with the subscription's secret, of TIMESTAMP "." BODY. While a
+// secret is being rotated the header carries a v1 entry for each secret.
+//
+// A Dispatcher passes events to handlers registered per event type, with
+// their payloads decoded into JobCompleted, JobFailed, ValidationRejected
+// and QuotaWarning.
+package strictwebhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+// SignatureHeader carries a delivery's signature.
+const SignatureHeader = "X-Strict-Signature"
+
+// DefaultTolerance is how far a delivery's timestamp may be from the
+// local clock, either way, before it is rejected.
+const DefaultTolerance = 5 * time.Minute
+
+// defaultMaxBodyBytes bounds the body a Handler reads.
+const defaultMaxBodyBytes = 1 << 20
+
+var (
+	// ErrNoSignature is returned for a delivery without a signature.
+	ErrNoSignature = errors.New("strictwebhook: missing " + SignatureHeader + " header")
+	// ErrBadSignature is returned when no signature matches any secret.
+	ErrBadSignature = errors.New("strictwebhook: signature mismatch")
+	// ErrStale is returned for a delivery whose timestamp is outside the
+	// tolerance.
+	ErrStale = errors.New("strictwebhook: timestamp outside tolerance")
+	// ErrReplay is returned for an event that was already handled.
+	ErrReplay = errors.New("strictwebhook: event already handled")
+	// ErrInProgress is returned for an event another delivery of which
+	// is being handled.
+	ErrInProgress = errors.New("strictwebhook: event is being handled")
+)
+
+// Event is a webhook delivery.
+type Event struct {
+	// ID identifies the event; redeliveries of an event keep its ID.
+	ID        string                  `json:"id"`
+	Type      strict.WebhookEventType `json:"type"`
+	CreatedAt time.Time               `json:"created_at"`
+	// Data is the event's payload, which depends on its Type.
+	Data json.RawMessage `json:"data"`
+}
+
+// Sign returns the signature header value for body, signed with secret
+// at time t, as the server sends it. Use it to test receivers.
+func Sign(secret []byte, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac(secret, ts, body))
+}
+
+func mac(secret []byte, ts string, body []byte) []byte {
+	m := hmac.New(sha256.New, secret)
+	m.Write([]byte(ts))
+	m.Write([]byte("."))
+	m.Write(body)
+	return m.Sum(nil)
+}
+
+// VerifySignature checks that header is a valid signature of body by one
+// of secrets, made within tolerance of now, and returns its timestamp. A
+// tolerance of zero means DefaultTolerance.
+func VerifySignature(header string, body []byte, secrets [][]byte, tolerance time.Duration, now time.Time) (time.Time, error) {
+	if header == "" {
+		return time.Time{}, ErrNoSignature
+	}
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	var (
+		ts   string
+		sigs [][]byte
+	)
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			// Skip malformed entries; another may still match.
+			if sig, err := hex.DecodeString(v); err == nil {
+				sigs = append(sigs, sig)
+			}
+		}
+	}
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: bad timestamp %q", ErrBadSignature, ts)
+	}
+	// Check the signature first, so a forged header can't probe the
+	// clock.
+	ok := false
+	for _, secret := range secrets {
+		want := mac(secret, ts, body)
+		for _, sig := range sigs {
+			ok = ok || hmac.Equal(sig, want)
+		}
+	}
+	if !ok {
+		return time.Time{}, ErrBadSignature
+	}
+	t := time.Unix(sec, 0)
+	if d := now.Sub(t); d > tolerance || d < -tolerance {
+		return t, ErrStale
+	}
+	return t, nil
+}
+
+// ReplayCache records the events a Handler has received, so it can
+// reject replays. Implementations must be safe for concurrent use; use a
+// shared one, backed by a database or cache server, when several
+// processes receive the same webhook.
+type ReplayCache interface {
+	// Claim records id as being handled, for ttl. If id is already
+	// recorded it returns InProgress, or Handled once Finish was called
+	// for it.
+	Claim(id string, ttl time.Duration) ClaimResult
+	// Finish records that id was handled.
+	Finish(id string)
+	// Release forgets id, so a redelivery of an event whose handling
+	// failed is accepted.
+	Release(id string)
+}
+
+// ClaimResult is the outcome of ReplayCache.Claim.
+type ClaimResult int
+
+const (
+	// Claimed means the ID was new and is now recorded.
+	Claimed ClaimResult = iota
+	// InProgress means another delivery of the event is being handled.
+	InProgress
+	// Handled means the event was already handled.
+	Handled
+)
+
+// NewMemoryReplayCache returns a ReplayCache that keeps IDs in memory.
+func NewMemoryReplayCache() ReplayCache {
+	return &memoryReplayCache{ids: make(map[string]replayEntry)}
+}
+
+type memoryReplayCache struct {
+	mu    sync.Mutex
+	ids   map[string]replayEntry
+	swept time.Time
+}
+
+type replayEntry struct {
+	expires time.Time
+	done    bool
+}
+
+func (c *memoryReplayCache) Claim(id string, ttl time.Duration) ClaimResult {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Sweep now and then rather than on every call.
+	if now.Sub(c.swept) > time.Minute {
+		for k, e := range c.ids {
+			if now.After(e.expires) {
+				delete(c.ids, k)
+			}
+		}
+		c.swept = now
+	}
+	if e, ok := c.ids[id]; ok && !now.After(e.expires) {
+		if e.done {
+			return Handled
+		}
+		return InProgress
+	}
+	c.ids[id] = replayEntry{expires: now.Add(ttl)}
+	return Claimed
+}
+
+func (c *memoryReplayCache) Finish(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.ids[id]; ok {
+		e.done = true
+		c.ids[id] = e
+	}
+}
+
+func (c *memoryReplayCache) Release(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.ids, id)
+}
+
+// Handler is an http.Handler that receives webhook deliveries and passes
+// each verified event to Handle. It answers:
+//
+//   - 204 when Handle succeeds, or the event is a replay of one it
+//     already handled, so the server stops redelivering it;
+//   - 400 for a body that isn't an Event;
+//   - 401 for a missing, invalid or stale signature;
+//   - 405 for methods other than POST;
+//   - 409 while another delivery of the event is being handled, so the
+//     server retries it later;
+//   - 413 for a body over MaxBodyBytes;
+//   - 500 when Handle fails, so the server redelivers the event later.
+//
+// Set the fields before serving; NewHandler fills in the usual ones.
+type Handler struct {
+	// Secrets verify signatures. List more than one while rotating the
+	// subscription's secret.
+	Secrets [][]byte
+	// Handle is called with each verified event and the request's
+	// context.
+	Handle func(ctx context.Context, e *Event) error
+	// Tolerance bounds clock skew and delivery delay. Zero means
+	// DefaultTolerance.
+	Tolerance time.Duration
+	// Replays records received events. Nil uses an in-memory cache.
+	Replays ReplayCache
+	// MaxBodyBytes bounds the body. Zero means 1 MiB.
+	MaxBodyBytes int64
+	// OnError, if set, is called with every rejected delivery's error
+	// and every error from Handle, for logging.
+	OnError func(r *http.Request, err error)
+	// Now returns the current time; it defaults to time.Now.
+	Now func() time.Time
+
+	once sync.Once
+}
+
+// NewHandler returns a Handler verifying deliveries with secret and
+// passing events to handle.
+func NewHandler(secret []byte, handle func(ctx context.Context, e *Event) error) *Handler {
+	return &Handler{Secrets: [][]byte{secret}, Handle: handle}
+}
+
+func (h *Handler) init() {
+	if h.Replays == nil {
+		h.Replays = NewMemoryReplayCache()
+	}
+}
+
+func (h *Handler) now() time.Time {
+	if h.Now != nil {
+		return h.Now()
+	}
+	return time.Now()
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.once.Do(h.init)
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	e, status, err := h.verify(r)
+	if errors.Is(err, ErrReplay) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err != nil {
+		h.reject(w, r, status, err)
+		return
+	}
+	if err := h.Handle(r.Context(), e); err != nil {
+		h.Replays.Release(e.ID)
+		h.reject(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	h.Replays.Finish(e.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// verify reads, checks and decodes a delivery, claiming its event ID.
+// On failure it returns the status to answer with.
+func (h *Handler) verify(r *http.Request) (*Event, int, error) {
+	limit := h.MaxBodyBytes
+	if limit <= 0 {
+		limit = defaultMaxBodyBytes
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("strictwebhook: read body: %w", err)
+	}
+	if int64(len(body)) > limit {
+		return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("strictwebhook: body over %d bytes", limit)
+	}
+	tolerance := h.Tolerance
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	now := h.now()
+	ts, err := VerifySignature(r.Header.Get(SignatureHeader), body, h.Secrets, tolerance, now)
+	if err != nil {
+		return nil, http.StatusUnauthorized, err
+	}
+	var e Event
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("strictwebhook: decode event: %w", err)
+	}
+	if e.ID == "" || e.Type == "" {
+		return nil, http.StatusBadRequest, errors.New("strictwebhook: event has no id or type")
+	}
+	// Past the tolerance the timestamp check rejects the delivery, so the
+	// ID needn't be kept any longer.
+	ttl := ts.Add(tolerance).Sub(now)
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+	switch h.Replays.Claim(e.ID, ttl) {
+	case Handled:
+		return nil, 0, ErrReplay
+	case InProgress:
+		return nil, http.StatusConflict, ErrInProgress
+	}
+	return &e, 0, nil
+}
+
+func (h *Handler) reject(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if h.OnError != nil {
+		h.OnError(r, err)
+	}
+	http.Error(w, http.StatusText(status), status)
+}
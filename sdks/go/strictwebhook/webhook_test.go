@@ -0,0 +1,177 @@
+package strictwebhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	testSecret = []byte("whsec_test")
+	testNow    = time.Unix(1_700_000_000, 0)
+	testBody   = []byte(`{"id":"evt_1","type":"job.completed","created_at":"2023-11-14T22:13:20Z","data":{"job_id":"j1"}}`)
+)
+
+func TestVerifySignature(t *testing.T) {
+	other := []byte("whsec_other")
+	tests := []struct {
+		name    string
+		header  string
+		body    []byte
+		secrets [][]byte
+		want    error
+	}{
+		{"valid", Sign(testSecret, testNow, testBody), testBody, [][]byte{testSecret}, nil},
+		{"missing", "", testBody, [][]byte{testSecret}, ErrNoSignature},
+		{"wrong secret", Sign(other, testNow, testBody), testBody, [][]byte{testSecret}, ErrBadSignature},
+		{"tampered body", Sign(testSecret, testNow, testBody), bytes.Replace(testBody, []byte("j1"), []byte("j2"), 1), [][]byte{testSecret}, ErrBadSignature},
+		{"bad timestamp", "t=soon,v1=00", testBody, [][]byte{testSecret}, ErrBadSignature},
+		{"not hex", "t=1700000000,v1=zz", testBody, [][]byte{testSecret}, ErrBadSignature},
+		{"too old", Sign(testSecret, testNow.Add(-6*time.Minute), testBody), testBody, [][]byte{testSecret}, ErrStale},
+		{"too new", Sign(testSecret, testNow.Add(6*time.Minute), testBody), testBody, [][]byte{testSecret}, ErrStale},
+		{"within tolerance", Sign(testSecret, testNow.Add(-4*time.Minute), testBody), testBody, [][]byte{testSecret}, nil},
+		{"rotated secret", Sign(other, testNow, testBody), testBody, [][]byte{testSecret, other}, nil},
+		{"one of several signatures", Sign(testSecret, testNow, testBody) + ",v1=" + strings.Repeat("0", 64), testBody, [][]byte{testSecret}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VerifySignature(tt.header, tt.body, tt.secrets, 0, testNow)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("VerifySignature(%q) = %v, want %v", tt.header, err, tt.want)
+			}
+		})
+	}
+}
+
+// deliver sends body to h signed at ts and returns the response status.
+func deliver(h *Handler, method string, ts time.Time, body []byte) int {
+	req := httptest.NewRequest(method, "/hook", bytes.NewReader(body))
+	if !ts.IsZero() {
+		req.Header.Set(SignatureHeader, Sign(testSecret, ts, body))
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestHandlerRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		ts      time.Time
+		body    []byte
+		maxBody int64
+		want    int
+		wantErr error
+	}{
+		{"method", http.MethodGet, testNow, testBody, 0, http.StatusMethodNotAllowed, nil},
+		{"unsigned", http.MethodPost, time.Time{}, testBody, 0, http.StatusUnauthorized, ErrNoSignature},
+		{"stale", http.MethodPost, testNow.Add(-10 * time.Minute), testBody, 0, http.StatusUnauthorized, ErrStale},
+		{"no id", http.MethodPost, testNow, []byte(`{}`), 0, http.StatusBadRequest, nil},
+		{"not json", http.MethodPost, testNow, []byte(`[`), 0, http.StatusBadRequest, nil},
+		{"too large", http.MethodPost, testNow, testBody, 10, http.StatusRequestEntityTooLarge, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rejected error
+			h := NewHandler(testSecret, func(ctx context.Context, e *Event) error {
+				t.Errorf("Handle called for %s", e.ID)
+				return nil
+			})
+			h.Now = func() time.Time { return testNow }
+			h.MaxBodyBytes = tt.maxBody
+			h.OnError = func(_ *http.Request, err error) { rejected = err }
+			if got := deliver(h, tt.method, tt.ts, tt.body); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+			if tt.wantErr != nil && !errors.Is(rejected, tt.wantErr) {
+				t.Errorf("OnError got %v, want %v", rejected, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHandlerReplays(t *testing.T) {
+	var (
+		handled []*Event
+		fail    = true
+	)
+	h := NewHandler(testSecret, func(ctx context.Context, e *Event) error {
+		if fail {
+			return errors.New("boom")
+		}
+		handled = append(handled, e)
+		return nil
+	})
+	h.Now = func() time.Time { return testNow }
+
+	// A failed event can be redelivered; a handled one is a replay.
+	if got := deliver(h, http.MethodPost, testNow, testBody); got != http.StatusInternalServerError {
+		t.Fatalf("failing delivery: status = %d, want 500", got)
+	}
+	fail = false
+	if got := deliver(h, http.MethodPost, testNow, testBody); got != http.StatusNoContent {
+		t.Fatalf("redelivery: status = %d, want 204", got)
+	}
+	if got := deliver(h, http.MethodPost, testNow.Add(time.Second), testBody); got != http.StatusNoContent {
+		t.Fatalf("replay: status = %d, want 204", got)
+	}
+	if len(handled) != 1 {
+		t.Fatalf("handled %d events, want 1", len(handled))
+	}
+	if e := handled[0]; e.ID != "evt_1" || e.Type != "job.completed" || !strings.Contains(string(e.Data), "j1") {
+		t.Errorf("handled %+v", e)
+	}
+}
+
+func TestHandlerConcurrentDuplicate(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	h := NewHandler(testSecret, func(ctx context.Context, e *Event) error {
+		close(started)
+		<-release
+		return nil
+	})
+	h.Now = func() time.Time { return testNow }
+
+	first := make(chan int)
+	go func() { first <- deliver(h, http.MethodPost, testNow, testBody) }()
+	<-started
+	if got := deliver(h, http.MethodPost, testNow, testBody); got != http.StatusConflict {
+		t.Errorf("duplicate during handling: status = %d, want 409", got)
+	}
+	close(release)
+	if got := <-first; got != http.StatusNoContent {
+		t.Errorf("first delivery: status = %d, want 204", got)
+	}
+	if got := deliver(h, http.MethodPost, testNow, testBody); got != http.StatusNoContent {
+		t.Errorf("duplicate after handling: status = %d, want 204", got)
+	}
+}
+
+func TestMemoryReplayCache(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(c ReplayCache)
+		want  ClaimResult
+	}{
+		{"new", func(c ReplayCache) {}, Claimed},
+		{"in progress", func(c ReplayCache) { c.Claim("a", time.Hour) }, InProgress},
+		{"handled", func(c ReplayCache) { c.Claim("a", time.Hour); c.Finish("a") }, Handled},
+		{"released", func(c ReplayCache) { c.Claim("a", time.Hour); c.Release("a") }, Claimed},
+		{"expired", func(c ReplayCache) { c.Claim("a", -time.Second); c.Finish("a") }, Claimed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewMemoryReplayCache()
+			tt.setup(c)
+			if got := c.Claim("a", time.Hour); got != tt.want {
+				t.Errorf("Claim() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
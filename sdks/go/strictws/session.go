@@ -0,0 +1,418 @@
+// Package strictws runs a WebSocket processing session against the
+// strict server's /ws/stream endpoint, for continuous feeds where one
+// HTTP request per frame is too slow.
+//
+//	sess, err := strictws.Dial(ctx, client, strictws.Config{})
+//	...
+//	go func() {
+//		for res := range sess.Results() {
+//			// res.Seq matches the number Send returned.
+//		}
+//	}()
+//	seq, err := sess.Send(ctx, strict.ProcessingRequest{...})
+//
+// If the connection drops, the session reconnects and resends every
+// frame that has not yet had a result, so frames are processed at least
+// once.
+package strictws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+// ErrClosed is returned by Send after Close.
+var ErrClosed = errors.New("strictws: session closed")
+
+const (
+	defaultPath               = "/ws/stream"
+	defaultReconnects         = 10
+	defaultBackoff            = 500 * time.Millisecond
+	maxBackoff                = 30 * time.Second
+	defaultResultBuffer       = 64
+	dialTimeout               = 30 * time.Second
+	maxMessageSize      int64 = 16 << 20
+)
+
+// Config tunes a session. The zero value is ready to use.
+type Config struct {
+	// Path is the WebSocket endpoint. Empty means "/ws/stream".
+	Path string
+	// MaxReconnects bounds consecutive reconnect attempts after the
+	// connection drops. Zero means 10; negative disables reconnecting.
+	MaxReconnects int
+	// ReconnectBackoff is the delay before the first reconnect attempt,
+	// doubling up to 30s. Zero means 500ms.
+	ReconnectBackoff time.Duration
+	// ResultBuffer is the capacity of the Results channel. Zero means 64.
+	ResultBuffer int
+}
+
+// Result is the server's answer to one frame.
+type Result struct {
+	// Seq is the sequence number Send returned for the frame.
+	Seq uint64
+	// Content is the processed output, joined from the chunks the server
+	// streamed.
+	Content string
+	// Err is set if the frame could not be processed.
+	Err error
+}
+
+// FrameError is a processing error the server reported for one frame.
+type FrameError struct {
+	Message string
+}
+
+func (e *FrameError) Error() string { return "strictws: " + e.Message }
+
+// message is the server's envelope for every reply.
+type message struct {
+	Type    string `json:"type"`
+	Content string `json:"content"`
+}
+
+type frame struct {
+	seq     uint64
+	data    []byte
+	content strings.Builder
+}
+
+// Session is an open processing session. Its methods are safe for
+// concurrent use.
+type Session struct {
+	client *strict.Client
+	cfg    Config
+	url    string
+	header http.Header
+
+	// wmu serializes writes with the pending queue, so frames reach the
+	// server in the order they were queued; results come back in the
+	// same order.
+	wmu     sync.Mutex
+	mu      sync.Mutex
+	conn    *websocket.Conn // nil while reconnecting
+	pending []*frame
+	nextSeq uint64
+	err     error
+	closed  bool
+
+	results chan Result
+	done    chan struct{}
+	exited  chan struct{}
+}
+
+// Dial opens a session using c's base URL, API key and HTTP client.
+// Sessions send input as plaintext, so Dial fails with
+// strict.ErrEncryptionUnsupported on a client with envelope encryption.
+func Dial(ctx context.Context, c *strict.Client, cfg Config) (*Session, error) {
+	if c.Encrypted() {
+		return nil, strict.ErrEncryptionUnsupported
+	}
+	if cfg.Path == "" {
+		cfg.Path = defaultPath
+	}
+	if cfg.MaxReconnects == 0 {
+		cfg.MaxReconnects = defaultReconnects
+	}
+	if cfg.ReconnectBackoff <= 0 {
+		cfg.ReconnectBackoff = defaultBackoff
+	}
+	if cfg.ResultBuffer <= 0 {
+		cfg.ResultBuffer = defaultResultBuffer
+	}
+	u, err := wsURL(c.BaseURL, cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.CheckEndpoint(c.BaseURL); err != nil {
+		return nil, err
+	}
+	header := make(http.Header)
+	header.Set("User-Agent", "strict-go/"+strict.Version+" strictws")
+	header.Set("API-Version", c.APIVersion())
+	if c.Sandbox() {
+		header.Set(strict.SandboxHeader, "true")
+	}
+
+	s := &Session{
+		client:  c,
+		cfg:     cfg,
+		url:     u,
+		header:  header,
+		results: make(chan Result, cfg.ResultBuffer),
+		done:    make(chan struct{}),
+		exited:  make(chan struct{}),
+	}
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.conn = conn
+	go s.run(conn)
+	return s, nil
+}
+
+func wsURL(base, path string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("strictws: invalid base URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	case "ws", "wss":
+	default:
+		return "", fmt.Errorf("strictws: unsupported base URL scheme %q", u.Scheme)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + path
+	return u.String(), nil
+}
+
+func (s *Session) dial(ctx context.Context) (*websocket.Conn, error) {
+	// Set credentials on every dial so reconnects pick up rotated keys
+	// and refreshed tokens.
+	header := s.header.Clone()
+	if err := s.client.SetAuthHeader(ctx, header); err != nil {
+		return nil, err
+	}
+	conn, _, err := websocket.Dial(ctx, s.url, &websocket.DialOptions{
+		HTTPClient: s.client.HTTPClient(),
+		HTTPHeader: header,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("strictws: dial %s: %w", s.url, err)
+	}
+	conn.SetReadLimit(maxMessageSize)
+	return conn, nil
+}
+
+// Send queues req for processing and returns its sequence number. ctx
+// bounds only the write; once Send succeeds the frame gets a Result,
+// unless the session is closed first. A frame whose write fails is
+// resent after the session reconnects.
+func (s *Session) Send(ctx context.Context, req strict.ProcessingRequest) (uint64, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return 0, fmt.Errorf("strictws: encode request: %w", err)
+	}
+
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return 0, ErrClosed
+	}
+	if s.err != nil {
+		s.mu.Unlock()
+		return 0, s.err
+	}
+	s.nextSeq++
+	f := &frame{seq: s.nextSeq, data: data}
+	s.pending = append(s.pending, f)
+	conn := s.conn
+	s.mu.Unlock()
+
+	if conn != nil {
+		// A failed write breaks the connection; the reader notices and
+		// reconnects, resending this frame with the others.
+		conn.Write(ctx, websocket.MessageText, data)
+	}
+	return f.seq, nil
+}
+
+// Results delivers one Result per frame, in the order frames were sent.
+// It is closed when the session ends.
+func (s *Session) Results() <-chan Result {
+	return s.results
+}
+
+// Err returns the error that ended the session, if reconnecting failed.
+func (s *Session) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close ends the session. Frames still pending get no Result.
+func (s *Session) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	conn := s.conn
+	s.mu.Unlock()
+
+	close(s.done)
+	var err error
+	if conn != nil {
+		err = conn.Close(websocket.StatusNormalClosure, "")
+	}
+	<-s.exited
+	return err
+}
+
+func (s *Session) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// run reads replies until the session is closed or can't reconnect.
+func (s *Session) run(conn *websocket.Conn) {
+	defer close(s.exited)
+	defer close(s.results)
+	for {
+		err := s.read(conn)
+		if s.isClosed() {
+			return
+		}
+		conn.CloseNow()
+		if conn, err = s.reconnect(err); err != nil {
+			s.fail(err)
+			return
+		}
+	}
+}
+
+// read handles replies on conn until it fails.
+func (s *Session) read(conn *websocket.Conn) error {
+	for {
+		_, data, err := conn.Read(context.Background())
+		if err != nil {
+			return err
+		}
+		var m message
+		if err := json.Unmarshal(data, &m); err != nil {
+			continue
+		}
+
+		s.mu.Lock()
+		if len(s.pending) == 0 {
+			s.mu.Unlock()
+			continue
+		}
+		f := s.pending[0]
+		var res *Result
+		switch m.Type {
+		case "chunk":
+			f.content.WriteString(m.Content)
+		case "done":
+			res = &Result{Seq: f.seq, Content: f.content.String()}
+		case "error":
+			res = &Result{Seq: f.seq, Err: &FrameError{Message: m.Content}}
+		}
+		if res != nil {
+			s.pending = s.pending[1:]
+		}
+		s.mu.Unlock()
+
+		if res != nil && !s.deliver(*res) {
+			return ErrClosed
+		}
+	}
+}
+
+func (s *Session) deliver(r Result) bool {
+	select {
+	case s.results <- r:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// reconnect dials again with backoff and resends pending frames.
+func (s *Session) reconnect(cause error) (*websocket.Conn, error) {
+	s.mu.Lock()
+	s.conn = nil
+	s.mu.Unlock()
+	if s.cfg.MaxReconnects < 0 {
+		return nil, fmt.Errorf("strictws: connection lost: %w", cause)
+	}
+
+	delay := s.cfg.ReconnectBackoff
+	for attempt := 0; attempt < s.cfg.MaxReconnects; attempt++ {
+		select {
+		case <-time.After(delay):
+		case <-s.done:
+			return nil, ErrClosed
+		}
+		delay = min(delay*2, maxBackoff)
+
+		ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+		conn, err := s.dial(ctx)
+		cancel()
+		if err != nil {
+			cause = err
+			continue
+		}
+		if err := s.resume(conn); err != nil {
+			conn.CloseNow()
+			cause = err
+			continue
+		}
+		return conn, nil
+	}
+	return nil, fmt.Errorf("strictws: reconnect failed after %d attempts: %w", s.cfg.MaxReconnects, cause)
+}
+
+// resume resends pending frames on conn and makes it the session's
+// connection. Partial output from the old connection is discarded, since
+// the server starts each resent frame over.
+func (s *Session) resume(conn *websocket.Conn) error {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+	s.mu.Lock()
+	pending := append([]*frame(nil), s.pending...)
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+	for _, f := range pending {
+		if err := conn.Write(ctx, websocket.MessageText, f.data); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	for _, f := range s.pending {
+		f.content.Reset()
+	}
+	s.conn = conn
+	return nil
+}
+
+// fail ends the session, failing every pending frame with err.
+func (s *Session) fail(err error) {
+	s.mu.Lock()
+	s.err = err
+	pending := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+	for _, f := range pending {
+		if !s.deliver(Result{Seq: f.seq, Err: err}) {
+			return
+		}
+	}
+}
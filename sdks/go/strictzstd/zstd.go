@@ -0,0 +1,53 @@
+// Package strictzstd adds zstd request compression and response
+// decompression to the strict Go client.
+//
+//	c := strict.NewClient(url, key,
+//		strict.WithRequestCompression(strictzstd.Compressor(), 0),
+//		strict.WithResponseDecompression(strictzstd.Decompressor()))
+package strictzstd
+
+import (
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+
+	strict "github.com/mohitmishra786/strict/sdks/go"
+)
+
+// Compressor returns a strict.Compressor producing zstd at the default
+// level. The returned value is safe for concurrent use.
+func Compressor() strict.Compressor {
+	enc, err := zstd.NewWriter(nil)
+	if err != nil {
+		// NewWriter only fails on invalid options, and none are given.
+		panic(err)
+	}
+	return compressor{enc: enc}
+}
+
+type compressor struct {
+	enc *zstd.Encoder
+}
+
+func (compressor) Encoding() string { return "zstd" }
+
+func (c compressor) Compress(p []byte) ([]byte, error) {
+	return c.enc.EncodeAll(p, make([]byte, 0, len(p)/2)), nil
+}
+
+// Decompressor returns a strict.Decompressor for zstd-encoded responses.
+func Decompressor() strict.Decompressor {
+	return decompressor{}
+}
+
+type decompressor struct{}
+
+func (decompressor) Encoding() string { return "zstd" }
+
+func (decompressor) Decompress(r io.Reader) (io.ReadCloser, error) {
+	dec, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return dec.IOReadCloser(), nil
+}
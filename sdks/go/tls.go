@@ -0,0 +1,92 @@
+package strict
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// These options configure the SDK's default transport; they have no effect
+// when a custom transport or HTTP client is supplied.
+
+// WithTLSConfig replaces the transport's TLS configuration. The config is
+// cloned, so later TLS options don't modify the caller's copy.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Client) {
+		if cfg != nil {
+			c.httpTransport.TLSClientConfig = cfg.Clone()
+		}
+	}
+}
+
+// WithClientCertificate presents the PEM-encoded certificate and key in
+// certFile and keyFile for mutual TLS.
+func WithClientCertificate(certFile, keyFile string) Option {
+	return func(c *Client) {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			c.setConfigErr(fmt.Errorf("strict: load client certificate: %w", err))
+			return
+		}
+		cfg := c.tlsConfig()
+		cfg.Certificates = append(cfg.Certificates, cert)
+	}
+}
+
+// WithMinTLSVersion refuses connections below version, e.g. tls.VersionTLS13.
+func WithMinTLSVersion(version uint16) Option {
+	return func(c *Client) {
+		c.tlsConfig().MinVersion = version
+	}
+}
+
+// WithCipherSuites restricts the TLS 1.0–1.2 cipher suites offered to the
+// server. TLS 1.3 suites are not configurable in Go.
+func WithCipherSuites(suites ...uint16) Option {
+	return func(c *Client) {
+		c.tlsConfig().CipherSuites = suites
+	}
+}
+
+// WithCACertFile trusts the PEM-encoded CA certificates in path in
+// addition to the system roots, for gateways signed by a private CA.
+func WithCACertFile(path string) Option {
+	return func(c *Client) {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			c.setConfigErr(fmt.Errorf("strict: read CA certificates: %w", err))
+			return
+		}
+		cfg := c.tlsConfig()
+		pool := cfg.RootCAs
+		if pool == nil {
+			if pool, err = x509.SystemCertPool(); err != nil {
+				pool = x509.NewCertPool()
+			}
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			c.setConfigErr(errors.New("strict: no CA certificates found in " + path))
+			return
+		}
+		cfg.RootCAs = pool
+	}
+}
+
+// WithCACertPool trusts exactly the CAs in pool, replacing the system
+// roots.
+func WithCACertPool(pool *x509.CertPool) Option {
+	return func(c *Client) {
+		c.tlsConfig().RootCAs = pool
+	}
+}
+
+// tlsConfig returns the default transport's TLS config, creating it if
+// needed.
+func (c *Client) tlsConfig() *tls.Config {
+	if c.httpTransport.TLSClientConfig == nil {
+		c.httpTransport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return c.httpTransport.TLSClientConfig
+}
@@ -0,0 +1,187 @@
+package strict
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// tokenExpiryDelta is how long before expiry a token stops being
+	// sent, so one doesn't lapse in flight.
+	tokenExpiryDelta = 30 * time.Second
+	// tokenRefreshWindow is how long before expiry a replacement is
+	// fetched in the background.
+	tokenRefreshWindow = 2 * time.Minute
+)
+
+// Token is a bearer token and when it expires.
+type Token struct {
+	AccessToken string
+	// Expiry is when the token lapses. If zero and the token is a JWT,
+	// its exp claim is used; otherwise the token is treated as never
+	// expiring.
+	Expiry time.Time
+}
+
+// TokenSource supplies bearer tokens. The client caches tokens and only
+// calls Token when the current one is about to expire or was rejected.
+type TokenSource interface {
+	Token(ctx context.Context) (Token, error)
+}
+
+// TokenSourceFunc adapts a function to a TokenSource.
+type TokenSourceFunc func(ctx context.Context) (Token, error)
+
+// Token calls f.
+func (f TokenSourceFunc) Token(ctx context.Context) (Token, error) { return f(ctx) }
+
+// WithTokenSource authenticates with bearer tokens from ts, sent as
+// Authorization instead of X-API-Key. A token is refreshed in the
+// background shortly before it expires, so calls rarely wait on ts, and
+// replaced after the server rejects it with 401. It takes precedence
+// over the API key and WithCredentials.
+func WithTokenSource(ts TokenSource) Option {
+	return func(c *Client) {
+		if ts == nil {
+			c.setConfigErr(errors.New("strict: WithTokenSource: nil source"))
+			return
+		}
+		c.tokens = &tokenCache{src: ts}
+	}
+}
+
+// tokenCache caches the token from src and refreshes it ahead of expiry.
+type tokenCache struct {
+	src TokenSource
+
+	mu         sync.Mutex
+	tok        Token
+	fetched    time.Time
+	refreshing bool
+}
+
+// token returns a usable token, fetching one if there is none or the
+// current one is about to lapse, and starting a background refresh if
+// it will lapse soon.
+func (tc *tokenCache) token(ctx context.Context) (string, error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	now := time.Now()
+	if tc.tok.AccessToken != "" {
+		if tc.tok.Expiry.IsZero() {
+			return tc.tok.AccessToken, nil
+		}
+		refreshAt, staleAt := tc.deadlines()
+		if now.Before(staleAt) {
+			if !tc.refreshing && now.After(refreshAt) {
+				tc.refreshing = true
+				go tc.refresh(context.WithoutCancel(ctx))
+			}
+			return tc.tok.AccessToken, nil
+		}
+	}
+	tok, err := tc.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	tc.tok = tok
+	tc.fetched = now
+	return tok.AccessToken, nil
+}
+
+// deadlines returns when the cached token should be refreshed in the
+// background and when it should no longer be sent. Both margins shrink
+// for tokens that live only a few minutes.
+func (tc *tokenCache) deadlines() (refreshAt, staleAt time.Time) {
+	life := tc.tok.Expiry.Sub(tc.fetched)
+	return tc.tok.Expiry.Add(-min(tokenRefreshWindow, life/4)),
+		tc.tok.Expiry.Add(-min(tokenExpiryDelta, life/10))
+}
+
+func (tc *tokenCache) refresh(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, tokenRefreshWindow)
+	defer cancel()
+	fetched := time.Now()
+	tok, err := tc.fetch(ctx)
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.refreshing = false
+	// On failure the next call past the stale deadline fetches in the
+	// foreground and reports the error.
+	if err == nil {
+		tc.tok, tc.fetched = tok, fetched
+	}
+}
+
+func (tc *tokenCache) fetch(ctx context.Context) (Token, error) {
+	tok, err := tc.src.Token(ctx)
+	if err != nil {
+		return Token{}, err
+	}
+	if tok.AccessToken == "" {
+		return Token{}, errors.New("strict: token source returned an empty token")
+	}
+	if tok.Expiry.IsZero() {
+		tok.Expiry = jwtExpiry(tok.AccessToken)
+	}
+	return tok, nil
+}
+
+// invalidate drops token if it is still the cached one, so the next call
+// fetches a new token.
+func (tc *tokenCache) invalidate(token string) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.tok.AccessToken == token {
+		tc.tok = Token{}
+	}
+}
+
+// jwtExpiry returns the exp claim of token if it is a JWT, or the zero
+// time. The signature is not checked; the token is only being sent on.
+func jwtExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp float64 `json:"exp"`
+	}
+	if json.Unmarshal(payload, &claims) != nil || claims.Exp <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(claims.Exp), 0)
+}
+
+// SetAuthHeader sets the request's credentials on h: a bearer token in
+// Authorization when a token source is configured, otherwise the API key
+// in X-API-Key. It is for packages that open their own connections to
+// the server.
+func (c *Client) SetAuthHeader(ctx context.Context, h http.Header) error {
+	if c.tokens != nil {
+		token, err := c.tokens.token(ctx)
+		if err != nil {
+			return err
+		}
+		h.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+	apiKey, err := c.ResolveAPIKey(ctx)
+	if err != nil {
+		return err
+	}
+	if apiKey != "" {
+		h.Set("X-API-Key", apiKey)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package strict
+
+import "errors"
+
+// Tokenizer counts the tokens in an input. The client uses it to fill in
+// ProcessingRequest.InputTokens when the caller leaves it zero.
+type Tokenizer interface {
+	CountTokens(text string) int
+}
+
+// TokenizerFunc adapts a function to a Tokenizer.
+type TokenizerFunc func(text string) int
+
+// CountTokens calls f(text).
+func (f TokenizerFunc) CountTokens(text string) int { return f(text) }
+
+// WordTokenizer returns the default Tokenizer, which counts
+// whitespace-separated words. It is a rough estimate, the same one
+// ProcessStream and ProcessUpload compute as they read input; use a
+// model's own tokenizer where the count matters.
+func WordTokenizer() Tokenizer {
+	return wordTokenizer{}
+}
+
+type wordTokenizer struct{}
+
+func (wordTokenizer) CountTokens(text string) int {
+	var w wordCounter
+	w.count([]byte(text))
+	return w.n
+}
+
+// WithTokenizer replaces the default Tokenizer. A nil Tokenizer turns off
+// automatic counting, leaving InputTokens as the caller set it.
+func WithTokenizer(t Tokenizer) Option {
+	return func(c *Client) {
+		c.tokenizer = t
+	}
+}
+
+// WithProcessorTokenizer counts tokens with t for requests sent to
+// processor type p, for processors whose models tokenize differently.
+// Requests with no processor type use the default Tokenizer.
+func WithProcessorTokenizer(p ProcessorType, t Tokenizer) Option {
+	return func(c *Client) {
+		if t == nil {
+			c.setConfigErr(errors.New("strict: WithProcessorTokenizer: nil tokenizer"))
+			return
+		}
+		if c.tokenizers == nil {
+			c.tokenizers = make(map[ProcessorType]Tokenizer)
+		}
+		c.tokenizers[p] = t
+	}
+}
+
+// countTokens fills in req.InputTokens if it is zero.
+func (c *Client) countTokens(req *ProcessingRequest) {
+	if req.InputTokens != 0 || req.InputData == "" {
+		return
+	}
+	if t := c.tokenizerFor(req.ProcessorType); t != nil {
+		req.InputTokens = t.CountTokens(req.InputData)
+	}
+}
+
+// tokenizerFor returns the Tokenizer for requests to processor type p, or
+// nil if counting is turned off.
+func (c *Client) tokenizerFor(p ProcessorType) Tokenizer {
+	if t, ok := c.tokenizers[p]; ok {
+		return t
+	}
+	return c.tokenizer
+}
@@ -0,0 +1,40 @@
+package strict
+
+import "context"
+
+// CallInfo describes a logical API call, which may span several HTTP
+// attempts.
+type CallInfo struct {
+	// Operation is the client method being called, e.g. "ProcessRequest".
+	Operation string
+	Method    string
+	Path      string
+	// Request is the processing request being sent, or nil for calls that
+	// don't carry one.
+	Request *ProcessingRequest
+	// RequestID is the X-Request-ID sent with every attempt of the call.
+	RequestID string
+	// CorrelationID is the caller's correlation ID, if any.
+	CorrelationID string
+	// Tenant is the tenant the call is made for, if any.
+	Tenant string
+}
+
+// CallResult describes how a logical API call ended.
+type CallResult struct {
+	// Attempts is the number of HTTP attempts made, including retries.
+	Attempts int
+	// StatusCode is the status of the last response, or zero if none was
+	// received.
+	StatusCode int
+	Err        error
+}
+
+// Tracer instruments logical API calls. StartCall is invoked before the
+// first attempt; the returned context is used for every attempt, so
+// middleware can read span context from the request, and finish is called
+// once the call completes. See the strictotel package for an OpenTelemetry
+// implementation.
+type Tracer interface {
+	StartCall(ctx context.Context, info *CallInfo) (_ context.Context, finish func(*CallResult))
+}
@@ -0,0 +1,52 @@
+package strict
+
+import "net/http"
+
+// TransportWrapper decorates an http.RoundTripper, for example to add a
+// corporate auth header or to record requests at the transport layer.
+type TransportWrapper func(http.RoundTripper) http.RoundTripper
+
+// newDefaultTransport returns the transport the client owns unless one is
+// supplied. Transport-level options configure it in place.
+func newDefaultTransport() *http.Transport {
+	return http.DefaultTransport.(*http.Transport).Clone()
+}
+
+// buildHTTPClient assembles the *http.Client from the transport-related
+// options once all options have been applied.
+func (c *Client) buildHTTPClient() *http.Client {
+	if c.httpClient != nil && c.transport == nil && len(c.transportWrappers) == 0 {
+		return c.httpClient
+	}
+
+	var rt http.RoundTripper
+	switch {
+	case c.transport != nil:
+		rt = c.transport
+	case c.httpClient != nil && c.httpClient.Transport != nil:
+		rt = c.httpClient.Transport
+	case c.httpClient != nil:
+		rt = http.DefaultTransport
+	default:
+		rt = c.defaultRoundTripper()
+	}
+	for _, wrap := range c.transportWrappers {
+		rt = wrap(rt)
+	}
+
+	hc := &http.Client{}
+	if c.httpClient != nil {
+		// Copy rather than mutate a client the caller may share.
+		*hc = *c.httpClient
+	}
+	hc.Transport = rt
+	return hc
+}
+
+// HTTPClient returns the *http.Client the client sends requests with,
+// including its proxy, TLS and connection settings. It is meant for
+// extensions that open connections outside the request pipeline, such as
+// WebSockets; requests sent with it bypass retries, hooks and the rest.
+func (c *Client) HTTPClient() *http.Client {
+	return c.httpClient
+}
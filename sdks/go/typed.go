@@ -0,0 +1,28 @@
+package strict
+
+import "context"
+
+// TypedOutput is an OutputSchema whose Result is decoded into T. Its
+// Result field shadows the untyped OutputSchema.Result.
+type TypedOutput[T any] struct {
+	OutputSchema
+	Result T
+}
+
+// ProcessRequestAs calls c.ProcessRequest and decodes the result into T
+// as DecodeResult does. c is usually a *Client.
+//
+//	out, err := strict.ProcessRequestAs[Summary](ctx, client, req)
+//	...
+//	fmt.Println(out.Result.Title)
+func ProcessRequestAs[T any](ctx context.Context, c API, req ProcessingRequest, opts ...CallOption) (*TypedOutput[T], error) {
+	out, err := c.ProcessRequest(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	typed := &TypedOutput[T]{OutputSchema: *out}
+	if err := out.DecodeResult(&typed.Result); err != nil {
+		return nil, err
+	}
+	return typed, nil
+}
@@ -0,0 +1,60 @@
+package strict
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strings"
+)
+
+const unixScheme = "unix://"
+
+// unixBaseURL is the placeholder origin used for requests sent over a Unix
+// socket; the host is ignored by the socket dialer.
+const unixBaseURL = "http://localhost"
+
+// WithUnixSocket sends every request over the Unix domain socket at path,
+// for sidecar deployments where the strict service listens locally.
+// Passing a BaseURL of the form unix:///var/run/strict.sock has the same
+// effect. With the option, the path of an http(s) BaseURL is kept and
+// only its origin is replaced. Only the SDK's default transport can dial
+// sockets.
+func WithUnixSocket(path string) Option {
+	return func(c *Client) {
+		c.unixSocket = path
+	}
+}
+
+// configureUnixSocket switches the default transport to dial the socket if
+// one was requested via option or BaseURL.
+func (c *Client) configureUnixSocket() {
+	if c.unixSocket == "" && strings.HasPrefix(c.BaseURL, unixScheme) {
+		c.unixSocket = strings.TrimPrefix(c.BaseURL, unixScheme)
+	}
+	if c.unixSocket == "" {
+		return
+	}
+	path := c.unixSocket
+	var d net.Dialer
+	c.httpTransport.Proxy = nil
+	c.httpTransport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		return d.DialContext(ctx, "unix", path)
+	}
+}
+
+// baseURL returns the base URL requests are sent to. Over a Unix socket
+// that is the placeholder origin followed by BaseURL's path, so a gateway
+// prefix such as /strict/v1 still applies.
+func (c *Client) baseURL() string {
+	if c.unixSocket == "" {
+		return c.BaseURL
+	}
+	if strings.HasPrefix(c.BaseURL, unixScheme) {
+		return unixBaseURL
+	}
+	u, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return unixBaseURL
+	}
+	return unixBaseURL + strings.TrimSuffix(u.EscapedPath(), "/")
+}
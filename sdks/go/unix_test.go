@@ -0,0 +1,24 @@
+package strict
+
+import "testing"
+
+func TestUnixSocketBaseURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		opts    []Option
+		want    string
+	}{
+		{"no socket", "https://gw.example/strict", nil, "https://gw.example/strict"},
+		{"socket URL", "unix:///var/run/strict.sock", nil, "http://localhost"},
+		{"option", "https://gw.example", []Option{WithUnixSocket("/tmp/s.sock")}, "http://localhost"},
+		{"option keeps path", "https://gw.example/strict/v1/", []Option{WithUnixSocket("/tmp/s.sock")}, "http://localhost/strict/v1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewClient(tt.baseURL, "k", tt.opts...).baseURL(); got != tt.want {
+				t.Errorf("baseURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
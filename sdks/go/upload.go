@@ -0,0 +1,243 @@
+package strict
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	defaultUploadThreshold = 64 << 20
+	defaultUploadChunkSize = 8 << 20
+)
+
+// UploadConfig controls how ProcessUpload sends large inputs.
+type UploadConfig struct {
+	// Threshold is the input size above which ProcessUpload uploads in
+	// chunks instead of a single request. Zero means 64 MiB.
+	Threshold int64
+	// ChunkSize is the size of each uploaded chunk. Zero means 8 MiB.
+	ChunkSize int64
+	// Store, if set, persists progress for calls made with WithUploadKey
+	// so they can resume where a previous process left off.
+	Store UploadStore
+}
+
+// WithChunkedUpload configures chunked uploads for ProcessUpload.
+func WithChunkedUpload(cfg UploadConfig) Option {
+	return func(c *Client) {
+		if cfg.Threshold < 0 || cfg.ChunkSize < 0 {
+			c.setConfigErr(errors.New("strict: WithChunkedUpload: negative size"))
+			return
+		}
+		c.upload = cfg
+	}
+}
+
+func (cfg UploadConfig) withDefaults() UploadConfig {
+	if cfg.Threshold == 0 {
+		cfg.Threshold = defaultUploadThreshold
+	}
+	if cfg.ChunkSize == 0 {
+		cfg.ChunkSize = defaultUploadChunkSize
+	}
+	return cfg
+}
+
+// uploadSession is the server's view of an upload.
+type uploadSession struct {
+	ID     string `json:"upload_id,omitempty"`
+	Offset int64  `json:"offset"`
+}
+
+type uploadComplete struct {
+	InputTokens   int           `json:"input_tokens"`
+	InputHash     string        `json:"input_hash"`
+	ProcessorType ProcessorType `json:"processor_type,omitempty"`
+	DryRun        bool          `json:"dry_run,omitempty"`
+}
+
+// ProcessUpload processes size bytes of input read from r. Inputs up to
+// the upload threshold are streamed in a single request, as with
+// ProcessStream. Larger inputs are uploaded in chunks:
+//
+//	POST /uploads                {"size": n}          → {"upload_id": id, "offset": 0}
+//	PUT  /uploads/{id}           chunk, Upload-Offset → {"offset": n}
+//	POST /uploads/{id}/complete  hash and token count → OutputSchema
+//
+// Each chunk is a separate call, retried under the client's retry
+// policy, so only one chunk is held in memory at a time. WithCallTimeout
+// bounds the whole upload.
+//
+// With an UploadConfig.Store and WithUploadKey, the session is recorded
+// after every chunk. A later call with the same key and size asks the
+// server, via GET /uploads/{id}, how much it holds and sends only the
+// rest. Sessions the server no longer knows are started afresh.
+//
+// The request is prepared as ProcessStream prepares one. With
+// WithRequestValidation the input's length and token count are checked
+// after the last chunk, before the upload is completed.
+func (c *Client) ProcessUpload(ctx context.Context, r io.ReaderAt, size int64, opts ...CallOption) (*StreamOutput, error) {
+	if c.envelope != nil {
+		return nil, ErrEncryptionUnsupported
+	}
+	cfg := c.upload.withDefaults()
+	if size <= cfg.Threshold {
+		return c.ProcessStream(ctx, io.NewSectionReader(r, 0, size), opts...)
+	}
+
+	co := newCallOptions(opts)
+	if co.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, co.timeout)
+		defer cancel()
+	}
+	var req ProcessingRequest
+	if err := c.prepareProcessor(ctx, &req, co); err != nil {
+		return nil, err
+	}
+	if err := c.checkStreamed(req, 1); err != nil {
+		return nil, err
+	}
+
+	sess, ok, err := c.resumeUpload(ctx, cfg, size, co)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		cl, err := newJSONCall("InitiateUpload", http.MethodPost, "/uploads", map[string]int64{"size": size}, &sess, co)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.do(ctx, cl); err != nil {
+			return nil, err
+		}
+		if sess.ID == "" {
+			return nil, errors.New("strict: server did not return an upload ID")
+		}
+		c.saveUpload(cfg, co, size, sess)
+	}
+	out, err := c.uploadChunks(ctx, r, size, req, sess, cfg, co)
+	if err == nil && cfg.Store != nil && co.uploadKey != "" {
+		cfg.Store.Delete(co.uploadKey)
+	}
+	return out, err
+}
+
+// resumeUpload looks up a stored session for the call's upload key and
+// asks the server how much of it has arrived.
+func (c *Client) resumeUpload(ctx context.Context, cfg UploadConfig, size int64, co callOptions) (uploadSession, bool, error) {
+	if cfg.Store == nil || co.uploadKey == "" {
+		return uploadSession{}, false, nil
+	}
+	state, ok := cfg.Store.Load(co.uploadKey)
+	if !ok || state.Size != size || state.UploadID == "" {
+		return uploadSession{}, false, nil
+	}
+	var sess uploadSession
+	cl, err := newJSONCall("GetUpload", http.MethodGet, "/uploads/"+url.PathEscape(state.UploadID), nil, &sess, co)
+	if err != nil {
+		return uploadSession{}, false, err
+	}
+	if err := c.do(ctx, cl); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			cfg.Store.Delete(co.uploadKey)
+			return uploadSession{}, false, nil
+		}
+		return uploadSession{}, false, err
+	}
+	sess.ID = state.UploadID
+	if sess.Offset < 0 || sess.Offset > size {
+		return uploadSession{}, false, fmt.Errorf("strict: upload %s: server reports offset %d of %d", sess.ID, sess.Offset, size)
+	}
+	return sess, true, nil
+}
+
+func (c *Client) saveUpload(cfg UploadConfig, co callOptions, size int64, sess uploadSession) {
+	if cfg.Store != nil && co.uploadKey != "" {
+		cfg.Store.Save(co.uploadKey, UploadState{UploadID: sess.ID, Size: size, Offset: sess.Offset})
+	}
+}
+
+// uploadChunks sends r to the upload session and completes it as req.
+func (c *Client) uploadChunks(ctx context.Context, r io.ReaderAt, size int64, req ProcessingRequest, sess uploadSession, cfg UploadConfig, co callOptions) (*StreamOutput, error) {
+	path := "/uploads/" + url.PathEscape(sess.ID)
+	digest := newInputDigest()
+	tokens := tokenCounter{t: c.tokenizerFor(req.ProcessorType)}
+	buf := make([]byte, cfg.ChunkSize)
+	for off := int64(0); off < size; {
+		end := min(off+cfg.ChunkSize, size)
+		if off < sess.Offset {
+			end = min(end, sess.Offset)
+		}
+		chunk := buf[:end-off]
+		if n, err := r.ReadAt(chunk, off); n < len(chunk) {
+			return nil, fmt.Errorf("strict: read input at offset %d: %w", off, err)
+		}
+		digest.write(chunk)
+		tokens.write(chunk)
+		if off < sess.Offset {
+			// The server already has this part; it is read only to
+			// compute the hash and token count.
+			off = end
+			continue
+		}
+
+		chunkOpts := co
+		chunkOpts.headers = co.headers.Clone()
+		chunkOpts.headers.Set("Upload-Offset", strconv.FormatInt(off, 10))
+		var ack uploadSession
+		cl := &call{
+			op:          "UploadChunk",
+			method:      http.MethodPut,
+			path:        path,
+			body:        chunk,
+			contentType: "application/octet-stream",
+			out:         &ack,
+			opts:        chunkOpts,
+		}
+		if err := c.do(ctx, cl); err != nil {
+			return nil, err
+		}
+		off = end
+		if ack.Offset != off {
+			return nil, fmt.Errorf("strict: upload %s: server at offset %d, expected %d", sess.ID, ack.Offset, off)
+		}
+		sess.Offset = off
+		c.saveUpload(cfg, co, size, sess)
+	}
+	tokens.flush()
+	hash := digest.sum()
+	req.InputTokens = tokens.count()
+	if err := c.checkStreamed(req, digest.runes); err != nil {
+		return nil, err
+	}
+
+	var output StreamOutput
+	done := uploadComplete{
+		InputTokens:   req.InputTokens,
+		InputHash:     hash,
+		ProcessorType: req.ProcessorType,
+		DryRun:        req.DryRun,
+	}
+	cl, err := newJSONCall("CompleteUpload", http.MethodPost, path+"/complete", &done, &output.OutputSchema, co)
+	if err != nil {
+		return nil, err
+	}
+	cl.request = &req
+	if err := c.do(ctx, cl); err != nil {
+		return nil, err
+	}
+	output.RequestID = cl.serverRequestID
+	output.InputHash = done.InputHash
+	output.InputTokens = done.InputTokens
+	if err := c.verifyInputHash(output.InputHash, &output.OutputSchema); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
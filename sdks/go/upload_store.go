@@ -0,0 +1,83 @@
+package strict
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// UploadState records the progress of a chunked upload.
+type UploadState struct {
+	UploadID string `json:"upload_id"`
+	Size     int64  `json:"size"`
+	// Offset is how many bytes the server has acknowledged.
+	Offset int64 `json:"offset"`
+}
+
+// UploadStore persists upload progress so that an upload interrupted by
+// a process restart can resume. Implementations must be safe for
+// concurrent use.
+type UploadStore interface {
+	Load(key string) (UploadState, bool)
+	Save(key string, state UploadState)
+	Delete(key string)
+}
+
+// FileUploadStore is an UploadStore that keeps one small file per upload
+// in a directory.
+type FileUploadStore struct {
+	dir string
+}
+
+// NewFileUploadStore opens or creates an upload store in dir.
+func NewFileUploadStore(dir string) (*FileUploadStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileUploadStore{dir: dir}, nil
+}
+
+// path hashes key, which is often a file path, into a safe file name.
+func (s *FileUploadStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".upload")
+}
+
+func (s *FileUploadStore) Load(key string) (UploadState, bool) {
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return UploadState{}, false
+	}
+	var state UploadState
+	if err := json.Unmarshal(data, &state); err != nil {
+		s.Delete(key)
+		return UploadState{}, false
+	}
+	return state, true
+}
+
+func (s *FileUploadStore) Save(key string, state UploadState) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(s.dir, "tmp-*")
+	if err != nil {
+		return
+	}
+	_, werr := tmp.Write(data)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), s.path(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+func (s *FileUploadStore) Delete(key string) {
+	os.Remove(s.path(key))
+}
@@ -0,0 +1,189 @@
+package strict
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Server-side limits on ProcessingRequest fields.
+const (
+	maxInputLength = 1_000_000
+	maxInputTokens = 1_000_000
+)
+
+// RequestLimits bounds what local validation accepts. Zero fields use the
+// server's own limits.
+type RequestLimits struct {
+	// MaxInputLength caps InputData, in characters.
+	MaxInputLength int
+	// MaxInputTokens caps InputTokens.
+	MaxInputTokens int
+	// MaxTimeout caps TimeoutSeconds. Zero means no cap beyond the
+	// value being positive and finite.
+	MaxTimeout time.Duration
+}
+
+// WithRequestValidation checks each ProcessingRequest against limits
+// before it is sent, so malformed requests fail without a round trip.
+// Failures are returned as *ValidationError.
+func WithRequestValidation(limits RequestLimits) Option {
+	return func(c *Client) {
+		if limits.MaxInputLength < 0 || limits.MaxInputTokens < 0 || limits.MaxTimeout < 0 {
+			c.setConfigErr(errors.New("strict: WithRequestValidation: negative limit"))
+			return
+		}
+		c.limits = &limits
+	}
+}
+
+// ValidationError reports a request that failed local validation. Its
+// Details have the same shape as the server's validation errors, and it
+// matches ErrValidationFailed.
+type ValidationError struct {
+	Details []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Details))
+	for i, d := range e.Details {
+		parts[i] = d.String()
+	}
+	return "strict: invalid request: " + strings.Join(parts, "; ")
+}
+
+// Is matches ErrValidationFailed.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidationFailed
+}
+
+// Validate checks req against the server's limits. It returns a
+// *ValidationError listing every invalid field, or nil.
+func (req ProcessingRequest) Validate() error {
+	return RequestLimits{}.Check(req)
+}
+
+// Check validates req against l. It returns a *ValidationError listing
+// every invalid field, or nil. An InputTokens of zero is accepted.
+func (l RequestLimits) Check(req ProcessingRequest) error {
+	n := len(req.InputData)
+	if n > l.maxInputLength() {
+		n = utf8.RuneCountInString(req.InputData)
+	}
+	return l.check(req, n)
+}
+
+func (l RequestLimits) maxInputLength() int {
+	if l.MaxInputLength == 0 {
+		return maxInputLength
+	}
+	return l.MaxInputLength
+}
+
+// check validates req as if its input were n characters long, for
+// requests whose input is streamed rather than held in InputData.
+func (l RequestLimits) check(req ProcessingRequest, n int) error {
+	maxLen := l.maxInputLength()
+	maxTokens := l.MaxInputTokens
+	if maxTokens == 0 {
+		maxTokens = maxInputTokens
+	}
+
+	var details []FieldError
+	add := func(field, typ, format string, args ...interface{}) {
+		details = append(details, FieldError{
+			Loc:     []string{"body", field},
+			Message: fmt.Sprintf(format, args...),
+			Type:    typ,
+		})
+	}
+	if n == 0 {
+		add("input_data", "string_too_short", "String should have at least 1 character")
+	} else if n > maxLen {
+		add("input_data", "string_too_long", "String should have at most %d characters", maxLen)
+	}
+	switch {
+	case req.InputTokens < 0:
+		add("input_tokens", "greater_than_equal", "Input should be greater than or equal to 0")
+	case req.InputTokens > maxTokens:
+		add("input_tokens", "less_than_equal", "Input should be less than or equal to %d", maxTokens)
+	}
+	if req.ProcessorType != "" && !req.ProcessorType.Known() {
+		add("processor_type", "enum", "Input should be %s", enumList(ProcessorTypes()))
+	}
+	switch t := req.TimeoutSeconds; {
+	case math.IsNaN(t) || math.IsInf(t, 0):
+		add("timeout_seconds", "finite_number", "Input should be a finite number")
+	case t < 0:
+		add("timeout_seconds", "greater_than", "Input should be greater than 0")
+	case l.MaxTimeout > 0 && t > l.MaxTimeout.Seconds():
+		add("timeout_seconds", "less_than_equal", "Input should be less than or equal to %g", l.MaxTimeout.Seconds())
+	}
+	if len(details) > 0 {
+		return &ValidationError{Details: details}
+	}
+	return nil
+}
+
+// enumList formats values the way the server lists allowed enum values:
+// 'a', 'b' or 'c'.
+func enumList(ps []ProcessorType) string {
+	var b strings.Builder
+	for i, p := range ps {
+		switch {
+		case i == 0:
+		case i == len(ps)-1:
+			b.WriteString(" or ")
+		default:
+			b.WriteString(", ")
+		}
+		b.WriteString("'" + string(p) + "'")
+	}
+	return b.String()
+}
+
+// prepareRequest applies the call's overrides and the client's router or
+// default processor to req, fills in InputTokens, and validates it.
+func (c *Client) prepareRequest(ctx context.Context, req *ProcessingRequest, co callOptions) error {
+	if err := c.prepareProcessor(ctx, req, co); err != nil {
+		return err
+	}
+	c.countTokens(req)
+	return c.checkRequest(*req)
+}
+
+// prepareProcessor applies the call's overrides and the client's router
+// or default processor to req, and checks that the client's region hosts
+// the processor chosen. Streamed calls, whose input isn't in req, stop
+// here and validate the input as they read it.
+func (c *Client) prepareProcessor(ctx context.Context, req *ProcessingRequest, co callOptions) error {
+	co.applyTo(req)
+	if req.ProcessorType == "" && c.router != nil {
+		c.route(ctx, req, co)
+	}
+	if req.ProcessorType == "" {
+		req.ProcessorType = c.processor
+	}
+	return c.checkRegion(req.ProcessorType)
+}
+
+// checkRequest validates req if WithRequestValidation is set.
+func (c *Client) checkRequest(req ProcessingRequest) error {
+	if c.limits == nil {
+		return nil
+	}
+	return c.limits.Check(req)
+}
+
+// checkStreamed validates a streamed request, whose input is n characters
+// long, if WithRequestValidation is set.
+func (c *Client) checkStreamed(req ProcessingRequest, n int) error {
+	if c.limits == nil {
+		return nil
+	}
+	return c.limits.check(req, n)
+}
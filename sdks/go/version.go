@@ -0,0 +1,22 @@
+package strict
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Version is the version of this SDK, sent in the User-Agent and
+// X-SDK-Version headers.
+const Version = "0.1.0"
+
+// userAgent builds "strict-go/<version> go/<goversion> <os>/<arch>",
+// followed by the caller's suffix if any.
+func userAgent(suffix string) string {
+	ua := fmt.Sprintf("strict-go/%s go/%s %s/%s",
+		Version, strings.TrimPrefix(runtime.Version(), "go"), runtime.GOOS, runtime.GOARCH)
+	if suffix != "" {
+		ua += " " + suffix
+	}
+	return ua
+}
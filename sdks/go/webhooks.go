@@ -0,0 +1,209 @@
+package strict
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// WebhookEventType names an event the server can notify webhooks of.
+type WebhookEventType string
+
+const (
+	// EventJobCompleted is sent when an asynchronous job's result is
+	// ready.
+	EventJobCompleted WebhookEventType = "job.completed"
+	// EventJobFailed is sent when an asynchronous job fails.
+	EventJobFailed WebhookEventType = "job.failed"
+	// EventValidationRejected is sent when the server rejects a request's
+	// input.
+	EventValidationRejected WebhookEventType = "validation.rejected"
+	// EventQuotaWarning is sent when usage nears the account's quota.
+	EventQuotaWarning WebhookEventType = "quota.warning"
+)
+
+// Webhook is a webhook subscription: the server POSTs the events it
+// subscribes to to its URL, signed with its secret.
+type Webhook struct {
+	ID     string             `json:"id"`
+	URL    string             `json:"url"`
+	Events []WebhookEventType `json:"events"`
+	// Secret signs deliveries. The server returns it only from
+	// CreateWebhook, and from UpdateWebhook when it sets a new one.
+	Secret      string    `json:"secret,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CreateWebhookRequest describes a webhook subscription to create.
+type CreateWebhookRequest struct {
+	// URL receives deliveries. It must be an absolute http or https URL.
+	URL    string             `json:"url"`
+	Events []WebhookEventType `json:"events"`
+	// Secret signs deliveries. Leave it empty to have the server generate
+	// one, returned in the new Webhook; store it then, as it isn't
+	// returned again.
+	Secret      string `json:"secret,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// UpdateWebhookRequest changes a webhook subscription. Nil fields are
+// left unchanged.
+type UpdateWebhookRequest struct {
+	URL         *string            `json:"url,omitempty"`
+	Events      []WebhookEventType `json:"events,omitempty"`
+	Secret      *string            `json:"secret,omitempty"`
+	Description *string            `json:"description,omitempty"`
+	// Active pauses deliveries when false, and resumes them when true.
+	Active *bool `json:"active,omitempty"`
+}
+
+// checkWebhookURL rejects URLs the server would never deliver to.
+func checkWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("strict: webhook URL: %w", err)
+	}
+	if (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("strict: webhook URL %q is not an absolute http or https URL", raw)
+	}
+	return nil
+}
+
+// CreateWebhook subscribes a URL to events with POST /webhooks.
+func (c *Client) CreateWebhook(ctx context.Context, req CreateWebhookRequest, opts ...CallOption) (*Webhook, error) {
+	if err := checkWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	if len(req.Events) == 0 {
+		return nil, errors.New("strict: webhook has no events")
+	}
+	var wh Webhook
+	cl, err := newJSONCall("CreateWebhook", http.MethodPost, "/webhooks", req, &wh, newCallOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.invoke(ctx, cl); err != nil {
+		return nil, err
+	}
+	return &wh, nil
+}
+
+type webhookList struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+// ListWebhooks lists the account's webhook subscriptions with GET
+// /webhooks. Secrets are not included.
+func (c *Client) ListWebhooks(ctx context.Context, opts ...CallOption) ([]Webhook, error) {
+	var list webhookList
+	cl, err := newJSONCall("ListWebhooks", http.MethodGet, "/webhooks", nil, &list, newCallOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.invoke(ctx, cl); err != nil {
+		return nil, err
+	}
+	return list.Webhooks, nil
+}
+
+// GetWebhook fetches a webhook subscription with GET /webhooks/{id}.
+func (c *Client) GetWebhook(ctx context.Context, id string, opts ...CallOption) (*Webhook, error) {
+	var wh Webhook
+	cl, err := newJSONCall("GetWebhook", http.MethodGet, webhookPath(id), nil, &wh, newCallOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.invoke(ctx, cl); err != nil {
+		return nil, err
+	}
+	return &wh, nil
+}
+
+// UpdateWebhook changes a webhook subscription with PATCH
+// /webhooks/{id}, returning it as updated.
+func (c *Client) UpdateWebhook(ctx context.Context, id string, req UpdateWebhookRequest, opts ...CallOption) (*Webhook, error) {
+	if req.URL != nil {
+		if err := checkWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+	}
+	var wh Webhook
+	cl, err := newJSONCall("UpdateWebhook", http.MethodPatch, webhookPath(id), req, &wh, newCallOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.invoke(ctx, cl); err != nil {
+		return nil, err
+	}
+	return &wh, nil
+}
+
+// DeleteWebhook removes a webhook subscription with DELETE
+// /webhooks/{id}. Deliveries already in flight may still arrive.
+func (c *Client) DeleteWebhook(ctx context.Context, id string, opts ...CallOption) error {
+	cl, err := newJSONCall("DeleteWebhook", http.MethodDelete, webhookPath(id), nil, nil, newCallOptions(opts))
+	if err != nil {
+		return err
+	}
+	return c.invoke(ctx, cl)
+}
+
+// ReplayFilter narrows the events ReplayEvents re-delivers. Zero fields
+// match every event.
+type ReplayFilter struct {
+	// WebhookID limits the replay to one subscription's deliveries.
+	WebhookID string `json:"webhook_id,omitempty"`
+	// Events limits the replay to events of these types.
+	Events []WebhookEventType `json:"events,omitempty"`
+}
+
+type replayRequest struct {
+	From      time.Time          `json:"from"`
+	To        time.Time          `json:"to"`
+	WebhookID string             `json:"webhook_id,omitempty"`
+	Events    []WebhookEventType `json:"events,omitempty"`
+}
+
+// Replay describes a replay the server has scheduled.
+type Replay struct {
+	ID string `json:"replay_id"`
+	// Events is how many events will be re-delivered.
+	Events int `json:"events"`
+}
+
+// ReplayEvents asks the server, with POST /events/replay, to re-deliver
+// the events created from from up to to that match filter, for instance
+// those missed while a receiver was down. A zero to means now. The server
+// delivers them asynchronously, in order, to their subscriptions as they
+// are now; they keep their IDs and are signed afresh. Events the receiver
+// did handle may arrive again, so handlers should be idempotent.
+func (c *Client) ReplayEvents(ctx context.Context, from, to time.Time, filter ReplayFilter, opts ...CallOption) (*Replay, error) {
+	if from.IsZero() {
+		return nil, errors.New("strict: ReplayEvents: zero from time")
+	}
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("strict: ReplayEvents: to %s is before from %s", to.Format(time.RFC3339), from.Format(time.RFC3339))
+	}
+	var r Replay
+	cl, err := newJSONCall("ReplayEvents", http.MethodPost, "/events/replay", replayRequest{From: from, To: to, WebhookID: filter.WebhookID, Events: filter.Events}, &r, newCallOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.invoke(ctx, cl); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func webhookPath(id string) string {
+	return "/webhooks/" + url.PathEscape(id)
+}
@@ -0,0 +1,204 @@
+package strict
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrPoolClosed is returned by Pool.Submit after Close.
+var ErrPoolClosed = errors.New("strict: pool is closed")
+
+// Pool processes requests on a bounded number of goroutines, for callers
+// that would otherwise fan out around ProcessRequest themselves. Submit
+// requests from one goroutine and read Results from another:
+//
+//	p := &strict.Pool{Client: c, Concurrency: 32, Retry: strict.DefaultRetryPolicy()}
+//	go func() {
+//		defer p.Close()
+//		for _, req := range reqs {
+//			if _, err := p.Submit(ctx, req); err != nil {
+//				return
+//			}
+//		}
+//	}()
+//	for res := range p.Results() {
+//		...
+//	}
+//
+// Submit blocks once twice Concurrency requests are waiting to be
+// processed or delivered, so Results must be drained as requests are
+// submitted. The fields must not change after the first Submit.
+type Pool struct {
+	Client *Client
+	// Concurrency bounds the requests in flight. The default is 8.
+	Concurrency int
+	// Retry retries requests that fail with a retryable error, on top of
+	// the client's own retry policy. The zero value makes one attempt.
+	Retry RetryPolicy
+	// Ordered delivers results in submission order. Otherwise each result
+	// is delivered as soon as it is ready.
+	Ordered bool
+	// CallOptions apply to every request.
+	CallOptions []CallOption
+
+	once    sync.Once
+	mu      sync.Mutex
+	closed  bool
+	next    int
+	jobs    chan poolJob
+	slots   chan struct{}
+	results chan PoolResult
+}
+
+// PoolResult is the outcome of one submitted request: Output on success,
+// Err otherwise.
+type PoolResult struct {
+	// Index is the request's position in submission order, from 0.
+	Index    int
+	Request  ProcessingRequest
+	Output   *OutputSchema
+	Err      error
+	Attempts int
+}
+
+type poolJob struct {
+	ctx   context.Context
+	index int
+	req   ProcessingRequest
+}
+
+func (p *Pool) start() {
+	n := p.Concurrency
+	if n <= 0 {
+		n = defaultBatchConcurrency
+	}
+	// A slot is held from Submit until the result is delivered, which
+	// bounds both queued jobs and, when ordered, results held back
+	// behind a slow one.
+	p.slots = make(chan struct{}, 2*n)
+	p.jobs = make(chan poolJob, 2*n)
+	p.results = make(chan PoolResult, n)
+
+	out := p.results
+	if p.Ordered {
+		out = make(chan PoolResult, n)
+		go p.reorder(out)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(out)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+}
+
+// Submit queues req for processing under ctx, which also bounds the wait
+// for room in the queue, and returns its index. It fails if the pool is
+// closed or ctx is done first.
+func (p *Pool) Submit(ctx context.Context, req ProcessingRequest) (int, error) {
+	if p.Client == nil {
+		return -1, errors.New("strict: Pool has no Client")
+	}
+	p.once.Do(p.start)
+	p.mu.Lock()
+	closed := p.closed
+	p.mu.Unlock()
+	if closed {
+		return -1, ErrPoolClosed
+	}
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return -1, ctx.Err()
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		<-p.slots
+		return -1, ErrPoolClosed
+	}
+	i := p.next
+	p.next++
+	// jobs has a buffer slot for every held slot, so this never blocks.
+	p.jobs <- poolJob{ctx: ctx, index: i, req: req}
+	return i, nil
+}
+
+// Results returns the channel results are delivered on. It is closed
+// after Close once every submitted request has been delivered.
+func (p *Pool) Results() <-chan PoolResult {
+	p.once.Do(p.start)
+	return p.results
+}
+
+// Close stops the pool accepting requests. Those already submitted are
+// still processed and delivered.
+func (p *Pool) Close() {
+	p.once.Do(p.start)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.closed {
+		p.closed = true
+		close(p.jobs)
+	}
+}
+
+func (p *Pool) work(out chan<- PoolResult) {
+	for job := range p.jobs {
+		res := PoolResult{Index: job.index, Request: job.req}
+		if err := job.ctx.Err(); err != nil {
+			res.Err = err
+		} else {
+			res.Output, res.Attempts, res.Err = processRetrying(job.ctx, p.Client, job.req, p.Retry, p.CallOptions)
+		}
+		out <- res
+		if !p.Ordered {
+			<-p.slots
+		}
+	}
+}
+
+// reorder delivers results from in in index order.
+func (p *Pool) reorder(in <-chan PoolResult) {
+	pending := make(map[int]PoolResult)
+	next := 0
+	for res := range in {
+		pending[res.Index] = res
+		for {
+			r, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			p.results <- r
+			<-p.slots
+			next++
+		}
+	}
+	close(p.results)
+}
+
+// processRetrying processes req with c, retrying retryable failures
+// under retry, and returns the number of attempts made. A request that
+// fails for good is dead-lettered once, with every attempt's error.
+func processRetrying(ctx context.Context, c *Client, req ProcessingRequest, retry RetryPolicy, opts []CallOption) (*OutputSchema, int, error) {
+	log := new(attemptLog)
+	opts = append(opts[:len(opts):len(opts)], withAttemptLog(log))
+	for attempt := 1; ; attempt++ {
+		out, err := c.ProcessRequest(ctx, req, opts...)
+		if err == nil {
+			return out, attempt, nil
+		}
+		if attempt >= retry.attempts() || !IsRetryable(err) || sleepContext(ctx, retry.backoff(attempt)) != nil {
+			c.deadLetter(ctx, req, log.history(err))
+			return nil, attempt, err
+		}
+	}
+}